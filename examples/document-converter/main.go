@@ -58,9 +58,15 @@ Convert Flags:
   -input <path>        PDF path (default: vim-cheatsheet.pdf)
   -output <dir>        Output directory (default: output/)
   -page <spec>         Page selection (default: all pages)
-  -format <fmt>        Output format: png or jpg (default: png)
+  -format <fmt>        Output format: png, jpg, webp, or avif (default: png)
   -dpi <int>           Rendering DPI (default: 300)
-  -quality <int>       JPEG quality 1-100 (default: 90)
+  -max-width <int>     Fit output within a width in pixels (0=not set)
+  -max-height <int>    Fit output within a height in pixels (0=not set)
+  -max-long-edge <int> Fit the longer side within pixels, e.g. 1568 (0=not set)
+  -max-mp <float>      Fit output within megapixels (0=not set)
+  -max-bytes <int>     Lower quality, then DPI, until output fits bytes (0=not set)
+  -quality <int>       JPEG/WebP/AVIF quality 1-100 (default: 90)
+  -lossless            Encode WebP losslessly
   -cache-dir <path>    Cache directory (default: /tmp/document-context-cache)
   -no-cache            Disable caching
   -base64              Include base64 data URI files
@@ -69,6 +75,10 @@ Convert Flags:
   -saturation <int>    Saturation 0-200 (100=neutral)
   -rotation <int>      Rotation 0-360 degrees
   -background <color>  Background color (default: white)
+  -color-mode <mode>   Color mode: rgb, grayscale, bilevel, or palette (default: rgb)
+  -threshold <int>     Bilevel threshold 0-100 percent (0=not set)
+  -colors <int>        Palette size 2-256 (0=not set)
+  -dither              Dither bilevel or palette output
 
 Page Selection Syntax:
   3          Single page (page 3)
@@ -92,9 +102,16 @@ func runConvert(args []string) error {
 	input := fs.String("input", "vim-cheatsheet.pdf", "PDF path")
 	output := fs.String("output", "output", "Output directory")
 	pageSpec := fs.String("page", "", "Page selection")
-	format := fs.String("format", "png", "Output format (png or jpg)")
+	rendererName := fs.String("renderer", image.DefaultRenderer, "Renderer ("+strings.Join(image.ListRenderers(), ", ")+")")
+	format := fs.String("format", "png", "Output format (png, jpg, webp, or avif)")
 	dpi := fs.Int("dpi", 300, "Rendering DPI")
-	quality := fs.Int("quality", 90, "JPEG quality")
+	maxWidth := fs.Int("max-width", 0, "Fit output within a width in pixels (0=not set)")
+	maxHeight := fs.Int("max-height", 0, "Fit output within a height in pixels (0=not set)")
+	maxLongEdge := fs.Int("max-long-edge", 0, "Fit the longer side within pixels (0=not set)")
+	maxMegapixels := fs.Float64("max-mp", 0, "Fit output within megapixels (0=not set)")
+	maxBytes := fs.Int("max-bytes", 0, "Lower quality, then DPI, until output fits bytes (0=not set)")
+	quality := fs.Int("quality", 90, "JPEG/WebP/AVIF quality")
+	lossless := fs.Bool("lossless", false, "Encode WebP losslessly")
 	cacheDir := fs.String("cache-dir", "/tmp/document-context-cache", "Cache directory")
 	noCache := fs.Bool("no-cache", false, "Disable caching")
 	includeBase64 := fs.Bool("base64", false, "Include base64 data URI files")
@@ -103,7 +120,11 @@ func runConvert(args []string) error {
 	contrast := fs.Int("contrast", 0, "Contrast -100 to +100 (0=not set)")
 	saturation := fs.Int("saturation", 0, "Saturation 0-200 (0=not set)")
 	rotation := fs.Int("rotation", 0, "Rotation 0-360 degrees (0=not set)")
-	background := fs.String("background", "white", "Background color")
+	background := fs.String("background", "", "Background color (empty=renderer default)")
+	colorMode := fs.String("color-mode", "", "Color mode: rgb, grayscale, bilevel, or palette (empty=rgb)")
+	threshold := fs.Int("threshold", 0, "Bilevel threshold 0-100 percent (0=not set)")
+	colors := fs.Int("colors", 0, "Palette size 2-256 (0=not set)")
+	dither := fs.Bool("dither", false, "Dither bilevel or palette output")
 
 	if err := fs.Parse(args); err != nil {
 		return err
@@ -121,10 +142,18 @@ func runConvert(args []string) error {
 	}
 
 	cfg := config.ImageConfig{
-		Format:  *format,
-		DPI:     *dpi,
-		Quality: *quality,
-		Options: make(map[string]any),
+		Renderer: *rendererName,
+		Format:   *format,
+		DPI:      *dpi,
+		Quality:  *quality,
+		Lossless: *lossless,
+		Options:  make(map[string]any),
+
+		MaxWidth:      *maxWidth,
+		MaxHeight:     *maxHeight,
+		MaxLongEdge:   *maxLongEdge,
+		MaxMegapixels: *maxMegapixels,
+		MaxBytes:      *maxBytes,
 	}
 
 	if *brightness != 0 {
@@ -139,9 +168,23 @@ func runConvert(args []string) error {
 	if *rotation != 0 {
 		cfg.Options["rotation"] = *rotation
 	}
-	cfg.Options["background"] = *background
+	if *background != "" {
+		cfg.Options["background"] = *background
+	}
+	if *colorMode != "" {
+		cfg.Options["color_mode"] = *colorMode
+	}
+	if *threshold != 0 {
+		cfg.Options["threshold"] = *threshold
+	}
+	if *colors != 0 {
+		cfg.Options["colors"] = *colors
+	}
+	if *dither {
+		cfg.Options["dither"] = true
+	}
 
-	renderer, err := image.NewImageMagickRenderer(cfg)
+	renderer, err := image.Create(cfg)
 	if err != nil {
 		return fmt.Errorf("failed to create renderer: %w", err)
 	}
@@ -181,29 +224,28 @@ func runConvert(args []string) error {
 			return fmt.Errorf("failed to extract page %d: %w", pageNum, err)
 		}
 
-		imageData, err := page.ToImage(renderer, c)
-		if err != nil {
-			return fmt.Errorf("failed to convert page %d: %w", pageNum, err)
-		}
-
 		baseName := strings.TrimSuffix(filepath.Base(*input), filepath.Ext(*input))
 		imagePath := filepath.Join(*output, fmt.Sprintf("%s-page-%d.%s", baseName, pageNum, *format))
 
-		if err := os.WriteFile(imagePath, imageData, 0644); err != nil {
-			return fmt.Errorf("failed to write image file: %w", err)
+		// Stream straight into the output file unless the image is needed in
+		// memory for the data URI or the byte budget report.
+		var result *document.RenderResult
+		if *includeBase64 || *maxBytes > 0 {
+			result, err = page.(document.RenderPage).Render(renderer, c)
+			if err != nil {
+				return fmt.Errorf("failed to convert page %d: %w", pageNum, err)
+			}
+			if err := os.WriteFile(imagePath, result.Data, 0644); err != nil {
+				return fmt.Errorf("failed to write image file: %w", err)
+			}
+		} else if err := writeImageFile(imagePath, page.(document.StreamPage), renderer, c); err != nil {
+			return fmt.Errorf("failed to convert page %d: %w", pageNum, err)
 		}
 
 		outputFiles = append(outputFiles, imagePath)
 
 		if *includeBase64 {
-			var imgFormat document.ImageFormat
-			if *format == "png" {
-				imgFormat = document.PNG
-			} else {
-				imgFormat = document.JPEG
-			}
-
-			dataURI, err := encoding.EncodeImageDataURI(imageData, imgFormat)
+			dataURI, err := encoding.EncodeImageDataURI(result.Data, result.Format)
 			if err != nil {
 				return fmt.Errorf("failed to encode data URI: %w", err)
 			}
@@ -217,7 +259,19 @@ func runConvert(args []string) error {
 		}
 
 		elapsed := time.Since(pageStart)
-		fmt.Printf("Converting page %d... done (%dms)\n", pageNum, elapsed.Milliseconds())
+		if result != nil {
+			source := "rendered"
+			if result.CacheHit {
+				source = "cached"
+			}
+			detail := fmt.Sprintf("%s, %dx%d", source, result.Width, result.Height)
+			if *maxBytes > 0 {
+				detail += fmt.Sprintf(", quality %d at %g DPI", result.Encoding.Quality, result.Encoding.DPI)
+			}
+			fmt.Printf("Converting page %d... done (%dms, %s)\n", pageNum, elapsed.Milliseconds(), detail)
+		} else {
+			fmt.Printf("Converting page %d... done (%dms)\n", pageNum, elapsed.Milliseconds())
+		}
 	}
 
 	totalElapsed := time.Since(start)
@@ -240,6 +294,20 @@ func runConvert(args []string) error {
 	return nil
 }
 
+// writeImageFile streams the page image into a new file at path.
+func writeImageFile(path string, page document.StreamPage, renderer image.Renderer, c cache.Cache) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create image file: %w", err)
+	}
+
+	err = page.WriteImage(f, renderer, c)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
 func runCache(args []string) error {
 	if len(args) < 1 {
 		return fmt.Errorf("cache command requires subcommand: clear, inspect, or stats")
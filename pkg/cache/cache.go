@@ -13,7 +13,7 @@ import (
 // type handling and meaningful filename suggestions when retrieving cached
 // images.
 type CacheEntry struct {
-	// Key is the unique cache key generated from document path, page number,
+	// Key is the unique cache key generated from document fingerprint, page number,
 	// and rendering settings. This is a SHA256 hash in hexadecimal format.
 	Key string
 
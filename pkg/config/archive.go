@@ -0,0 +1,76 @@
+package config
+
+// ArchiveConfig defines limits for opening archive (ZIP) documents.
+//
+// The limits protect against decompression bombs. They apply to the archive
+// and every document nested within it together (entry archives, and the
+// attachments of entry messages), so a tree of nested documents shares one
+// entry count and size budget.
+//
+// This configuration follows the Configuration Transformation Pattern (Type 1).
+// It is consumed by archive and message open functions (e.g.,
+// document.OpenZIPWithConfig, document.OpenEMLWithConfig). Messages retain
+// the limits until their attachments are opened.
+//
+// Validation of field values is performed by the consuming package.
+type ArchiveConfig struct {
+	// MaxEntries is the maximum number of file entries. Defaults to 1000.
+	MaxEntries int `json:"max_entries,omitempty"`
+
+	// MaxTotalSize is the maximum total uncompressed size of all entries, in
+	// bytes. Defaults to 1 GiB.
+	MaxTotalSize int64 `json:"max_total_size,omitempty"`
+
+	// MaxDepth is the maximum archive nesting depth, counting the outermost
+	// archive as 1. A value of 1 rejects archives that contain archives.
+	// Defaults to 3.
+	MaxDepth int `json:"max_depth,omitempty"`
+}
+
+// DefaultArchiveConfig returns an ArchiveConfig with recommended default
+// values.
+//
+// Defaults:
+//   - MaxEntries: 1000
+//   - MaxTotalSize: 1 GiB
+//   - MaxDepth: 3
+func DefaultArchiveConfig() ArchiveConfig {
+	return ArchiveConfig{
+		MaxEntries:   1000,
+		MaxTotalSize: 1 << 30,
+		MaxDepth:     3,
+	}
+}
+
+// Merge overlays non-zero values from source onto the receiver.
+//
+// Merge semantics:
+//   - MaxEntries, MaxTotalSize, MaxDepth: only merge if source is greater
+//     than zero
+func (c *ArchiveConfig) Merge(source *ArchiveConfig) {
+	if source == nil {
+		return
+	}
+
+	if source.MaxEntries > 0 {
+		c.MaxEntries = source.MaxEntries
+	}
+
+	if source.MaxTotalSize > 0 {
+		c.MaxTotalSize = source.MaxTotalSize
+	}
+
+	if source.MaxDepth > 0 {
+		c.MaxDepth = source.MaxDepth
+	}
+}
+
+// Finalize applies default values for any unset fields.
+//
+// This method merges the receiver's values onto a fresh default configuration,
+// ensuring all fields have valid values. It modifies the receiver in place.
+func (c *ArchiveConfig) Finalize() {
+	defaults := DefaultArchiveConfig()
+	defaults.Merge(c)
+	*c = defaults
+}
@@ -0,0 +1,105 @@
+package config
+
+// FingerprintMode selects how a document content fingerprint is derived.
+//
+// The fingerprint identifies document content independent of its location on
+// disk and is the basis for rendered image cache keys.
+type FingerprintMode string
+
+const (
+	// FingerprintContent hashes the complete document bytes with SHA-256.
+	// This is the most reliable mode: any change to the file produces a new
+	// fingerprint, and identical files at different paths share a fingerprint.
+	FingerprintContent FingerprintMode = "content"
+
+	// FingerprintFast derives the fingerprint from document metadata (the PDF
+	// trailer ID array) combined with file size and modification time. This
+	// avoids reading the entire file but may miss edits that preserve size and
+	// modification time.
+	FingerprintFast FingerprintMode = "fast"
+)
+
+// ValidationMode selects how strictly a document is validated when opened.
+type ValidationMode string
+
+const (
+	// ValidationStrict requires full compliance with the PDF specification.
+	// Many real-world documents that viewers open without complaint fail
+	// strict validation.
+	ValidationStrict ValidationMode = "strict"
+
+	// ValidationRelaxed tolerates frequently encountered specification
+	// violations. This matches the default behavior of pdfcpu.
+	ValidationRelaxed ValidationMode = "relaxed"
+
+	// ValidationNone skips validation entirely. The document is only parsed,
+	// so structural problems surface when pages are accessed.
+	ValidationNone ValidationMode = "none"
+)
+
+// DocumentConfig defines configuration for opening documents.
+//
+// This configuration follows the Configuration Transformation Pattern (Type 1).
+// It is consumed by document open functions (e.g., document.OpenPDFWithConfig)
+// and is discarded after the document is opened.
+//
+// Validation of field values is performed by the consuming package.
+type DocumentConfig struct {
+	// Fingerprint selects the content fingerprint mode. Defaults to FingerprintContent.
+	Fingerprint FingerprintMode `json:"fingerprint,omitempty"`
+
+	// Validation selects the validation mode. Defaults to ValidationRelaxed.
+	Validation ValidationMode `json:"validation,omitempty"`
+
+	// Repair enables best-effort recovery of documents that fail to parse or
+	// validate. Repaired documents open with warnings instead of failing.
+	// Defaults to false.
+	Repair bool `json:"repair,omitempty"`
+}
+
+// DefaultDocumentConfig returns a DocumentConfig with recommended default values.
+//
+// Defaults:
+//   - Fingerprint: FingerprintContent (SHA-256 of document bytes)
+//   - Validation: ValidationRelaxed
+//   - Repair: false
+func DefaultDocumentConfig() DocumentConfig {
+	return DocumentConfig{
+		Fingerprint: FingerprintContent,
+		Validation:  ValidationRelaxed,
+	}
+}
+
+// Merge overlays non-empty values from source onto the receiver.
+//
+// Merge semantics:
+//   - Fingerprint: only merge if source is non-empty
+//   - Validation: only merge if source is non-empty
+//   - Repair: only merge if source is true (false is the default)
+func (c *DocumentConfig) Merge(source *DocumentConfig) {
+	if source == nil {
+		return
+	}
+
+	if source.Fingerprint != "" {
+		c.Fingerprint = source.Fingerprint
+	}
+
+	if source.Validation != "" {
+		c.Validation = source.Validation
+	}
+
+	if source.Repair {
+		c.Repair = true
+	}
+}
+
+// Finalize applies default values for any unset fields.
+//
+// This method merges the receiver's values onto a fresh default configuration,
+// ensuring all fields have valid values. It modifies the receiver in place.
+func (c *DocumentConfig) Finalize() {
+	defaults := DefaultDocumentConfig()
+	defaults.Merge(c)
+	*c = defaults
+}
@@ -0,0 +1,49 @@
+package config
+
+// EPUBConfig defines configuration for opening EPUB documents.
+//
+// This configuration follows the Configuration Transformation Pattern (Type 1).
+// It is consumed by EPUB open functions (e.g., document.OpenEPUBWithConfig)
+// and is discarded after the document is opened.
+//
+// Validation of field values is performed by the consuming package.
+type EPUBConfig struct {
+	// MaxChars is the maximum number of text characters per page. Chapters
+	// with more text are split into several pages at paragraph boundaries.
+	// Defaults to 3000.
+	MaxChars int `json:"max_chars,omitempty"`
+}
+
+// DefaultEPUBConfig returns an EPUBConfig with recommended default values.
+//
+// Defaults:
+//   - MaxChars: 3000
+func DefaultEPUBConfig() EPUBConfig {
+	return EPUBConfig{
+		MaxChars: 3000,
+	}
+}
+
+// Merge overlays non-zero values from source onto the receiver.
+//
+// Merge semantics:
+//   - MaxChars: only merge if source is greater than zero
+func (c *EPUBConfig) Merge(source *EPUBConfig) {
+	if source == nil {
+		return
+	}
+
+	if source.MaxChars > 0 {
+		c.MaxChars = source.MaxChars
+	}
+}
+
+// Finalize applies default values for any unset fields.
+//
+// This method merges the receiver's values onto a fresh default configuration,
+// ensuring all fields have valid values. It modifies the receiver in place.
+func (c *EPUBConfig) Finalize() {
+	defaults := DefaultEPUBConfig()
+	defaults.Merge(c)
+	*c = defaults
+}
@@ -0,0 +1,96 @@
+package config
+
+import "slices"
+
+// HTMLConfig defines configuration for opening HTML documents.
+//
+// HTML is paginated and rendered by converting it to PDF with an external
+// converter such as wkhtmltopdf or headless Chromium. The converter is invoked
+// as Converter followed by Args, where the placeholders "{input}" and
+// "{output}" are replaced with the HTML file path and the PDF output path.
+// Placeholders may appear inside an argument (e.g., "--print-to-pdf={output}").
+//
+// HTML is treated as untrusted: it reaches the converter automatically from
+// email attachments and archive entries, and the converter runs as soon as
+// the document is opened. The default Args therefore disable JavaScript and
+// access to local files, so a hostile document cannot pull files such as
+// "file:///etc/passwd" into the rendered pages. Custom Args replace the
+// defaults entirely and should apply the equivalent restrictions for their
+// converter (e.g., a sandboxed headless Chromium) unless every input is
+// trusted.
+//
+// This configuration follows the Configuration Transformation Pattern (Type 1).
+// It is consumed by HTML open functions (e.g., document.OpenHTMLWithConfig)
+// and is discarded after the document is opened.
+//
+// Validation of field values is performed by the consuming package.
+type HTMLConfig struct {
+	// Converter is the path or name of the HTML-to-PDF executable. Defaults
+	// to "wkhtmltopdf".
+	Converter string `json:"converter,omitempty"`
+
+	// Args are the converter arguments, containing the "{input}" and
+	// "{output}" placeholders. Defaults to ["--quiet",
+	// "--disable-local-file-access", "--disable-javascript", "{input}",
+	// "{output}"].
+	Args []string `json:"args,omitempty"`
+
+	// TextOnly skips conversion and opens the document as a single page of
+	// readable text. The converter is not required in this mode. Defaults to
+	// false.
+	TextOnly bool `json:"text_only,omitempty"`
+}
+
+// DefaultHTMLConfig returns an HTMLConfig with recommended default values.
+//
+// Defaults:
+//   - Converter: "wkhtmltopdf"
+//   - Args: ["--quiet", "--disable-local-file-access", "--disable-javascript",
+//     "{input}", "{output}"]
+//   - TextOnly: false
+func DefaultHTMLConfig() HTMLConfig {
+	return HTMLConfig{
+		Converter: "wkhtmltopdf",
+		Args: []string{
+			"--quiet",
+			"--disable-local-file-access",
+			"--disable-javascript",
+			"{input}",
+			"{output}",
+		},
+	}
+}
+
+// Merge overlays non-zero values from source onto the receiver.
+//
+// Merge semantics:
+//   - Converter: only merge if source is non-empty
+//   - Args: replaced (not appended) if source is non-empty
+//   - TextOnly: only merge if source is true (false is the default)
+func (c *HTMLConfig) Merge(source *HTMLConfig) {
+	if source == nil {
+		return
+	}
+
+	if source.Converter != "" {
+		c.Converter = source.Converter
+	}
+
+	if len(source.Args) > 0 {
+		c.Args = slices.Clone(source.Args)
+	}
+
+	if source.TextOnly {
+		c.TextOnly = true
+	}
+}
+
+// Finalize applies default values for any unset fields.
+//
+// This method merges the receiver's values onto a fresh default configuration,
+// ensuring all fields have valid values. It modifies the receiver in place.
+func (c *HTMLConfig) Finalize() {
+	defaults := DefaultHTMLConfig()
+	defaults.Merge(c)
+	*c = defaults
+}
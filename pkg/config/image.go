@@ -12,10 +12,29 @@ import "maps"
 // Validation of field values is performed by the consuming package
 // (e.g., pkg/image) during transformation to domain objects.
 type ImageConfig struct {
-	Format  string         `json:"format,omitempty"`  // Image format: "png" or "jpg"
-	Quality int            `json:"quality,omitempty"` // JPEG quality: 1-100 (ignored for PNG)
-	DPI     int            `json:"dpi,omitempty"`     // Render density in dots per inch
-	Options map[string]any `json:"options,omitempty"`
+	// Renderer names the registered renderer implementation used by
+	// image.Create (e.g., "imagemagick", "pdftoppm"). Empty selects
+	// "imagemagick".
+	Renderer string `json:"renderer,omitempty"`
+
+	Format   string         `json:"format,omitempty"`   // Image format: "png", "jpg", "webp", or "avif"
+	Quality  int            `json:"quality,omitempty"`  // Lossy quality: 1-100 (ignored for PNG and lossless WebP)
+	Lossless bool           `json:"lossless,omitempty"` // Encode WebP losslessly
+	DPI      int            `json:"dpi,omitempty"`      // Render density in dots per inch
+	Options  map[string]any `json:"options,omitempty"`
+
+	// Fit limits size output in pixels instead of rendering at DPI. When any
+	// limit is set, renderers compute an effective DPI per page from its size
+	// so the output fits every limit, and DPI is ignored.
+	MaxWidth      int     `json:"max_width,omitempty"`      // Maximum width in pixels
+	MaxHeight     int     `json:"max_height,omitempty"`     // Maximum height in pixels
+	MaxLongEdge   int     `json:"max_long_edge,omitempty"`  // Maximum longer side in pixels
+	MaxMegapixels float64 `json:"max_megapixels,omitempty"` // Maximum pixel count in millions
+
+	// MaxBytes is a byte budget for rendered images. When set, documents
+	// lower the quality of lossy formats, then the resolution, until the
+	// encoded image fits (see document.BudgetPage).
+	MaxBytes int `json:"max_bytes,omitempty"`
 }
 
 // DefaultImageConfig returns an ImageConfig with recommended default values.
@@ -23,7 +42,10 @@ type ImageConfig struct {
 // Defaults:
 //   - Format: "png"
 //   - Quality: 0 (not applicable for PNG)
+//   - Lossless: false
 //   - DPI: 300
+//   - Fit limits: 0 (not set; render at DPI)
+//   - MaxBytes: 0 (no byte budget)
 //   - All filter fields: nil (no filters applied)
 func DefaultImageConfig() ImageConfig {
 	return ImageConfig{
@@ -38,12 +60,17 @@ func DefaultImageConfig() ImageConfig {
 //
 // Merge semantics:
 //   - String fields: only merge if source is non-empty
-//   - Integer fields: only merge if source is greater than zero
+//   - Numeric fields: only merge if source is greater than zero
+//   - Boolean fields: only merge if source is true
 //   - Pointer fields: only merge if source is non-nil (allows explicit zero via pointer to 0)
 //
 // This enables layered configuration where higher-priority sources
 // can override lower-priority sources without affecting unset values.
 func (c *ImageConfig) Merge(source *ImageConfig) {
+	if source.Renderer != "" {
+		c.Renderer = source.Renderer
+	}
+
 	if source.Format != "" {
 		c.Format = source.Format
 	}
@@ -52,10 +79,34 @@ func (c *ImageConfig) Merge(source *ImageConfig) {
 		c.Quality = source.Quality
 	}
 
+	if source.Lossless {
+		c.Lossless = true
+	}
+
 	if source.DPI > 0 {
 		c.DPI = source.DPI
 	}
 
+	if source.MaxWidth > 0 {
+		c.MaxWidth = source.MaxWidth
+	}
+
+	if source.MaxHeight > 0 {
+		c.MaxHeight = source.MaxHeight
+	}
+
+	if source.MaxLongEdge > 0 {
+		c.MaxLongEdge = source.MaxLongEdge
+	}
+
+	if source.MaxMegapixels > 0 {
+		c.MaxMegapixels = source.MaxMegapixels
+	}
+
+	if source.MaxBytes > 0 {
+		c.MaxBytes = source.MaxBytes
+	}
+
 	if source.Options != nil {
 		if c.Options == nil {
 			c.Options = make(map[string]any)
@@ -90,14 +141,33 @@ func (c *ImageConfig) Finalize() {
 //   - Saturation: 0-200, where 100 is neutral (no change)
 //   - Rotation: 0-360 degrees clockwise
 //
+// Color mode options:
+//   - ColorMode: "rgb" (default), "grayscale", "bilevel", or "palette"
+//   - Threshold: Bilevel black/white cut-off, 0-100 percent (default: 50)
+//   - Dither: Dither bilevel and palette output instead of thresholding or
+//     mapping to the nearest color (default: false)
+//   - Colors: Palette size, 2-256 (default: 16)
+//
+// Binary options:
+//   - Binary: Path or name of the ImageMagick executable (default: "", which
+//     locates ImageMagick 7 "magick" and falls back to ImageMagick 6 "convert")
+//   - IncludeVersion: Fold the ImageMagick version into cache key parameters
+//     so upgrading ImageMagick invalidates old cache entries (default: false)
+//
 // Validation of field values is performed during transformation to renderer objects.
 type ImageMagickConfig struct {
-	Config     ImageConfig // Base configuration (format, DPI, quality)
-	Background string      // Background color for alpha channel flattening
-	Brightness *int        // Brightness adjustment (0-200, 100=neutral)
-	Contrast   *int        // Contrast adjustment (-100 to +100, 0=neutral)
-	Saturation *int        // Saturation adjustment (0-200, 100=neutral)
-	Rotation   *int        // Rotation in degrees (0-360)
+	Config         ImageConfig // Base configuration (format, DPI, quality)
+	Background     string      // Background color for alpha channel flattening
+	Brightness     *int        // Brightness adjustment (0-200, 100=neutral)
+	Contrast       *int        // Contrast adjustment (-100 to +100, 0=neutral)
+	Saturation     *int        // Saturation adjustment (0-200, 100=neutral)
+	Rotation       *int        // Rotation in degrees (0-360)
+	ColorMode      string      // Output color mode (rgb, grayscale, bilevel, palette)
+	Threshold      int         // Bilevel threshold percent (0-100)
+	Dither         bool        // Dither bilevel and palette output
+	Colors         int         // Palette size (2-256)
+	Binary         string      // ImageMagick executable ("" = auto-detect)
+	IncludeVersion bool        // Include the ImageMagick version in cache keys
 }
 
 // DefaultImageMagickConfig returns an ImageMagickConfig with recommended defaults.
@@ -106,13 +176,64 @@ type ImageMagickConfig struct {
 //   - Config: DefaultImageConfig()
 //   - Background: "white"
 //   - All filter fields: nil (no filters applied)
+//   - ColorMode: "rgb"
+//   - Threshold: 50
+//   - Dither: false
+//   - Colors: 16
+//   - Binary: "" (auto-detect "magick", then "convert")
+//   - IncludeVersion: false
 func DefaultImageMagickConfig() ImageMagickConfig {
 	return ImageMagickConfig{
-		Config:     DefaultImageConfig(),
-		Background: "white",
-		Brightness: nil,
-		Contrast:   nil,
-		Saturation: nil,
-		Rotation:   nil,
+		Config:         DefaultImageConfig(),
+		Background:     "white",
+		Brightness:     nil,
+		Contrast:       nil,
+		Saturation:     nil,
+		Rotation:       nil,
+		ColorMode:      "rgb",
+		Threshold:      50,
+		Dither:         false,
+		Colors:         16,
+		Binary:         "",
+		IncludeVersion: false,
+	}
+}
+
+// PdftoppmConfig extends ImageConfig with Poppler pdftoppm rendering options.
+//
+// This configuration is parsed from ImageConfig.Options during renderer
+// initialization. pdftoppm has no image filters, so the ImageMagick filter
+// options (background, brightness, contrast, saturation, rotation) and color
+// mode options (color_mode, threshold, dither, colors) are not supported.
+//
+// Options:
+//   - Antialias: Anti-alias text and vector graphics (default: true)
+//   - CropBox: Render the page crop box instead of the media box (default: false)
+//   - Binary: Path or name of the pdftoppm executable (default: "pdftoppm")
+//   - IncludeVersion: Fold the Poppler version into cache key parameters
+//     (default: false)
+type PdftoppmConfig struct {
+	Config         ImageConfig // Base configuration (format, DPI, quality)
+	Antialias      bool        // Anti-alias text and vector graphics
+	CropBox        bool        // Render the crop box instead of the media box
+	Binary         string      // pdftoppm executable
+	IncludeVersion bool        // Include the Poppler version in cache keys
+}
+
+// DefaultPdftoppmConfig returns a PdftoppmConfig with recommended defaults.
+//
+// Defaults:
+//   - Config: DefaultImageConfig()
+//   - Antialias: true
+//   - CropBox: false
+//   - Binary: "pdftoppm"
+//   - IncludeVersion: false
+func DefaultPdftoppmConfig() PdftoppmConfig {
+	return PdftoppmConfig{
+		Config:         DefaultImageConfig(),
+		Antialias:      true,
+		CropBox:        false,
+		Binary:         "pdftoppm",
+		IncludeVersion: false,
 	}
 }
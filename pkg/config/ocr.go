@@ -0,0 +1,73 @@
+package config
+
+import "slices"
+
+// OCRConfig defines configuration for OCR engines.
+//
+// This configuration follows the Configuration Transformation Pattern (Type 1).
+// It is consumed by engine constructors (e.g., ocr.NewTesseractEngine) and is
+// exposed afterward only as immutable settings.
+//
+// Validation of field values is performed by the consuming package.
+type OCRConfig struct {
+	// Binary is the path or name of the OCR executable. Defaults to "tesseract".
+	Binary string `json:"binary,omitempty"`
+
+	// Languages lists the language models used for recognition, in priority
+	// order (e.g., ["eng", "deu"]). Defaults to ["eng"].
+	Languages []string `json:"languages,omitempty"`
+
+	// PageSegMode selects the page segmentation mode (Tesseract --psm, 1-13).
+	// Mode 0 (orientation and script detection only) produces no text and is
+	// not supported; 0 and negative values are treated as unset. Defaults to
+	// 3 (fully automatic page segmentation).
+	PageSegMode int `json:"psm,omitempty"`
+}
+
+// DefaultOCRConfig returns an OCRConfig with recommended default values.
+//
+// Defaults:
+//   - Binary: "tesseract"
+//   - Languages: ["eng"]
+//   - PageSegMode: 3 (fully automatic page segmentation)
+func DefaultOCRConfig() OCRConfig {
+	return OCRConfig{
+		Binary:      "tesseract",
+		Languages:   []string{"eng"},
+		PageSegMode: 3,
+	}
+}
+
+// Merge overlays non-zero values from source onto the receiver.
+//
+// Merge semantics:
+//   - Binary: only merge if source is non-empty
+//   - Languages: replaced (not appended) if source is non-empty
+//   - PageSegMode: only merge if source is greater than zero
+func (c *OCRConfig) Merge(source *OCRConfig) {
+	if source == nil {
+		return
+	}
+
+	if source.Binary != "" {
+		c.Binary = source.Binary
+	}
+
+	if len(source.Languages) > 0 {
+		c.Languages = slices.Clone(source.Languages)
+	}
+
+	if source.PageSegMode > 0 {
+		c.PageSegMode = source.PageSegMode
+	}
+}
+
+// Finalize applies default values for any unset fields.
+//
+// This method merges the receiver's values onto a fresh default configuration,
+// ensuring all fields have valid values. It modifies the receiver in place.
+func (c *OCRConfig) Finalize() {
+	defaults := DefaultOCRConfig()
+	defaults.Merge(c)
+	*c = defaults
+}
@@ -87,3 +87,30 @@ func ParseNilIntRanged(options map[string]any, key string, low, high int) (*int,
 	}
 	return nil, nil
 }
+
+// ParseBool extracts a boolean value from an options map with fallback support.
+//
+// Parameters:
+//   - options: The map[string]any to extract from
+//   - key: The configuration key to look up
+//   - fallback: Default value returned when key is not present
+//
+// Returns the boolean value from options, or fallback if key is absent.
+// Returns an error if the key exists but the value is not a boolean.
+//
+// Example:
+//
+//	antialias, err := ParseBool(cfg.Options, "antialias", true)
+//	if err != nil {
+//	    return nil, fmt.Errorf("invalid antialias: %w", err)
+//	}
+func ParseBool(options map[string]any, key string, fallback bool) (bool, error) {
+	if value, ok := options[key]; ok {
+		result, ok := value.(bool)
+		if !ok {
+			return false, fmt.Errorf("%s must be a boolean", key)
+		}
+		return result, nil
+	}
+	return fallback, nil
+}
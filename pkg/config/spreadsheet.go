@@ -0,0 +1,73 @@
+package config
+
+// SpreadsheetConfig defines configuration for opening spreadsheet documents.
+//
+// This configuration follows the Configuration Transformation Pattern (Type 1).
+// It is consumed by spreadsheet open functions (e.g.,
+// document.OpenXLSXWithConfig) and is discarded after the document is opened.
+//
+// Validation of field values is performed by the consuming package.
+type SpreadsheetConfig struct {
+	// MaxRows is the maximum number of data rows per page. Sheets with more
+	// rows are split into several pages. Defaults to 50.
+	MaxRows int `json:"max_rows,omitempty"`
+
+	// MaxColumns is the maximum number of columns per page. Wider sheets are
+	// split into several pages. Defaults to 20.
+	MaxColumns int `json:"max_columns,omitempty"`
+
+	// HeaderRow treats the first row of each sheet as a header that is
+	// repeated at the top of every page of the sheet. Uses a pointer to
+	// distinguish "not set" (nil) from an explicit false. Defaults to true.
+	HeaderRow *bool `json:"header_row,omitempty"`
+}
+
+// DefaultSpreadsheetConfig returns a SpreadsheetConfig with recommended
+// default values.
+//
+// Defaults:
+//   - MaxRows: 50
+//   - MaxColumns: 20
+//   - HeaderRow: true
+func DefaultSpreadsheetConfig() SpreadsheetConfig {
+	header := true
+	return SpreadsheetConfig{
+		MaxRows:    50,
+		MaxColumns: 20,
+		HeaderRow:  &header,
+	}
+}
+
+// Merge overlays non-zero values from source onto the receiver.
+//
+// Merge semantics:
+//   - MaxRows, MaxColumns: only merge if source is greater than zero
+//   - HeaderRow: only merge if source is non-nil (allows explicit false)
+func (c *SpreadsheetConfig) Merge(source *SpreadsheetConfig) {
+	if source == nil {
+		return
+	}
+
+	if source.MaxRows > 0 {
+		c.MaxRows = source.MaxRows
+	}
+
+	if source.MaxColumns > 0 {
+		c.MaxColumns = source.MaxColumns
+	}
+
+	if source.HeaderRow != nil {
+		header := *source.HeaderRow
+		c.HeaderRow = &header
+	}
+}
+
+// Finalize applies default values for any unset fields.
+//
+// This method merges the receiver's values onto a fresh default configuration,
+// ensuring all fields have valid values. It modifies the receiver in place.
+func (c *SpreadsheetConfig) Finalize() {
+	defaults := DefaultSpreadsheetConfig()
+	defaults.Merge(c)
+	*c = defaults
+}
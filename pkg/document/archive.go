@@ -0,0 +1,505 @@
+package document
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/JaimeStill/document-context/pkg/cache"
+	"github.com/JaimeStill/document-context/pkg/config"
+	"github.com/JaimeStill/document-context/pkg/image"
+)
+
+// ErrArchiveLimit is returned (wrapped) when an archive exceeds the entry
+// count, total size, or nesting depth limits of its ArchiveConfig.
+var ErrArchiveLimit = errors.New("archive limit exceeded")
+
+// sniffSize is the number of leading bytes read to detect an entry's type.
+const sniffSize = 512
+
+// The archive opener is registered at init rather than in the formatRegistry
+// literal because opening entries consults the registry, which would
+// otherwise form an initialization cycle.
+func init() {
+	formatRegistry["application/zip"] = func(path string) (Document, error) {
+		return OpenZIP(path)
+	}
+}
+
+// ArchiveDocument is a ZIP archive opened as a collection of documents.
+//
+// Every supported entry is opened through the format registry, and nested
+// archives are opened recursively. The pages of the archive are the pages of
+// all entry documents, flattened in entry order; each ArchivePage records the
+// entry path and page number it came from.
+type ArchiveDocument struct {
+	path        string
+	fingerprint string
+	entries     []ArchiveEntry
+	pages       []archivePageRef
+	tmpDir      string
+	closed      bool
+	mu          sync.Mutex
+}
+
+// ArchiveEntry is a file entry of an archive.
+type ArchiveEntry struct {
+	// Path is the entry path within the archive (e.g., "scans/page1.png").
+	Path string
+
+	// ContentType is the detected content type (see DetectContentType).
+	ContentType string
+
+	// Size is the uncompressed size in bytes.
+	Size int64
+
+	// Document is the entry opened through the format registry (an
+	// *ArchiveDocument for nested archives), or nil if it could not be
+	// opened.
+	Document Document
+
+	// Err reports why Document is nil, wrapping ErrUnsupportedContentType
+	// for formats without a registered opener.
+	Err error
+}
+
+// archivePageRef locates a flattened page: page number page of doc, from the
+// entry at path entry (including enclosing archive entries).
+type archivePageRef struct {
+	entry string
+	doc   Document
+	page  int
+}
+
+// archiveBudget tracks the limits shared by an archive and the documents
+// nested within it: entry archives, and the attachments of entry messages.
+// Attachments are opened lazily and messages may be read concurrently, so
+// the counters are guarded by mu.
+type archiveBudget struct {
+	cfg     config.ArchiveConfig
+	entries int
+	size    int64
+	mu      sync.Mutex
+}
+
+// addEntry charges one file entry to the budget.
+func (b *archiveBudget) addEntry() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.entries++
+	if b.entries > b.cfg.MaxEntries {
+		return fmt.Errorf("%w: more than %d entries", ErrArchiveLimit, b.cfg.MaxEntries)
+	}
+	return nil
+}
+
+// remaining returns the number of bytes left in the size budget.
+func (b *archiveBudget) remaining() int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.cfg.MaxTotalSize - b.size
+}
+
+// addSize charges n bytes to the budget.
+func (b *archiveBudget) addSize(n int64) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.size += n
+	if b.size > b.cfg.MaxTotalSize {
+		return fmt.Errorf("%w: total size exceeds %d bytes", ErrArchiveLimit, b.cfg.MaxTotalSize)
+	}
+	return nil
+}
+
+// openNested opens a document found inside an archive or message at the
+// given archive nesting depth. Archives and messages are opened under the
+// enclosing budget rather than through the format registry, whose openers
+// would start a fresh one, so nesting cannot escape the caller's limits.
+func openNested(path, contentType string, budget *archiveBudget, depth int) (Document, error) {
+	switch contentType {
+	case "application/zip":
+		doc, err := openArchive(path, budget, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		return doc, nil
+	case "message/rfc822":
+		doc, err := openEML(path, budget, depth)
+		if err != nil {
+			return nil, err
+		}
+		return doc, nil
+	default:
+		return Open(path, contentType)
+	}
+}
+
+// OpenZIP opens a ZIP archive using the default ArchiveConfig.
+func OpenZIP(path string) (*ArchiveDocument, error) {
+	return OpenZIPWithConfig(path, config.DefaultArchiveConfig())
+}
+
+// OpenZIPWithConfig opens a ZIP archive as a collection of documents.
+//
+// Entries are enumerated in archive order; directories and macOS metadata
+// (__MACOSX/, ._ files) are skipped. Each entry's type is detected from its
+// name and leading bytes. Supported entries are extracted to a temporary
+// directory and opened through the format registry; nested ZIP archives are
+// opened recursively with the same limits. Entries that are unsupported or
+// fail to open are listed with an error and contribute no pages.
+//
+// The limits of cfg apply to the whole tree of archives: the number of file
+// entries, the total uncompressed size of extracted entries (counted as
+// bytes are decompressed, not trusted from the archive directory), and the
+// nesting depth. Exceeding any limit fails the open with an error wrapping
+// ErrArchiveLimit. Email messages within the tree inherit the limits, and
+// their attachments (see EmailDocument.Attachments) are charged against them
+// when opened.
+//
+// Returns an error if the archive cannot be read or a limit is exceeded.
+func OpenZIPWithConfig(path string, cfg config.ArchiveConfig) (*ArchiveDocument, error) {
+	cfg.Finalize()
+	return openArchive(path, &archiveBudget{cfg: cfg}, 1)
+}
+
+func openArchive(path string, budget *archiveBudget, depth int) (*ArchiveDocument, error) {
+	if depth > budget.cfg.MaxDepth {
+		return nil, fmt.Errorf("%w: nesting depth exceeds %d", ErrArchiveLimit, budget.cfg.MaxDepth)
+	}
+
+	reader, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer reader.Close()
+
+	fingerprint, err := contentFingerprint(path)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpDir, err := os.MkdirTemp("", "archive-entries-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create entry directory: %w", err)
+	}
+
+	doc := &ArchiveDocument{
+		path:        path,
+		fingerprint: fingerprint,
+		tmpDir:      tmpDir,
+	}
+
+	for _, f := range reader.File {
+		if skipArchiveEntry(f) {
+			continue
+		}
+
+		entry, err := doc.openEntry(f, budget, depth)
+		if err != nil {
+			doc.Close()
+			return nil, err
+		}
+		doc.entries = append(doc.entries, entry)
+	}
+
+	for _, entry := range doc.entries {
+		switch d := entry.Document.(type) {
+		case nil:
+		case *ArchiveDocument:
+			for _, ref := range d.pages {
+				doc.pages = append(doc.pages, archivePageRef{entry: entry.Path + "/" + ref.entry, doc: ref.doc, page: ref.page})
+			}
+		default:
+			for n := 1; n <= d.PageCount(); n++ {
+				doc.pages = append(doc.pages, archivePageRef{entry: entry.Path, doc: d, page: n})
+			}
+		}
+	}
+
+	return doc, nil
+}
+
+// skipArchiveEntry reports whether f is a directory or file system metadata
+// rather than content.
+func skipArchiveEntry(f *zip.File) bool {
+	name := f.Name
+	if f.FileInfo().IsDir() || strings.HasSuffix(name, "/") {
+		return true
+	}
+	return strings.HasPrefix(name, "__MACOSX/") || strings.HasPrefix(path.Base(name), "._")
+}
+
+// openEntry detects, extracts, and opens an archive entry. Errors are
+// returned only for limit violations and extraction failures; open failures
+// are recorded on the entry.
+func (d *ArchiveDocument) openEntry(f *zip.File, budget *archiveBudget, depth int) (ArchiveEntry, error) {
+	entry := ArchiveEntry{Path: f.Name, Size: int64(f.UncompressedSize64)}
+
+	if err := budget.addEntry(); err != nil {
+		return entry, err
+	}
+
+	rc, err := f.Open()
+	if err != nil {
+		entry.Err = fmt.Errorf("entry %q: %w", f.Name, err)
+		return entry, nil
+	}
+	defer rc.Close()
+
+	head := make([]byte, sniffSize)
+	n, err := io.ReadFull(rc, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		entry.Err = fmt.Errorf("entry %q: %w", f.Name, err)
+		return entry, nil
+	}
+	head = head[:n]
+
+	entry.ContentType = DetectContentType(f.Name, "", head)
+	nested := entry.ContentType == "application/zip"
+	if !nested && !IsSupported(entry.ContentType) {
+		entry.Err = fmt.Errorf("entry %q: %w: %s", f.Name, ErrUnsupportedContentType, entry.ContentType)
+		return entry, nil
+	}
+
+	target, err := d.extract(f, head, rc, budget, len(d.entries)+1)
+	if err != nil {
+		return entry, err
+	}
+
+	entry.Document, entry.Err = openNested(target, entry.ContentType, budget, depth)
+	if errors.Is(entry.Err, ErrArchiveLimit) {
+		return entry, fmt.Errorf("entry %q: %w", f.Name, entry.Err)
+	}
+	if entry.Err != nil {
+		entry.Document = nil
+		entry.Err = fmt.Errorf("entry %q: %w", f.Name, entry.Err)
+	}
+	return entry, nil
+}
+
+// extract writes an entry (its already read head followed by the rest of
+// rc) to the temporary directory, charging the bytes to the size budget.
+func (d *ArchiveDocument) extract(f *zip.File, head []byte, rc io.Reader, budget *archiveBudget, index int) (string, error) {
+	remaining := budget.remaining()
+	if int64(f.UncompressedSize64) > remaining {
+		return "", fmt.Errorf("%w: total size exceeds %d bytes", ErrArchiveLimit, budget.cfg.MaxTotalSize)
+	}
+
+	dir := filepath.Join(d.tmpDir, fmt.Sprint(index))
+	if err := os.Mkdir(dir, 0o700); err != nil {
+		return "", fmt.Errorf("failed to extract entry %q: %w", f.Name, err)
+	}
+	target := filepath.Join(dir, safeFilename(f.Name))
+
+	out, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_EXCL, 0o600)
+	if err != nil {
+		return "", fmt.Errorf("failed to extract entry %q: %w", f.Name, err)
+	}
+
+	// Read one byte past the budget to detect entries whose declared size
+	// understates their content.
+	src := io.LimitReader(io.MultiReader(bytes.NewReader(head), rc), remaining+1)
+	written, err := io.Copy(out, src)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to extract entry %q: %w", f.Name, err)
+	}
+
+	if err := budget.addSize(written); err != nil {
+		return "", err
+	}
+	return target, nil
+}
+
+func (d *ArchiveDocument) PageCount() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return len(d.pages)
+}
+
+// Fingerprint returns the SHA-256 of the archive file computed at open time.
+func (d *ArchiveDocument) Fingerprint() string {
+	return d.fingerprint
+}
+
+// Entries returns the file entries of the archive in archive order, including
+// entries that could not be opened.
+func (d *ArchiveDocument) Entries() ([]ArchiveEntry, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.closed {
+		return nil, fmt.Errorf("document is closed")
+	}
+	return append([]ArchiveEntry(nil), d.entries...), nil
+}
+
+func (d *ArchiveDocument) ExtractPage(pageNum int) (Page, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.closed {
+		return nil, fmt.Errorf("document is closed")
+	}
+	if pageNum < 1 || pageNum > len(d.pages) {
+		return nil, fmt.Errorf("page %d out of range [1-%d]", pageNum, len(d.pages))
+	}
+
+	ref := d.pages[pageNum-1]
+	page, err := ref.doc.ExtractPage(ref.page)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract page %d of %s: %w", ref.page, ref.entry, err)
+	}
+
+	return &ArchivePage{number: pageNum, entry: ref.entry, entryPage: ref.page, page: page}, nil
+}
+
+func (d *ArchiveDocument) ExtractAllPages() ([]Page, error) {
+	pages := make([]Page, 0, d.PageCount())
+	for i := 1; i <= d.PageCount(); i++ {
+		page, err := d.ExtractPage(i)
+		if err != nil {
+			return nil, fmt.Errorf("failed to extract page %d: %w", i, err)
+		}
+		pages = append(pages, page)
+	}
+	return pages, nil
+}
+
+// Close closes the entry documents and removes the extracted files.
+func (d *ArchiveDocument) Close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.closed {
+		return nil
+	}
+	d.closed = true
+
+	var err error
+	for _, entry := range d.entries {
+		if entry.Document == nil {
+			continue
+		}
+		if closeErr := entry.Document.Close(); closeErr != nil && err == nil {
+			err = fmt.Errorf("failed to close entry %q: %w", entry.Path, closeErr)
+		}
+	}
+	if removeErr := os.RemoveAll(d.tmpDir); removeErr != nil && err == nil {
+		err = removeErr
+	}
+
+	d.entries = nil
+	d.pages = nil
+	return err
+}
+
+// ArchivePage is a page of an ArchiveDocument: a page of one of its entry
+// documents, with its provenance.
+type ArchivePage struct {
+	number    int
+	entry     string
+	entryPage int
+	page      Page
+}
+
+// Number returns the page number within the flattened archive.
+func (p *ArchivePage) Number() int {
+	return p.number
+}
+
+// Entry returns the path of the entry the page belongs to. Entries of nested
+// archives are prefixed with the path of the enclosing archive entry (e.g.,
+// "batch/scans.zip/page1.png").
+func (p *ArchivePage) Entry() string {
+	return p.entry
+}
+
+// EntryPage returns the page number within the entry document.
+func (p *ArchivePage) EntryPage() int {
+	return p.entryPage
+}
+
+// Source returns the page of the entry document, for access to capabilities
+// ArchivePage does not forward (e.g., MarkdownPage or LayoutPage).
+func (p *ArchivePage) Source() Page {
+	return p.page
+}
+
+// Text returns the text of the entry page. Returns an error when the entry
+// page does not implement TextPage.
+func (p *ArchivePage) Text() (string, error) {
+	tp, ok := p.page.(TextPage)
+	if !ok {
+		return "", fmt.Errorf("page %d (%s) does not support text extraction", p.number, p.entry)
+	}
+	return tp.Text()
+}
+
+// ToImage renders the page through its entry document. Cache keys derive
+// from the entry document's fingerprint, so identical files share images
+// regardless of the archive they come from.
+func (p *ArchivePage) ToImage(renderer image.Renderer, c cache.Cache) ([]byte, error) {
+	return p.page.ToImage(renderer, c)
+}
+
+// ToBudgetImage renders the page like ToImage and returns the image with the
+// encoding it was rendered with (see Render).
+func (p *ArchivePage) ToBudgetImage(renderer image.Renderer, c cache.Cache) (*BudgetImage, error) {
+	if bp, ok := p.page.(BudgetPage); ok {
+		return bp.ToBudgetImage(renderer, c)
+	}
+	return budgetImage(p.Render(renderer, c))
+}
+
+// Render renders the page like ToImage and returns the image with its
+// metadata, forwarding to the entry page when it implements RenderPage.
+// Otherwise the image is rendered through ToImage and described with the
+// configured encoding, without a cache key.
+func (p *ArchivePage) Render(renderer image.Renderer, c cache.Cache) (*RenderResult, error) {
+	if rp, ok := p.page.(RenderPage); ok {
+		return rp.Render(renderer, c)
+	}
+
+	start := time.Now()
+	data, err := p.page.ToImage(renderer, c)
+	if err != nil {
+		return nil, err
+	}
+
+	settings := renderer.Settings()
+	img := &BudgetImage{
+		Data:     data,
+		Encoding: image.Encoding{DPI: float64(settings.DPI), Quality: settings.Quality},
+	}
+	return newRenderResult(renderer, img, "", false, start), nil
+}
+
+// WriteImage renders the page like ToImage and writes the image to w,
+// forwarding to the entry page when it implements StreamPage.
+func (p *ArchivePage) WriteImage(w io.Writer, renderer image.Renderer, c cache.Cache) error {
+	if sp, ok := p.page.(StreamPage); ok {
+		return sp.WriteImage(w, renderer, c)
+	}
+
+	data, err := p.page.ToImage(renderer, c)
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("failed to write image: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+package document
+
+import (
+	"fmt"
+	"sort"
+)
+
+// PageKind classifies how a page's content was produced, which determines
+// whether its text should be extracted, rendered, or recognized with OCR.
+type PageKind string
+
+const (
+	// PageDigital indicates a page with a visible text layer and little image
+	// content. Text extraction is reliable. Pages with no content are also
+	// reported as digital since there is nothing to recognize.
+	PageDigital PageKind = "digital"
+
+	// PageScanned indicates a page dominated by image content with no visible
+	// text layer. OCR or image rendering is required to read it. Scans with an
+	// invisible OCR text layer are still reported as scanned.
+	PageScanned PageKind = "scanned"
+
+	// PageMixed indicates a page combining a visible text layer with
+	// significant image content, or image content alongside sparse text.
+	PageMixed PageKind = "mixed"
+)
+
+const (
+	// scannedCoverage is the fraction of the page area covered by images at
+	// which a page is considered image dominated.
+	scannedCoverage = 0.5
+
+	// minTextGlyphs is the number of visible glyphs at which a page is
+	// considered to have a text layer.
+	minTextGlyphs = 20
+
+	// coverageGrid is the resolution of the grid used to estimate the union
+	// of image areas on the page.
+	coverageGrid = 64
+)
+
+// PageClassification reports the kind of a page along with the statistics
+// used to derive it.
+type PageClassification struct {
+	// Page is the 1-indexed page number.
+	Page int
+
+	// Kind is the derived page classification.
+	Kind PageKind
+
+	// TextOperators is the number of text-showing operators (Tj, TJ, ', ").
+	TextOperators int
+
+	// Glyphs is the number of visible non-whitespace glyphs.
+	Glyphs int
+
+	// InvisibleGlyphs is the number of glyphs drawn with an invisible text
+	// render mode, typically an OCR layer over a scanned image.
+	InvisibleGlyphs int
+
+	// Images is the number of images painted on the page.
+	Images int
+
+	// ImageCoverage is the fraction (0-1) of the page area covered by images.
+	ImageCoverage float64
+
+	// Fonts lists the names of fonts used by text operators, sorted.
+	Fonts []string
+}
+
+// ClassifiablePage is implemented by pages that can classify their content.
+type ClassifiablePage interface {
+	Page
+	Classify() (*PageClassification, error)
+}
+
+// Classify inspects the page content streams and reports whether the page is
+// digital, scanned, or mixed.
+//
+// Classification rules:
+//   - Image coverage ≥ 50% and fewer than 20 visible glyphs: scanned
+//   - Image coverage ≥ 50% with a visible text layer: mixed
+//   - Images present with fewer than 20 visible glyphs: mixed
+//   - Otherwise: digital
+func (p *PDFPage) Classify() (*PageClassification, error) {
+	content, err := p.interpret()
+	if err != nil {
+		return nil, err
+	}
+
+	fonts := make([]string, 0, len(content.fonts))
+	for name := range content.fonts {
+		fonts = append(fonts, name)
+	}
+	sort.Strings(fonts)
+
+	result := &PageClassification{
+		Page:            p.number,
+		TextOperators:   content.textOperators,
+		Glyphs:          content.glyphs,
+		InvisibleGlyphs: content.invisibleGlyphs,
+		Images:          len(content.images),
+		ImageCoverage:   imageCoverage(content.box, content.images),
+		Fonts:           fonts,
+	}
+	result.Kind = classify(result)
+
+	return result, nil
+}
+
+func classify(c *PageClassification) PageKind {
+	hasText := c.Glyphs >= minTextGlyphs
+
+	switch {
+	case c.ImageCoverage >= scannedCoverage && !hasText:
+		return PageScanned
+	case c.ImageCoverage >= scannedCoverage:
+		return PageMixed
+	case c.Images > 0 && !hasText:
+		return PageMixed
+	default:
+		return PageDigital
+	}
+}
+
+// imageCoverage estimates the fraction of box covered by the union of images
+// by sampling a grid of points across the page.
+func imageCoverage(box Rect, images []Rect) float64 {
+	if len(images) == 0 || box.Width() <= 0 || box.Height() <= 0 {
+		return 0
+	}
+
+	covered := 0
+	for gy := 0; gy < coverageGrid; gy++ {
+		y := box.Y0 + (float64(gy)+0.5)*box.Height()/coverageGrid
+		for gx := 0; gx < coverageGrid; gx++ {
+			x := box.X0 + (float64(gx)+0.5)*box.Width()/coverageGrid
+			for _, img := range images {
+				if x >= img.X0 && x <= img.X1 && y >= img.Y0 && y <= img.Y1 {
+					covered++
+					break
+				}
+			}
+		}
+	}
+
+	return float64(covered) / float64(coverageGrid*coverageGrid)
+}
+
+// ClassifyPages classifies every page of a document.
+//
+// Pages that do not implement ClassifiablePage cause an error, since their
+// content cannot be inspected.
+func ClassifyPages(doc Document) ([]PageClassification, error) {
+	pages, err := doc.ExtractAllPages()
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]PageClassification, 0, len(pages))
+	for _, page := range pages {
+		cp, ok := page.(ClassifiablePage)
+		if !ok {
+			return nil, fmt.Errorf("page %d does not support classification", page.Number())
+		}
+
+		c, err := cp.Classify()
+		if err != nil {
+			return nil, fmt.Errorf("failed to classify page %d: %w", page.Number(), err)
+		}
+		results = append(results, *c)
+	}
+
+	return results, nil
+}
@@ -0,0 +1,357 @@
+package document
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+)
+
+// contentToken is a single lexical element of a PDF content stream.
+//
+// Operands are represented with Go types: float64 for numbers, pdfName for
+// names, []byte for string literals, []any for arrays and map[string]any for
+// dictionaries. Operators are represented as pdfOperator.
+type contentToken any
+
+type pdfName string
+
+type pdfOperator string
+
+// contentLexer tokenizes PDF content streams (PDF 32000-1:2008, 7.8.2).
+//
+// The lexer is intentionally forgiving: malformed tokens are skipped rather
+// than reported so that text extraction degrades gracefully on damaged
+// content streams.
+type contentLexer struct {
+	data []byte
+	pos  int
+}
+
+func newContentLexer(data []byte) *contentLexer {
+	return &contentLexer{data: data}
+}
+
+func isPDFWhitespace(c byte) bool {
+	switch c {
+	case 0, '\t', '\n', '\f', '\r', ' ':
+		return true
+	}
+	return false
+}
+
+func isPDFDelimiter(c byte) bool {
+	switch c {
+	case '(', ')', '<', '>', '[', ']', '{', '}', '/', '%':
+		return true
+	}
+	return false
+}
+
+func (l *contentLexer) skipWhitespace() {
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		if isPDFWhitespace(c) {
+			l.pos++
+			continue
+		}
+		if c == '%' {
+			for l.pos < len(l.data) && l.data[l.pos] != '\n' && l.data[l.pos] != '\r' {
+				l.pos++
+			}
+			continue
+		}
+		return
+	}
+}
+
+// next returns the next token, or nil when the stream is exhausted.
+func (l *contentLexer) next() contentToken {
+	for {
+		l.skipWhitespace()
+		if l.pos >= len(l.data) {
+			return nil
+		}
+
+		c := l.data[l.pos]
+		switch {
+		case c == '/':
+			return l.readName()
+		case c == '(':
+			return l.readLiteralString()
+		case c == '<':
+			if l.pos+1 < len(l.data) && l.data[l.pos+1] == '<' {
+				l.pos += 2
+				return l.readDict()
+			}
+			return l.readHexString()
+		case c == '[':
+			l.pos++
+			return l.readArray()
+		case c == ']' || c == '>' || c == ')' || c == '{' || c == '}':
+			l.pos++
+			continue
+		case c == '+' || c == '-' || c == '.' || (c >= '0' && c <= '9'):
+			if tok, ok := l.readNumber(); ok {
+				return tok
+			}
+			continue
+		default:
+			return l.readKeyword()
+		}
+	}
+}
+
+func (l *contentLexer) readName() contentToken {
+	l.pos++
+	start := l.pos
+	for l.pos < len(l.data) && !isPDFWhitespace(l.data[l.pos]) && !isPDFDelimiter(l.data[l.pos]) {
+		l.pos++
+	}
+	raw := l.data[start:l.pos]
+	if bytes.IndexByte(raw, '#') < 0 {
+		return pdfName(raw)
+	}
+
+	var decoded []byte
+	for i := 0; i < len(raw); i++ {
+		if raw[i] == '#' && i+2 < len(raw) {
+			if v, err := strconv.ParseUint(string(raw[i+1:i+3]), 16, 8); err == nil {
+				decoded = append(decoded, byte(v))
+				i += 2
+				continue
+			}
+		}
+		decoded = append(decoded, raw[i])
+	}
+	return pdfName(decoded)
+}
+
+func (l *contentLexer) readNumber() (contentToken, bool) {
+	start := l.pos
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		if c == '+' || c == '-' || c == '.' || (c >= '0' && c <= '9') {
+			l.pos++
+			continue
+		}
+		break
+	}
+	v, err := strconv.ParseFloat(string(l.data[start:l.pos]), 64)
+	if err != nil {
+		return nil, false
+	}
+	return v, true
+}
+
+func (l *contentLexer) readLiteralString() contentToken {
+	l.pos++
+	var out []byte
+	depth := 1
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		l.pos++
+		switch c {
+		case '(':
+			depth++
+			out = append(out, c)
+		case ')':
+			depth--
+			if depth == 0 {
+				return out
+			}
+			out = append(out, c)
+		case '\\':
+			if l.pos >= len(l.data) {
+				return out
+			}
+			e := l.data[l.pos]
+			l.pos++
+			switch e {
+			case 'n':
+				out = append(out, '\n')
+			case 'r':
+				out = append(out, '\r')
+			case 't':
+				out = append(out, '\t')
+			case 'b':
+				out = append(out, '\b')
+			case 'f':
+				out = append(out, '\f')
+			case '\r':
+				if l.pos < len(l.data) && l.data[l.pos] == '\n' {
+					l.pos++
+				}
+			case '\n':
+			default:
+				if e >= '0' && e <= '7' {
+					v := int(e - '0')
+					for i := 0; i < 2 && l.pos < len(l.data); i++ {
+						d := l.data[l.pos]
+						if d < '0' || d > '7' {
+							break
+						}
+						v = v*8 + int(d-'0')
+						l.pos++
+					}
+					out = append(out, byte(v))
+				} else {
+					out = append(out, e)
+				}
+			}
+		default:
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+func (l *contentLexer) readHexString() contentToken {
+	l.pos++
+	var digits []byte
+	for l.pos < len(l.data) && l.data[l.pos] != '>' {
+		c := l.data[l.pos]
+		if !isPDFWhitespace(c) {
+			digits = append(digits, c)
+		}
+		l.pos++
+	}
+	l.pos++
+	if len(digits)%2 == 1 {
+		digits = append(digits, '0')
+	}
+	out := make([]byte, 0, len(digits)/2)
+	for i := 0; i+1 < len(digits); i += 2 {
+		v, err := strconv.ParseUint(string(digits[i:i+2]), 16, 8)
+		if err != nil {
+			continue
+		}
+		out = append(out, byte(v))
+	}
+	return out
+}
+
+func (l *contentLexer) readArray() contentToken {
+	var items []any
+	for {
+		l.skipWhitespace()
+		if l.pos >= len(l.data) {
+			return items
+		}
+		if l.data[l.pos] == ']' {
+			l.pos++
+			return items
+		}
+		tok := l.next()
+		if tok == nil {
+			return items
+		}
+		items = append(items, tok)
+	}
+}
+
+func (l *contentLexer) readDict() contentToken {
+	dict := make(map[string]any)
+	var key pdfName
+	haveKey := false
+	for {
+		l.skipWhitespace()
+		if l.pos >= len(l.data) {
+			return dict
+		}
+		if l.data[l.pos] == '>' && l.pos+1 < len(l.data) && l.data[l.pos+1] == '>' {
+			l.pos += 2
+			return dict
+		}
+		tok := l.next()
+		if tok == nil {
+			return dict
+		}
+		if !haveKey {
+			if n, ok := tok.(pdfName); ok {
+				key = n
+				haveKey = true
+			}
+			continue
+		}
+		dict[string(key)] = tok
+		haveKey = false
+	}
+}
+
+func (l *contentLexer) readKeyword() contentToken {
+	start := l.pos
+	for l.pos < len(l.data) && !isPDFWhitespace(l.data[l.pos]) && !isPDFDelimiter(l.data[l.pos]) {
+		l.pos++
+	}
+	if l.pos == start {
+		l.pos++
+		return l.next()
+	}
+	op := pdfOperator(l.data[start:l.pos])
+	if op == "BI" {
+		l.skipInlineImage()
+		return pdfOperator("EI")
+	}
+	return op
+}
+
+// skipInlineImage advances past an inline image (BI ... ID <data> EI).
+func (l *contentLexer) skipInlineImage() {
+	idx := bytes.Index(l.data[l.pos:], []byte("ID"))
+	if idx < 0 {
+		l.pos = len(l.data)
+		return
+	}
+	l.pos += idx + 2
+	for l.pos < len(l.data) {
+		idx := bytes.Index(l.data[l.pos:], []byte("EI"))
+		if idx < 0 {
+			l.pos = len(l.data)
+			return
+		}
+		end := l.pos + idx
+		before := end == 0 || isPDFWhitespace(l.data[end-1])
+		after := end+2 >= len(l.data) || isPDFWhitespace(l.data[end+2])
+		l.pos = end + 2
+		if before && after {
+			return
+		}
+	}
+}
+
+// matrix is a PDF transformation matrix [a b c d e f].
+type matrix [6]float64
+
+var identityMatrix = matrix{1, 0, 0, 1, 0, 0}
+
+// multiply returns m × n.
+func (m matrix) multiply(n matrix) matrix {
+	return matrix{
+		m[0]*n[0] + m[1]*n[2],
+		m[0]*n[1] + m[1]*n[3],
+		m[2]*n[0] + m[3]*n[2],
+		m[2]*n[1] + m[3]*n[3],
+		m[4]*n[0] + m[5]*n[2] + n[4],
+		m[4]*n[1] + m[5]*n[3] + n[5],
+	}
+}
+
+// apply transforms the point (x, y) by m.
+func (m matrix) apply(x, y float64) (float64, float64) {
+	return x*m[0] + y*m[2] + m[4], x*m[1] + y*m[3] + m[5]
+}
+
+func matrixFromOperands(ops []any) (matrix, error) {
+	if len(ops) < 6 {
+		return identityMatrix, fmt.Errorf("expected 6 operands, got %d", len(ops))
+	}
+	var m matrix
+	for i := 0; i < 6; i++ {
+		v, ok := ops[len(ops)-6+i].(float64)
+		if !ok {
+			return identityMatrix, fmt.Errorf("matrix operand %d is not a number", i)
+		}
+		m[i] = v
+	}
+	return m, nil
+}
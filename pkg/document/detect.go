@@ -0,0 +1,69 @@
+package document
+
+import (
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+)
+
+// extensionTypes maps file extensions to the content types of the format
+// registry. It takes precedence over the system MIME table, which may not
+// know Office formats.
+var extensionTypes = map[string]string{
+	".pdf":  "application/pdf",
+	".docx": "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+	".xlsx": "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	".pptx": "application/vnd.openxmlformats-officedocument.presentationml.presentation",
+	".csv":  "text/csv",
+	".tsv":  "text/tab-separated-values",
+	".epub": "application/epub+zip",
+	".eml":  "message/rfc822",
+	".html": "text/html",
+	".htm":  "text/html",
+	".zip":  "application/zip",
+	".png":  "image/png",
+	".jpg":  "image/jpeg",
+	".jpeg": "image/jpeg",
+	".gif":  "image/gif",
+}
+
+// DetectContentType determines the content type of an embedded file, such as
+// an email attachment, for opening through the format registry.
+//
+// The declared type is used when it is a supported format. Otherwise the
+// type is derived from the file name extension and, failing that, sniffed
+// from the leading bytes of data. Parameters (e.g., charset) are dropped.
+//
+// Parameters:
+//   - name: the file name, used for its extension (may be empty)
+//   - declared: the declared content type (may be empty or generic, such as
+//     application/octet-stream)
+//   - data: the file content, or a prefix of at least 512 bytes
+//
+// Returns the detected content type, which may not be supported.
+func DetectContentType(name, declared string, data []byte) string {
+	if mediaType, _, err := mime.ParseMediaType(declared); err == nil {
+		declared = strings.ToLower(mediaType)
+		if IsSupported(declared) {
+			return declared
+		}
+	}
+
+	ext := strings.ToLower(filepath.Ext(name))
+	if contentType, ok := extensionTypes[ext]; ok {
+		return contentType
+	}
+	if contentType := mime.TypeByExtension(ext); contentType != "" {
+		if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+			return mediaType
+		}
+	}
+
+	if declared != "" && declared != "application/octet-stream" {
+		return declared
+	}
+
+	sniffed, _, _ := mime.ParseMediaType(http.DetectContentType(data))
+	return sniffed
+}
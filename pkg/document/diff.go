@@ -0,0 +1,457 @@
+package document
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	goimage "image"
+	"image/color"
+	_ "image/jpeg"
+	"image/png"
+	"math/bits"
+	"strings"
+
+	"github.com/JaimeStill/document-context/pkg/cache"
+	"github.com/JaimeStill/document-context/pkg/image"
+)
+
+// PageChange classifies how a page differs between two document versions.
+type PageChange string
+
+const (
+	// PageUnchanged indicates the page text and rendered pixels are identical.
+	PageUnchanged PageChange = "unchanged"
+
+	// PageModified indicates the page was aligned with a page in the other
+	// version but its text or rendered pixels differ.
+	PageModified PageChange = "modified"
+
+	// PageAdded indicates the page exists only in the revised document.
+	PageAdded PageChange = "added"
+
+	// PageRemoved indicates the page exists only in the original document.
+	PageRemoved PageChange = "removed"
+)
+
+// matchThreshold is the minimum combined similarity for two pages to be
+// aligned as versions of the same page.
+const matchThreshold = 0.5
+
+// pixelThreshold is the minimum luminance difference (0-255) for a pixel to be
+// reported as changed in a visual diff.
+const pixelThreshold = 48
+
+// PageDiff describes the alignment of a single page between two versions.
+type PageDiff struct {
+	// Change classifies the page difference.
+	Change PageChange
+
+	// OldPage is the page number in the original document (0 when added).
+	OldPage int
+
+	// NewPage is the page number in the revised document (0 when removed).
+	NewPage int
+
+	// Similarity is the combined text and perceptual similarity (0-1) of
+	// aligned pages. It is 0 for added and removed pages.
+	Similarity float64
+
+	// HashDistance is the Hamming distance (0-64) between the perceptual
+	// hashes of aligned pages, or -1 when pages were not rendered.
+	HashDistance int
+}
+
+// Comparison is the page-level difference between two document versions.
+//
+// Pages are listed in document order: removed pages appear at their position
+// in the original, added pages at their position in the revision.
+type Comparison struct {
+	Pages []PageDiff
+}
+
+// Changed returns the page differences that are not PageUnchanged.
+func (c *Comparison) Changed() []PageDiff {
+	changed := make([]PageDiff, 0, len(c.Pages))
+	for _, p := range c.Pages {
+		if p.Change != PageUnchanged {
+			changed = append(changed, p)
+		}
+	}
+	return changed
+}
+
+// pageSignature captures the comparable properties of a page.
+type pageSignature struct {
+	text      string
+	words     map[string]int
+	hasText   bool
+	hash      uint64
+	pixelHash [32]byte
+	hasImage  bool
+}
+
+// Compare aligns the pages of two document versions and reports added,
+// removed and modified pages.
+//
+// Pages are compared by extracted text (for pages implementing TextPage) and
+// by a perceptual hash of the rendered page when a renderer is provided. The
+// alignment preserves page order and maximizes total similarity, so inserted
+// or deleted pages do not cause every following page to be reported modified.
+//
+// Parameters:
+//   - original, revised: the document versions to compare
+//   - renderer: optional renderer enabling perceptual comparison. Pass nil to compare by text only.
+//   - c: optional cache for rendered pages. Pass nil to disable caching.
+//
+// Returns an error if a page cannot be read or rendered, or if pages offer
+// neither text nor a renderer to compare with.
+func Compare(original, revised Document, renderer image.Renderer, c cache.Cache) (*Comparison, error) {
+	oldSigs, err := signatures(original, renderer, c)
+	if err != nil {
+		return nil, fmt.Errorf("failed to analyze original document: %w", err)
+	}
+
+	newSigs, err := signatures(revised, renderer, c)
+	if err != nil {
+		return nil, fmt.Errorf("failed to analyze revised document: %w", err)
+	}
+
+	n, m := len(oldSigs), len(newSigs)
+	sim := make([][]float64, n)
+	for i := range sim {
+		sim[i] = make([]float64, m)
+		for j := range sim[i] {
+			sim[i][j] = similarity(oldSigs[i], newSigs[j])
+		}
+	}
+
+	score := make([][]float64, n+1)
+	for i := range score {
+		score[i] = make([]float64, m+1)
+	}
+	for i := 1; i <= n; i++ {
+		for j := 1; j <= m; j++ {
+			best := max(score[i-1][j], score[i][j-1])
+			if s := sim[i-1][j-1]; s >= matchThreshold {
+				best = max(best, score[i-1][j-1]+s)
+			}
+			score[i][j] = best
+		}
+	}
+
+	var reversed []PageDiff
+	i, j := n, m
+	for i > 0 || j > 0 {
+		switch {
+		case i > 0 && j > 0 && sim[i-1][j-1] >= matchThreshold && score[i][j] == score[i-1][j-1]+sim[i-1][j-1]:
+			reversed = append(reversed, alignedDiff(oldSigs[i-1], newSigs[j-1], i, j, sim[i-1][j-1]))
+			i--
+			j--
+		case j > 0 && (i == 0 || score[i][j] == score[i][j-1]):
+			reversed = append(reversed, PageDiff{Change: PageAdded, NewPage: j, HashDistance: -1})
+			j--
+		default:
+			reversed = append(reversed, PageDiff{Change: PageRemoved, OldPage: i, HashDistance: -1})
+			i--
+		}
+	}
+
+	pages := make([]PageDiff, len(reversed))
+	for k, d := range reversed {
+		pages[len(reversed)-1-k] = d
+	}
+
+	return &Comparison{Pages: pages}, nil
+}
+
+func alignedDiff(a, b pageSignature, oldPage, newPage int, similarity float64) PageDiff {
+	diff := PageDiff{
+		Change:       PageModified,
+		OldPage:      oldPage,
+		NewPage:      newPage,
+		Similarity:   similarity,
+		HashDistance: -1,
+	}
+
+	if a.hasImage && b.hasImage {
+		diff.HashDistance = bits.OnesCount64(a.hash ^ b.hash)
+	}
+
+	sameText := !a.hasText || !b.hasText || a.text == b.text
+	sameImage := !a.hasImage || !b.hasImage || a.pixelHash == b.pixelHash
+	if sameText && sameImage {
+		diff.Change = PageUnchanged
+	}
+
+	return diff
+}
+
+func signatures(doc Document, renderer image.Renderer, c cache.Cache) ([]pageSignature, error) {
+	pages, err := doc.ExtractAllPages()
+	if err != nil {
+		return nil, err
+	}
+
+	sigs := make([]pageSignature, len(pages))
+	for i, page := range pages {
+		var sig pageSignature
+
+		if tp, ok := page.(TextPage); ok {
+			text, err := tp.Text()
+			if err != nil {
+				return nil, fmt.Errorf("failed to extract text from page %d: %w", page.Number(), err)
+			}
+			sig.text = normalizeText(text)
+			sig.words = wordCounts(sig.text)
+			sig.hasText = true
+		}
+
+		if renderer != nil {
+			data, err := page.ToImage(renderer, c)
+			if err != nil {
+				return nil, err
+			}
+			img, _, err := goimage.Decode(bytes.NewReader(data))
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode page %d image: %w", page.Number(), err)
+			}
+			sig.hash = perceptualHash(img)
+			sig.pixelHash = pixelHash(img)
+			sig.hasImage = true
+		}
+
+		if !sig.hasText && !sig.hasImage {
+			return nil, fmt.Errorf("page %d has no text layer and no renderer was provided", page.Number())
+		}
+
+		sigs[i] = sig
+	}
+
+	return sigs, nil
+}
+
+func normalizeText(s string) string {
+	return strings.Join(strings.Fields(strings.ToLower(s)), " ")
+}
+
+func wordCounts(s string) map[string]int {
+	counts := make(map[string]int)
+	for _, w := range strings.Fields(s) {
+		counts[w]++
+	}
+	return counts
+}
+
+// textSimilarity returns the Dice coefficient of the word multisets of a and b.
+func textSimilarity(a, b pageSignature) float64 {
+	total := 0
+	for _, n := range a.words {
+		total += n
+	}
+	for _, n := range b.words {
+		total += n
+	}
+	if total == 0 {
+		return 1
+	}
+
+	common := 0
+	for w, n := range a.words {
+		common += min(n, b.words[w])
+	}
+	return 2 * float64(common) / float64(total)
+}
+
+// similarity combines text and perceptual similarity of two pages. Text is
+// weighted more heavily because it is insensitive to rendering noise.
+func similarity(a, b pageSignature) float64 {
+	hasText := a.hasText && b.hasText && (len(a.words) > 0 || len(b.words) > 0)
+	hasImage := a.hasImage && b.hasImage
+
+	var hashSim float64
+	if hasImage {
+		hashSim = 1 - float64(bits.OnesCount64(a.hash^b.hash))/64
+	}
+
+	switch {
+	case hasText && hasImage:
+		return 0.6*textSimilarity(a, b) + 0.4*hashSim
+	case hasText:
+		return textSimilarity(a, b)
+	case hasImage:
+		return hashSim
+	default:
+		return 1
+	}
+}
+
+func luminance(c color.Color) uint8 {
+	return color.GrayModel.Convert(c).(color.Gray).Y
+}
+
+// perceptualHash computes a 64-bit difference hash (dHash) of img: the image
+// is reduced to a 9x8 grayscale grid and each bit records whether a cell is
+// brighter than its right neighbour.
+func perceptualHash(img goimage.Image) uint64 {
+	const w, h = 9, 8
+	b := img.Bounds()
+
+	var grid [h][w]float64
+	for gy := 0; gy < h; gy++ {
+		y0 := b.Min.Y + gy*b.Dy()/h
+		y1 := max(b.Min.Y+(gy+1)*b.Dy()/h, y0+1)
+		for gx := 0; gx < w; gx++ {
+			x0 := b.Min.X + gx*b.Dx()/w
+			x1 := max(b.Min.X+(gx+1)*b.Dx()/w, x0+1)
+
+			sum, count := 0.0, 0
+			stepY := max((y1-y0)/16, 1)
+			stepX := max((x1-x0)/16, 1)
+			for y := y0; y < y1; y += stepY {
+				for x := x0; x < x1; x += stepX {
+					sum += float64(luminance(img.At(x, y)))
+					count++
+				}
+			}
+			grid[gy][gx] = sum / float64(count)
+		}
+	}
+
+	var hash uint64
+	for gy := 0; gy < h; gy++ {
+		for gx := 0; gx < w-1; gx++ {
+			hash <<= 1
+			if grid[gy][gx] > grid[gy][gx+1] {
+				hash |= 1
+			}
+		}
+	}
+	return hash
+}
+
+// pixelHash returns a SHA-256 digest of the decoded pixels, independent of the
+// encoded file's metadata.
+func pixelHash(img goimage.Image) [32]byte {
+	b := img.Bounds()
+	h := sha256.New()
+	fmt.Fprintf(h, "%dx%d;", b.Dx(), b.Dy())
+	row := make([]byte, 0, b.Dx()*4)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		row = row[:0]
+		for x := b.Min.X; x < b.Max.X; x++ {
+			r, g, bl, a := img.At(x, y).RGBA()
+			row = append(row, byte(r>>8), byte(g>>8), byte(bl>>8), byte(a>>8))
+		}
+		h.Write(row)
+	}
+	var sum [32]byte
+	copy(sum[:], h.Sum(nil))
+	return sum
+}
+
+// VisualDiff is a rendered highlight of the pixel differences between two pages.
+type VisualDiff struct {
+	// Data is the PNG encoded diff image. Unchanged content is shown faded,
+	// changed pixels are painted red.
+	Data []byte
+
+	// ChangedPixels is the number of pixels whose luminance differs.
+	ChangedPixels int
+
+	// ChangedRatio is ChangedPixels divided by the total pixel count.
+	ChangedRatio float64
+}
+
+// RenderDiff renders two versions of a page and produces an image
+// highlighting the pixels that changed.
+//
+// Both pages are rendered with the same renderer so their dimensions match for
+// equal page sizes. When sizes differ, the diff covers the larger extent and
+// pixels present in only one version are reported changed.
+//
+// Parameters:
+//   - original, revised: the page versions to compare
+//   - renderer: image renderer used for both pages
+//   - c: optional cache for rendered pages. Pass nil to disable caching.
+func RenderDiff(original, revised Page, renderer image.Renderer, c cache.Cache) (*VisualDiff, error) {
+	if renderer == nil {
+		return nil, fmt.Errorf("renderer is required for visual diff")
+	}
+
+	oldImg, err := renderDecoded(original, renderer, c)
+	if err != nil {
+		return nil, err
+	}
+
+	newImg, err := renderDecoded(revised, renderer, c)
+	if err != nil {
+		return nil, err
+	}
+
+	ob, nb := oldImg.Bounds(), newImg.Bounds()
+	width := max(ob.Dx(), nb.Dx())
+	height := max(ob.Dy(), nb.Dy())
+
+	out := goimage.NewRGBA(goimage.Rect(0, 0, width, height))
+	changed := 0
+	highlight := color.RGBA{R: 255, A: 255}
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			inOld := x < ob.Dx() && y < ob.Dy()
+			inNew := x < nb.Dx() && y < nb.Dy()
+
+			var lo, ln uint8 = 255, 255
+			if inOld {
+				lo = luminance(oldImg.At(ob.Min.X+x, ob.Min.Y+y))
+			}
+			if inNew {
+				ln = luminance(newImg.At(nb.Min.X+x, nb.Min.Y+y))
+			}
+
+			delta := int(lo) - int(ln)
+			if delta < 0 {
+				delta = -delta
+			}
+
+			if inOld != inNew || delta > pixelThreshold {
+				out.SetRGBA(x, y, highlight)
+				changed++
+				continue
+			}
+
+			faded := uint8(255 - (255-int(ln))/3)
+			out.SetRGBA(x, y, color.RGBA{R: faded, G: faded, B: faded, A: 255})
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, out); err != nil {
+		return nil, fmt.Errorf("failed to encode diff image: %w", err)
+	}
+
+	ratio := 0.0
+	if total := width * height; total > 0 {
+		ratio = float64(changed) / float64(total)
+	}
+
+	return &VisualDiff{
+		Data:          buf.Bytes(),
+		ChangedPixels: changed,
+		ChangedRatio:  ratio,
+	}, nil
+}
+
+func renderDecoded(page Page, renderer image.Renderer, c cache.Cache) (goimage.Image, error) {
+	data, err := page.ToImage(renderer, c)
+	if err != nil {
+		return nil, err
+	}
+
+	img, _, err := goimage.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode page %d image: %w", page.Number(), err)
+	}
+
+	return img, nil
+}
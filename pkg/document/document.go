@@ -1,8 +1,11 @@
 package document
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"strings"
+	"time"
 
 	"github.com/JaimeStill/document-context/pkg/cache"
 	"github.com/JaimeStill/document-context/pkg/image"
@@ -13,6 +16,8 @@ type ImageFormat string
 const (
 	PNG  ImageFormat = "png"
 	JPEG ImageFormat = "jpg"
+	WebP ImageFormat = "webp"
+	AVIF ImageFormat = "avif"
 )
 
 func (f ImageFormat) MimeType() (string, error) {
@@ -21,6 +26,10 @@ func (f ImageFormat) MimeType() (string, error) {
 		return "image/png", nil
 	case JPEG:
 		return "image/jpeg", nil
+	case WebP:
+		return "image/webp", nil
+	case AVIF:
+		return "image/avif", nil
 	default:
 		return "", fmt.Errorf("unsupported image format: %s", f)
 	}
@@ -32,6 +41,10 @@ func ParseImageFormat(s string) (ImageFormat, error) {
 		return PNG, nil
 	case "jpg", "jpeg":
 		return JPEG, nil
+	case "webp":
+		return WebP, nil
+	case "avif":
+		return AVIF, nil
 	default:
 		return "", fmt.Errorf("unsupported image format: %s", s)
 	}
@@ -39,6 +52,9 @@ func ParseImageFormat(s string) (ImageFormat, error) {
 
 type Document interface {
 	PageCount() int
+	// Fingerprint returns an identifier derived from the document content,
+	// stable across file paths and changed whenever the content changes.
+	Fingerprint() string
 	ExtractPage(pageNum int) (Page, error)
 	ExtractAllPages() ([]Page, error)
 	Close() error
@@ -49,10 +65,155 @@ type Page interface {
 	ToImage(renderer image.Renderer, c cache.Cache) ([]byte, error)
 }
 
+// TextPage is implemented by pages that can extract their text content.
+//
+// Text extraction is optional: formats without a text layer implement only Page.
+// Callers should use a type assertion to detect support.
+type TextPage interface {
+	Page
+	Text() (string, error)
+}
+
+// MarkdownPage is implemented by pages that can render their content as
+// Markdown, preserving structure such as headings, lists, and tables.
+type MarkdownPage interface {
+	Page
+	Markdown() (string, error)
+}
+
+// BudgetPage is implemented by pages that can report the encoding their
+// image was rendered with, for renderers with a byte budget (see
+// config.ImageConfig MaxBytes).
+//
+// ToImage honors the budget as well; ToBudgetImage additionally returns the
+// quality and DPI that fit it. Without a budget, the image is rendered at
+// the configured quality and the page's render DPI.
+type BudgetPage interface {
+	Page
+	ToBudgetImage(renderer image.Renderer, c cache.Cache) (*BudgetImage, error)
+}
+
+// RenderPage is implemented by pages that can report metadata about their
+// rendered image alongside the data. ToImage remains available on every
+// Page and returns only RenderResult.Data.
+type RenderPage interface {
+	Page
+	Render(renderer image.Renderer, c cache.Cache) (*RenderResult, error)
+}
+
+// RenderResult is a rendered page image with the metadata callers would
+// otherwise have to recover by decoding it.
+type RenderResult struct {
+	// Data is the encoded image.
+	Data []byte
+
+	// Format is the image format. Formats of custom renderers that
+	// ParseImageFormat does not recognize are reported as configured.
+	Format ImageFormat
+
+	// MimeType is the MIME type of Format (e.g., "image/png"), or empty for
+	// unrecognized formats.
+	MimeType string
+
+	// Width and Height are the pixel dimensions read from the image header,
+	// or zero when they cannot be determined.
+	Width  int
+	Height int
+
+	// Size is the length of Data in bytes.
+	Size int
+
+	// Encoding is the quality and DPI the image was rendered with (see
+	// BudgetImage).
+	Encoding image.Encoding
+
+	// CacheKey is the key the image is cached under, set even when no cache
+	// is used.
+	CacheKey string
+
+	// CacheHit reports whether the image was read from the cache.
+	CacheHit bool
+
+	// Duration is the time taken to produce the image, including the cache
+	// lookup and any byte-budget search.
+	Duration time.Duration
+
+	// Parameters are the renderer's parameters (see image.Renderer).
+	Parameters []string
+}
+
+// StreamPage is implemented by pages that can write their image to an
+// io.Writer.
+//
+// With an image.StreamRenderer, and no cache or byte budget, the image is
+// streamed from the renderer to w without being held in memory or written to
+// a temporary file; output already written is not retracted if rendering
+// fails. Otherwise WriteImage renders as ToImage does and writes the result.
+type StreamPage interface {
+	Page
+	WriteImage(w io.Writer, renderer image.Renderer, c cache.Cache) error
+}
+
+// BudgetImage is a rendered page image together with the encoding that
+// produced it.
+type BudgetImage struct {
+	// Data is the encoded image.
+	Data []byte
+
+	// Encoding is the quality and DPI the image was rendered with, lowered
+	// from the configured values when needed to fit the byte budget.
+	Encoding image.Encoding
+}
+
+// ErrByteBudget is returned (wrapped) when a page cannot be rendered within
+// the renderer's byte budget, even at the lowest quality and resolution tried.
+var ErrByteBudget = errors.New("image exceeds byte budget")
+
+// ErrRenderNotSupported is returned (wrapped) by ToImage for pages of formats
+// that cannot be rendered to images.
+var ErrRenderNotSupported = errors.New("page rendering not supported")
+
+// ErrUnsupportedContentType is returned (wrapped) by Open for content types
+// without a registered opener.
+var ErrUnsupportedContentType = errors.New("unsupported content type")
+
 var formatRegistry = map[string]func(string) (Document, error){
 	"application/pdf": func(path string) (Document, error) {
 		return OpenPDF(path)
 	},
+	"application/vnd.openxmlformats-officedocument.wordprocessingml.document": func(path string) (Document, error) {
+		return OpenDOCX(path)
+	},
+	"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet": func(path string) (Document, error) {
+		return OpenXLSX(path)
+	},
+	"application/vnd.openxmlformats-officedocument.presentationml.presentation": func(path string) (Document, error) {
+		return OpenPPTX(path)
+	},
+	"application/epub+zip": func(path string) (Document, error) {
+		return OpenEPUB(path)
+	},
+	"message/rfc822": func(path string) (Document, error) {
+		return OpenEML(path)
+	},
+	"image/png": func(path string) (Document, error) {
+		return OpenImage(path)
+	},
+	"image/jpeg": func(path string) (Document, error) {
+		return OpenImage(path)
+	},
+	"image/gif": func(path string) (Document, error) {
+		return OpenImage(path)
+	},
+	"text/html": func(path string) (Document, error) {
+		return OpenHTML(path)
+	},
+	"text/csv": func(path string) (Document, error) {
+		return OpenCSV(path)
+	},
+	"text/tab-separated-values": func(path string) (Document, error) {
+		return OpenTSV(path)
+	},
 }
 
 func SupportedFormats() []string {
@@ -71,7 +232,7 @@ func IsSupported(contentType string) bool {
 func Open(path string, contentType string) (Document, error) {
 	opener, ok := formatRegistry[contentType]
 	if !ok {
-		return nil, fmt.Errorf("unsupported content type: %s", contentType)
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedContentType, contentType)
 	}
 	return opener(path)
 }
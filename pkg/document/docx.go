@@ -0,0 +1,947 @@
+package document
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/JaimeStill/document-context/pkg/cache"
+	"github.com/JaimeStill/document-context/pkg/image"
+)
+
+// DOCXDocument is a Word document read natively from its Office Open XML
+// package, without external converters.
+//
+// The document body is divided into pages at explicit page breaks, paragraphs
+// marked to start on a new page, and section breaks. Word lays out pages at
+// display time, so these pages follow the author's explicit breaks rather
+// than the printed pagination. A document without breaks is a single page.
+type DOCXDocument struct {
+	path        string
+	fingerprint string
+	pages       [][]docxBlock
+	footnotes   map[int][]docxBlock
+	mu          sync.Mutex
+}
+
+// docxInline is a run of paragraph text or a footnote reference.
+type docxInline struct {
+	text string
+	note int
+}
+
+// docxBlock is a paragraph or table of the document body.
+type docxBlock struct {
+	inlines []docxInline
+
+	// heading is the heading level (1-9), or 0 for body paragraphs.
+	heading int
+
+	// list is set for list items.
+	list *docxListItem
+
+	// table is set for tables, whose cells hold paragraphs.
+	table [][][]docxBlock
+}
+
+type docxListItem struct {
+	level   int
+	ordered bool
+	number  int
+}
+
+// OpenDOCX opens a Word (.docx) document.
+//
+// The package is parsed at open time: paragraphs, headings (from Title and
+// "heading N" styles or outline levels), bulleted and numbered lists, tables,
+// and footnotes. The fingerprint is the SHA-256 of the file.
+//
+// Returns an error if the file is not a readable DOCX package.
+func OpenDOCX(path string) (*DOCXDocument, error) {
+	pkg, err := openOOXML(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open DOCX: %w", err)
+	}
+	defer pkg.Close()
+
+	main, err := pkg.mainPart("word/document.xml")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open DOCX: %w", err)
+	}
+
+	parser := &docxParser{
+		counters:  make(map[string][]int),
+		noteOrder: make(map[string]int),
+	}
+
+	if err := parser.loadStyles(pkg, main); err != nil {
+		return nil, err
+	}
+	if err := parser.loadNumbering(pkg, main); err != nil {
+		return nil, err
+	}
+
+	data, err := pkg.read(main)
+	if err != nil {
+		return nil, err
+	}
+	pages, err := parser.parseBody(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse DOCX body: %w", err)
+	}
+
+	footnotes, err := parser.loadFootnotes(pkg, main)
+	if err != nil {
+		return nil, err
+	}
+
+	fingerprint, err := contentFingerprint(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DOCXDocument{
+		path:        path,
+		fingerprint: fingerprint,
+		pages:       pages,
+		footnotes:   footnotes,
+	}, nil
+}
+
+func (d *DOCXDocument) PageCount() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return len(d.pages)
+}
+
+// Fingerprint returns the SHA-256 of the document file computed at open time.
+func (d *DOCXDocument) Fingerprint() string {
+	return d.fingerprint
+}
+
+func (d *DOCXDocument) ExtractPage(pageNum int) (Page, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if pageNum < 1 || pageNum > len(d.pages) {
+		return nil, fmt.Errorf("page %d out of range [1-%d]", pageNum, len(d.pages))
+	}
+	return &DOCXPage{doc: d, number: pageNum}, nil
+}
+
+func (d *DOCXDocument) ExtractAllPages() ([]Page, error) {
+	pages := make([]Page, 0, d.PageCount())
+	for i := 1; i <= d.PageCount(); i++ {
+		page, err := d.ExtractPage(i)
+		if err != nil {
+			return nil, fmt.Errorf("failed to extract page %d: %w", i, err)
+		}
+		pages = append(pages, page)
+	}
+	return pages, nil
+}
+
+// Close releases the parsed document content.
+func (d *DOCXDocument) Close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.pages = nil
+	d.footnotes = nil
+	return nil
+}
+
+// DOCXPage is a page of a DOCXDocument.
+type DOCXPage struct {
+	doc    *DOCXDocument
+	number int
+}
+
+func (p *DOCXPage) Number() int {
+	return p.number
+}
+
+// ToImage is not supported for DOCX pages and returns an error wrapping
+// ErrRenderNotSupported.
+func (p *DOCXPage) ToImage(renderer image.Renderer, c cache.Cache) ([]byte, error) {
+	return nil, fmt.Errorf("DOCX page %d: %w", p.number, ErrRenderNotSupported)
+}
+
+// Text returns the page as plain text.
+//
+// Paragraphs are separated by newlines, list items keep their bullet or
+// number, table cells are separated by tabs, and footnote references appear
+// as [N] with the referenced footnotes appended after the page content.
+func (p *DOCXPage) Text() (string, error) {
+	return p.render(false)
+}
+
+// Markdown returns the page as Markdown.
+//
+// Headings use ATX markers, lists are indented by level, tables are rendered
+// as GitHub-flavored Markdown tables, and footnotes use [^N] references with
+// their definitions appended after the page content.
+func (p *DOCXPage) Markdown() (string, error) {
+	return p.render(true)
+}
+
+func (p *DOCXPage) render(markdown bool) (string, error) {
+	p.doc.mu.Lock()
+	defer p.doc.mu.Unlock()
+
+	if p.doc.pages == nil {
+		return "", fmt.Errorf("document is closed")
+	}
+
+	r := &docxRenderer{markdown: markdown}
+	var builder strings.Builder
+
+	blocks := p.doc.pages[p.number-1]
+	for i, block := range blocks {
+		if i > 0 {
+			if markdown && (blocks[i-1].list == nil || block.list == nil) {
+				builder.WriteString("\n\n")
+			} else {
+				builder.WriteString("\n")
+			}
+		}
+		builder.WriteString(r.block(block))
+	}
+
+	var notes []string
+	for _, n := range r.notes {
+		body := r.paragraphs(p.doc.footnotes[n], " ")
+		if markdown {
+			notes = append(notes, fmt.Sprintf("[^%d]: %s", n, body))
+		} else {
+			notes = append(notes, fmt.Sprintf("[%d] %s", n, body))
+		}
+	}
+	if len(notes) > 0 {
+		builder.WriteString("\n\n")
+		builder.WriteString(strings.Join(notes, "\n"))
+	}
+
+	return strings.TrimSpace(builder.String()), nil
+}
+
+// docxRenderer renders blocks as text or Markdown, collecting footnote
+// references in order of appearance.
+type docxRenderer struct {
+	markdown bool
+	notes    []int
+	seen     map[int]bool
+}
+
+func (r *docxRenderer) block(b docxBlock) string {
+	if b.table != nil {
+		return r.table(b.table)
+	}
+
+	text := r.inlines(b.inlines)
+
+	switch {
+	case b.heading > 0 && r.markdown:
+		return strings.Repeat("#", min(b.heading, 6)) + " " + text
+	case b.list != nil:
+		marker := "-"
+		if b.list.ordered {
+			marker = strconv.Itoa(b.list.number) + "."
+		}
+		return strings.Repeat("  ", b.list.level) + marker + " " + text
+	default:
+		return text
+	}
+}
+
+func (r *docxRenderer) inlines(inlines []docxInline) string {
+	var builder strings.Builder
+	for _, in := range inlines {
+		if in.note == 0 {
+			builder.WriteString(in.text)
+			continue
+		}
+
+		if r.seen == nil {
+			r.seen = make(map[int]bool)
+		}
+		if !r.seen[in.note] {
+			r.seen[in.note] = true
+			r.notes = append(r.notes, in.note)
+		}
+
+		if r.markdown {
+			fmt.Fprintf(&builder, "[^%d]", in.note)
+		} else {
+			fmt.Fprintf(&builder, "[%d]", in.note)
+		}
+	}
+	return builder.String()
+}
+
+func (r *docxRenderer) paragraphs(blocks []docxBlock, sep string) string {
+	texts := make([]string, 0, len(blocks))
+	for _, b := range blocks {
+		if text := r.block(b); text != "" {
+			texts = append(texts, text)
+		}
+	}
+	return strings.Join(texts, sep)
+}
+
+func (r *docxRenderer) table(rows [][][]docxBlock) string {
+	table := Table{Cells: make([][]Cell, len(rows))}
+	for i, row := range rows {
+		table.Cells[i] = make([]Cell, len(row))
+		for j, cell := range row {
+			table.Cells[i][j] = Cell{Text: r.paragraphs(cell, "\n")}
+		}
+	}
+
+	if r.markdown {
+		return strings.TrimSuffix(table.Markdown(), "\n")
+	}
+
+	lines := make([]string, len(table.Cells))
+	for i, row := range table.Rows() {
+		for j, cell := range row {
+			row[j] = strings.ReplaceAll(cell, "\n", " ")
+		}
+		lines[i] = strings.Join(row, "\t")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// docxStyle is the subset of a paragraph style relevant to structure.
+type docxStyle struct {
+	name    string
+	basedOn string
+	outline int
+	numID   string
+	ilvl    int
+}
+
+// docxLevel is a list level definition from the numbering part.
+type docxLevel struct {
+	format string
+	start  int
+}
+
+type docxParser struct {
+	styles    map[string]docxStyle
+	numbering map[string]map[int]docxLevel
+	counters  map[string][]int
+	noteOrder map[string]int
+}
+
+type valAttr struct {
+	Val string `xml:"val,attr"`
+}
+
+func (p *docxParser) loadStyles(pkg *ooxmlPackage, main string) error {
+	name, err := pkg.related(main, "/styles")
+	if err != nil || name == "" {
+		return err
+	}
+
+	data, err := pkg.read(name)
+	if err != nil {
+		return err
+	}
+
+	var styles struct {
+		Styles []struct {
+			ID      string  `xml:"styleId,attr"`
+			Name    valAttr `xml:"name"`
+			BasedOn valAttr `xml:"basedOn"`
+			PPr     struct {
+				Outline *valAttr `xml:"outlineLvl"`
+				NumPr   struct {
+					Ilvl  valAttr `xml:"ilvl"`
+					NumID valAttr `xml:"numId"`
+				} `xml:"numPr"`
+			} `xml:"pPr"`
+		} `xml:"style"`
+	}
+	if err := xml.Unmarshal(data, &styles); err != nil {
+		return fmt.Errorf("failed to parse DOCX styles: %w", err)
+	}
+
+	p.styles = make(map[string]docxStyle, len(styles.Styles))
+	for _, s := range styles.Styles {
+		style := docxStyle{
+			name:    s.Name.Val,
+			basedOn: s.BasedOn.Val,
+			outline: -1,
+			numID:   s.PPr.NumPr.NumID.Val,
+		}
+		if s.PPr.Outline != nil {
+			style.outline, _ = strconv.Atoi(s.PPr.Outline.Val)
+		}
+		style.ilvl, _ = strconv.Atoi(s.PPr.NumPr.Ilvl.Val)
+		p.styles[s.ID] = style
+	}
+	return nil
+}
+
+func (p *docxParser) loadNumbering(pkg *ooxmlPackage, main string) error {
+	name, err := pkg.related(main, "/numbering")
+	if err != nil || name == "" {
+		return err
+	}
+
+	data, err := pkg.read(name)
+	if err != nil {
+		return err
+	}
+
+	var numbering struct {
+		Abstract []struct {
+			ID     string `xml:"abstractNumId,attr"`
+			Levels []struct {
+				Ilvl   int      `xml:"ilvl,attr"`
+				Start  *valAttr `xml:"start"`
+				Format valAttr  `xml:"numFmt"`
+			} `xml:"lvl"`
+		} `xml:"abstractNum"`
+		Nums []struct {
+			ID       string  `xml:"numId,attr"`
+			Abstract valAttr `xml:"abstractNumId"`
+		} `xml:"num"`
+	}
+	if err := xml.Unmarshal(data, &numbering); err != nil {
+		return fmt.Errorf("failed to parse DOCX numbering: %w", err)
+	}
+
+	abstract := make(map[string]map[int]docxLevel, len(numbering.Abstract))
+	for _, a := range numbering.Abstract {
+		levels := make(map[int]docxLevel, len(a.Levels))
+		for _, l := range a.Levels {
+			level := docxLevel{format: l.Format.Val, start: 1}
+			if l.Start != nil {
+				level.start, _ = strconv.Atoi(l.Start.Val)
+			}
+			levels[l.Ilvl] = level
+		}
+		abstract[a.ID] = levels
+	}
+
+	p.numbering = make(map[string]map[int]docxLevel, len(numbering.Nums))
+	for _, n := range numbering.Nums {
+		p.numbering[n.ID] = abstract[n.Abstract.Val]
+	}
+	return nil
+}
+
+// loadFootnotes parses the footnotes part, keyed by the display numbers
+// assigned to references while parsing the body. Footnotes that are never
+// referenced are omitted.
+func (p *docxParser) loadFootnotes(pkg *ooxmlPackage, main string) (map[int][]docxBlock, error) {
+	footnotes := make(map[int][]docxBlock)
+
+	name, err := pkg.related(main, "/footnotes")
+	if err != nil || name == "" {
+		return footnotes, err
+	}
+
+	data, err := pkg.read(name)
+	if err != nil {
+		return nil, err
+	}
+
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		tok, err := nextToken(dec)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse DOCX footnotes: %w", err)
+		}
+
+		start, ok := tok.(xml.StartElement)
+		if !ok || start.Name.Local != "footnote" {
+			continue
+		}
+
+		number, referenced := p.noteOrder[attr(start, "id")]
+		if !referenced {
+			if err := dec.Skip(); err != nil {
+				return nil, fmt.Errorf("failed to parse DOCX footnotes: %w", err)
+			}
+			continue
+		}
+
+		blocks, err := p.parseContainer(dec)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse DOCX footnotes: %w", err)
+		}
+		footnotes[number] = blocks
+	}
+
+	return footnotes, nil
+}
+
+// nextToken returns the next token, skipping the fallback branches of markup
+// compatibility blocks so alternate representations are not read twice.
+func nextToken(dec *xml.Decoder) (xml.Token, error) {
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		if start, ok := tok.(xml.StartElement); ok && start.Name.Local == "Fallback" {
+			if err := dec.Skip(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		return tok, nil
+	}
+}
+
+// parseBody parses the document part into pages of blocks.
+func (p *docxParser) parseBody(data []byte) ([][]docxBlock, error) {
+	var pages [][]docxBlock
+	var current []docxBlock
+	newPage := func() {
+		if len(current) > 0 {
+			pages = append(pages, current)
+			current = nil
+		}
+	}
+
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		tok, err := nextToken(dec)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		switch start.Name.Local {
+		case "p":
+			para, err := p.parseParagraph(dec)
+			if err != nil {
+				return nil, err
+			}
+			if para.breakBefore {
+				newPage()
+			}
+			for i, segment := range para.segments {
+				if i > 0 {
+					newPage()
+				}
+				if block, ok := p.paragraphBlock(para, segment); ok {
+					current = append(current, block)
+				}
+			}
+			if para.sectionBreak {
+				newPage()
+			}
+		case "tbl":
+			table, err := p.parseTable(dec)
+			if err != nil {
+				return nil, err
+			}
+			current = append(current, docxBlock{table: table})
+		case "sectPr":
+			if err := dec.Skip(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	newPage()
+
+	if len(pages) == 0 {
+		pages = append(pages, nil)
+	}
+	return pages, nil
+}
+
+// docxParagraph is a parsed paragraph. Segments are separated by page breaks.
+type docxParagraph struct {
+	segments     [][]docxInline
+	style        string
+	numID        string
+	ilvl         int
+	hasNum       bool
+	outline      int
+	breakBefore  bool
+	sectionBreak bool
+}
+
+// parseParagraph parses a w:p element whose start tag has been consumed.
+// Nested paragraphs (e.g., in text boxes) contribute their text inline.
+func (p *docxParser) parseParagraph(dec *xml.Decoder) (*docxParagraph, error) {
+	para := &docxParagraph{segments: [][]docxInline{nil}, outline: -1}
+	add := func(in docxInline) {
+		last := &para.segments[len(para.segments)-1]
+		*last = append(*last, in)
+	}
+
+	depth, inText, inRun := 1, false, 0
+	for depth > 0 {
+		tok, err := nextToken(dec)
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			depth++
+			switch t.Name.Local {
+			case "pStyle":
+				para.style = attr(t, "val")
+			case "ilvl":
+				para.ilvl, _ = strconv.Atoi(attr(t, "val"))
+				para.hasNum = true
+			case "numId":
+				para.numID = attr(t, "val")
+				para.hasNum = true
+			case "outlineLvl":
+				para.outline, _ = strconv.Atoi(attr(t, "val"))
+			case "pageBreakBefore":
+				para.breakBefore = isOn(attr(t, "val"))
+			case "sectPr":
+				var sect struct {
+					Type valAttr `xml:"type"`
+				}
+				if err := dec.DecodeElement(&sect, &t); err != nil {
+					return nil, err
+				}
+				depth--
+				para.sectionBreak = sect.Type.Val != "continuous"
+			case "rPr":
+				if err := dec.Skip(); err != nil {
+					return nil, err
+				}
+				depth--
+			case "r":
+				inRun++
+			case "t":
+				inText = inRun > 0
+			case "tab":
+				if inRun > 0 {
+					add(docxInline{text: "\t"})
+				}
+			case "br":
+				if inRun > 0 {
+					if attr(t, "type") == "page" {
+						para.segments = append(para.segments, nil)
+					} else {
+						add(docxInline{text: "\n"})
+					}
+				}
+			case "cr":
+				if inRun > 0 {
+					add(docxInline{text: "\n"})
+				}
+			case "noBreakHyphen":
+				add(docxInline{text: "-"})
+			case "footnoteReference":
+				add(docxInline{note: p.noteNumber(attr(t, "id"))})
+			}
+		case xml.EndElement:
+			depth--
+			switch t.Name.Local {
+			case "r":
+				inRun--
+			case "t":
+				inText = false
+			}
+		case xml.CharData:
+			if inText {
+				add(docxInline{text: string(t)})
+			}
+		}
+	}
+
+	return para, nil
+}
+
+func isOn(val string) bool {
+	return val != "0" && val != "false" && val != "off"
+}
+
+// noteNumber returns the display number of a footnote, assigning numbers in
+// order of first reference.
+func (p *docxParser) noteNumber(id string) int {
+	if n, ok := p.noteOrder[id]; ok {
+		return n
+	}
+	n := len(p.noteOrder) + 1
+	p.noteOrder[id] = n
+	return n
+}
+
+// paragraphBlock builds a block from a paragraph segment, resolving heading
+// levels and list numbering from the paragraph and its style. Empty segments
+// produce no block.
+func (p *docxParser) paragraphBlock(para *docxParagraph, segment []docxInline) (docxBlock, bool) {
+	empty := true
+	for _, in := range segment {
+		if in.note != 0 || strings.TrimSpace(in.text) != "" {
+			empty = false
+			break
+		}
+	}
+	if empty {
+		return docxBlock{}, false
+	}
+
+	block := docxBlock{inlines: trimInlines(segment)}
+
+	outline := para.outline
+	numID, ilvl, hasNum := para.numID, para.ilvl, para.hasNum
+
+	for id, depth := para.style, 0; id != "" && depth < 10; depth++ {
+		style, ok := p.styles[id]
+		if !ok {
+			style = docxStyle{name: id, outline: -1}
+		}
+
+		name := strings.ToLower(style.name)
+		switch {
+		case block.heading == 0 && name == "title":
+			block.heading = 1
+		case block.heading == 0 && strings.HasPrefix(name, "heading"):
+			if n, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(name, "heading"))); err == nil && n > 0 {
+				block.heading = n
+			}
+		}
+		if outline < 0 {
+			outline = style.outline
+		}
+		if !hasNum && style.numID != "" {
+			numID, ilvl, hasNum = style.numID, style.ilvl, true
+		}
+
+		id = style.basedOn
+	}
+
+	if block.heading == 0 && outline >= 0 && outline < 9 {
+		block.heading = outline + 1
+	}
+
+	if hasNum && numID != "" && numID != "0" && block.heading == 0 {
+		block.list = p.listItem(numID, ilvl)
+	}
+
+	return block, true
+}
+
+// listItem returns the list item for a paragraph at level ilvl of list numID,
+// advancing the list's counters.
+func (p *docxParser) listItem(numID string, ilvl int) *docxListItem {
+	ilvl = max(0, min(ilvl, 8))
+
+	level, ok := p.numbering[numID][ilvl]
+	if !ok {
+		level = docxLevel{format: "bullet", start: 1}
+	}
+
+	counters := p.counters[numID]
+	if len(counters) <= ilvl {
+		counters = append(counters, make([]int, ilvl+1-len(counters))...)
+	}
+	if counters[ilvl] == 0 {
+		counters[ilvl] = level.start
+	} else {
+		counters[ilvl]++
+	}
+	for i := ilvl + 1; i < len(counters); i++ {
+		counters[i] = 0
+	}
+	p.counters[numID] = counters
+
+	return &docxListItem{
+		level:   ilvl,
+		ordered: level.format != "bullet" && level.format != "none",
+		number:  counters[ilvl],
+	}
+}
+
+// trimInlines removes leading and trailing whitespace from a paragraph.
+func trimInlines(inlines []docxInline) []docxInline {
+	out := append([]docxInline(nil), inlines...)
+	if n := len(out); n > 0 && out[0].note == 0 {
+		out[0].text = strings.TrimLeft(out[0].text, " \t\n")
+	}
+	if n := len(out); n > 0 && out[n-1].note == 0 {
+		out[n-1].text = strings.TrimRight(out[n-1].text, " \t\n")
+	}
+	return out
+}
+
+// parseTable parses a w:tbl element whose start tag has been consumed into
+// rows of cells. Horizontally merged cells are padded with empty cells so
+// every row spans the full grid.
+func (p *docxParser) parseTable(dec *xml.Decoder) ([][][]docxBlock, error) {
+	var rows [][][]docxBlock
+
+	depth := 1
+	for depth > 0 {
+		tok, err := nextToken(dec)
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "tr":
+				rows = append(rows, nil)
+				depth++
+			case "tc":
+				cell, span, err := p.parseCell(dec)
+				if err != nil {
+					return nil, err
+				}
+				if len(rows) == 0 {
+					rows = append(rows, nil)
+				}
+				row := &rows[len(rows)-1]
+				*row = append(*row, cell)
+				for range span - 1 {
+					*row = append(*row, nil)
+				}
+			case "tblPr", "tblGrid", "trPr":
+				if err := dec.Skip(); err != nil {
+					return nil, err
+				}
+			default:
+				depth++
+			}
+		case xml.EndElement:
+			depth--
+		}
+	}
+
+	width := 0
+	for _, row := range rows {
+		width = max(width, len(row))
+	}
+	for i := range rows {
+		for len(rows[i]) < width {
+			rows[i] = append(rows[i], nil)
+		}
+	}
+
+	return rows, nil
+}
+
+// parseCell parses a w:tc element whose start tag has been consumed,
+// returning its paragraphs and horizontal span. Nested tables are flattened
+// into one paragraph per row.
+func (p *docxParser) parseCell(dec *xml.Decoder) ([]docxBlock, int, error) {
+	var blocks []docxBlock
+	span := 1
+
+	depth := 1
+	for depth > 0 {
+		tok, err := nextToken(dec)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			depth++
+			switch t.Name.Local {
+			case "gridSpan":
+				if n, err := strconv.Atoi(attr(t, "val")); err == nil && n > 1 {
+					span = n
+				}
+			case "p":
+				para, err := p.parseParagraph(dec)
+				if err != nil {
+					return nil, 0, err
+				}
+				depth--
+				for _, segment := range para.segments {
+					if block, ok := p.paragraphBlock(para, segment); ok {
+						blocks = append(blocks, block)
+					}
+				}
+			case "tbl":
+				table, err := p.parseTable(dec)
+				if err != nil {
+					return nil, 0, err
+				}
+				depth--
+				for _, row := range table {
+					var inlines []docxInline
+					for _, cell := range row {
+						for _, b := range cell {
+							if len(inlines) > 0 {
+								inlines = append(inlines, docxInline{text: " "})
+							}
+							inlines = append(inlines, b.inlines...)
+						}
+					}
+					if len(inlines) > 0 {
+						blocks = append(blocks, docxBlock{inlines: inlines})
+					}
+				}
+			}
+		case xml.EndElement:
+			depth--
+		}
+	}
+
+	return blocks, span, nil
+}
+
+// parseContainer parses the paragraphs and tables of an element whose start
+// tag has been consumed, such as a footnote.
+func (p *docxParser) parseContainer(dec *xml.Decoder) ([]docxBlock, error) {
+	var blocks []docxBlock
+
+	depth := 1
+	for depth > 0 {
+		tok, err := nextToken(dec)
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "p":
+				para, err := p.parseParagraph(dec)
+				if err != nil {
+					return nil, err
+				}
+				for _, segment := range para.segments {
+					if block, ok := p.paragraphBlock(para, segment); ok {
+						blocks = append(blocks, block)
+					}
+				}
+			case "tbl":
+				table, err := p.parseTable(dec)
+				if err != nil {
+					return nil, err
+				}
+				blocks = append(blocks, docxBlock{table: table})
+			default:
+				depth++
+			}
+		case xml.EndElement:
+			depth--
+		}
+	}
+
+	return blocks, nil
+}
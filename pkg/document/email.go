@@ -0,0 +1,676 @@
+package document
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/JaimeStill/document-context/pkg/cache"
+	"github.com/JaimeStill/document-context/pkg/config"
+	"github.com/JaimeStill/document-context/pkg/image"
+)
+
+const (
+	// maxMessageSize bounds the size of an email message read into memory.
+	maxMessageSize = 256 << 20
+
+	// maxMIMEDepth bounds the nesting of multipart bodies within a message.
+	maxMIMEDepth = 32
+)
+
+// EmailDocument is an email message (.eml, RFC 5322 with MIME).
+//
+// The message headers and body form a single text page. Attachments are not
+// pages of the message; they are child documents returned by Attachments,
+// opened through the format registry, so attached messages yield their own
+// attachments in turn.
+type EmailDocument struct {
+	path        string
+	fingerprint string
+	header      EmailHeader
+	body        string
+	parts       []emailPart
+
+	// budget and depth are the archive limits attachments are opened
+	// under: those of the enclosing archive, or of the message itself
+	// when it was opened directly.
+	budget *archiveBudget
+	depth  int
+
+	attachments []Attachment
+	opened      bool
+	tmpDir      string
+	closed      bool
+	mu          sync.Mutex
+}
+
+// EmailHeader holds the decoded headers of an email message.
+type EmailHeader struct {
+	From      string
+	To        []string
+	Cc        []string
+	Subject   string
+	Date      time.Time
+	MessageID string
+}
+
+// Attachment is a file attached to an email message.
+type Attachment struct {
+	// Filename is the attachment's file name, or a generated name
+	// ("attachment-N") when the message does not provide one.
+	Filename string
+
+	// ContentType is the detected content type (see DetectContentType).
+	ContentType string
+
+	// Size is the decoded size in bytes.
+	Size int
+
+	// Document is the attachment opened through the format registry, or nil
+	// if it could not be opened.
+	Document Document
+
+	// Err reports why Document is nil, wrapping ErrUnsupportedContentType
+	// for formats without a registered opener.
+	Err error
+}
+
+// emailPart is a decoded attachment of a message.
+type emailPart struct {
+	filename    string
+	contentType string
+	data        []byte
+}
+
+// OpenEML opens an email message in RFC 5322 format with MIME bodies.
+//
+// The message is parsed at open time. Headers are decoded (including RFC 2047
+// encoded words), and the body is the first text/plain part, or the first
+// text/html part converted to text when there is no plain-text
+// alternative. Parts with an attachment disposition, named parts, inline
+// non-text parts (e.g., embedded images), and attached messages
+// (message/rfc822) become attachments.
+//
+// Attachments are opened under the default ArchiveConfig; use
+// OpenEMLWithConfig to set the limits.
+//
+// Returns an error if the file cannot be read or is not a valid message.
+func OpenEML(path string) (*EmailDocument, error) {
+	return OpenEMLWithConfig(path, config.DefaultArchiveConfig())
+}
+
+// OpenEMLWithConfig opens an email message whose attachments are opened
+// under the archive limits of cfg.
+//
+// The limits apply to the whole tree of documents below the message, as for
+// OpenZIPWithConfig: every attachment, including those of attached messages
+// and the entries of attached archives, counts toward the entry and total
+// size limits, and attached archives are nested one level below the
+// message. Attached messages share the limits rather than starting afresh.
+//
+// Returns an error if the file cannot be read or is not a valid message.
+func OpenEMLWithConfig(path string, cfg config.ArchiveConfig) (*EmailDocument, error) {
+	cfg.Finalize()
+	return openEML(path, &archiveBudget{cfg: cfg}, 0)
+}
+
+// openEML opens a message whose attachments are charged to budget, found
+// at the given archive nesting depth (0 outside any archive).
+func openEML(path string, budget *archiveBudget, depth int) (*EmailDocument, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open email: %w", err)
+	}
+	if info.Size() > maxMessageSize {
+		return nil, fmt.Errorf("email exceeds %d bytes", maxMessageSize)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read email: %w", err)
+	}
+
+	msg, err := mail.ReadMessage(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse email: %w", err)
+	}
+
+	body, err := io.ReadAll(msg.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read email body: %w", err)
+	}
+
+	parser := &emailParser{}
+	if err := parser.walk(textproto.MIMEHeader(msg.Header), body, 0); err != nil {
+		return nil, fmt.Errorf("failed to parse email body: %w", err)
+	}
+
+	fingerprint, err := contentFingerprint(path)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &EmailDocument{
+		path:        path,
+		fingerprint: fingerprint,
+		header:      parseEmailHeader(msg.Header),
+		parts:       parser.parts,
+		budget:      budget,
+		depth:       depth,
+	}
+
+	switch {
+	case parser.plain != nil:
+		doc.body = *parser.plain
+	case parser.html != nil:
+		doc.body = htmlToText(*parser.html)
+	}
+
+	return doc, nil
+}
+
+func (d *EmailDocument) PageCount() int {
+	return 1
+}
+
+// Fingerprint returns the SHA-256 of the message file computed at open time.
+func (d *EmailDocument) Fingerprint() string {
+	return d.fingerprint
+}
+
+// Header returns the decoded message headers.
+func (d *EmailDocument) Header() EmailHeader {
+	return d.header
+}
+
+func (d *EmailDocument) ExtractPage(pageNum int) (Page, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.closed {
+		return nil, fmt.Errorf("document is closed")
+	}
+	if pageNum != 1 {
+		return nil, fmt.Errorf("page %d out of range [1-1]", pageNum)
+	}
+	return &EmailPage{doc: d}, nil
+}
+
+func (d *EmailDocument) ExtractAllPages() ([]Page, error) {
+	page, err := d.ExtractPage(1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract page 1: %w", err)
+	}
+	return []Page{page}, nil
+}
+
+// Attachments returns the message attachments in message order, each opened
+// through the format registry.
+//
+// Attachments are written to a temporary directory and opened on the first
+// call; later calls return the same documents. An attachment that cannot be
+// opened (e.g., an unsupported format) has a nil Document and a non-nil Err,
+// and does not fail the call. Attached documents are closed, and the
+// temporary files removed, when the message is closed.
+//
+// Attachments are charged against the archive limits the message was
+// opened under (see OpenEMLWithConfig), which for a message inside an
+// archive are the limits of the outermost archive. Attached archives and
+// messages are opened under the same limits rather than the defaults of
+// the format registry, so nesting cannot be used to avoid them.
+//
+// Returns an error if the document is closed, the attachments cannot be
+// written, or a limit is exceeded (wrapping ErrArchiveLimit).
+func (d *EmailDocument) Attachments() ([]Attachment, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.closed {
+		return nil, fmt.Errorf("document is closed")
+	}
+	if d.opened {
+		return d.attachments, nil
+	}
+	if len(d.parts) == 0 {
+		d.opened = true
+		return nil, nil
+	}
+
+	tmpDir, err := os.MkdirTemp("", "email-attachments-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create attachment directory: %w", err)
+	}
+
+	attachments := make([]Attachment, 0, len(d.parts))
+	for i, part := range d.parts {
+		a := Attachment{Filename: part.filename, Size: len(part.data)}
+		if a.Filename == "" {
+			a.Filename = fmt.Sprintf("attachment-%d", i+1)
+			if part.contentType == "message/rfc822" {
+				a.Filename += ".eml"
+			}
+		}
+		a.ContentType = DetectContentType(a.Filename, part.contentType, part.data)
+
+		if err := d.budget.addEntry(); err != nil {
+			closeAttachments(attachments)
+			os.RemoveAll(tmpDir)
+			return nil, fmt.Errorf("attachment %q: %w", a.Filename, err)
+		}
+		if err := d.budget.addSize(int64(len(part.data))); err != nil {
+			closeAttachments(attachments)
+			os.RemoveAll(tmpDir)
+			return nil, fmt.Errorf("attachment %q: %w", a.Filename, err)
+		}
+
+		// Each attachment gets its own directory so file names never
+		// collide while keeping the original name for cache filenames.
+		dir := filepath.Join(tmpDir, fmt.Sprint(i+1))
+		path := filepath.Join(dir, safeFilename(a.Filename))
+		if err := os.Mkdir(dir, 0o700); err != nil {
+			closeAttachments(attachments)
+			os.RemoveAll(tmpDir)
+			return nil, fmt.Errorf("failed to write attachment %q: %w", a.Filename, err)
+		}
+		if err := os.WriteFile(path, part.data, 0o600); err != nil {
+			closeAttachments(attachments)
+			os.RemoveAll(tmpDir)
+			return nil, fmt.Errorf("failed to write attachment %q: %w", a.Filename, err)
+		}
+
+		a.Document, a.Err = openNested(path, a.ContentType, d.budget, d.depth)
+		if errors.Is(a.Err, ErrArchiveLimit) {
+			closeAttachments(attachments)
+			os.RemoveAll(tmpDir)
+			return nil, fmt.Errorf("attachment %q: %w", a.Filename, a.Err)
+		}
+		if a.Err != nil {
+			a.Document = nil
+			a.Err = fmt.Errorf("attachment %q: %w", a.Filename, a.Err)
+		}
+		attachments = append(attachments, a)
+	}
+
+	d.tmpDir = tmpDir
+	d.attachments = attachments
+	d.opened = true
+	return attachments, nil
+}
+
+// Close closes attached documents, removes their temporary files, and
+// releases the message content.
+func (d *EmailDocument) Close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.closed {
+		return nil
+	}
+	d.closed = true
+
+	err := closeAttachments(d.attachments)
+	if d.tmpDir != "" {
+		if removeErr := os.RemoveAll(d.tmpDir); err == nil {
+			err = removeErr
+		}
+	}
+
+	d.attachments = nil
+	d.parts = nil
+	d.body = ""
+	return err
+}
+
+func closeAttachments(attachments []Attachment) error {
+	var first error
+	for _, a := range attachments {
+		if a.Document == nil {
+			continue
+		}
+		if err := a.Document.Close(); err != nil && first == nil {
+			first = fmt.Errorf("failed to close attachment %q: %w", a.Filename, err)
+		}
+	}
+	return first
+}
+
+// safeFilename reduces a file name from a message to a single path element.
+func safeFilename(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	if name == "." || name == ".." || name == "/" {
+		return "attachment"
+	}
+	return name
+}
+
+// EmailPage is the single page of an EmailDocument: the message headers and
+// body.
+type EmailPage struct {
+	doc *EmailDocument
+}
+
+func (p *EmailPage) Number() int {
+	return 1
+}
+
+// Text returns the message headers (From, To, Cc, Date, Subject), a line
+// listing attachment file names, a blank line, and the body text.
+func (p *EmailPage) Text() (string, error) {
+	d := p.doc
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.closed {
+		return "", fmt.Errorf("document is closed")
+	}
+
+	var builder strings.Builder
+	writeField := func(name, value string) {
+		if value != "" {
+			fmt.Fprintf(&builder, "%s: %s\n", name, value)
+		}
+	}
+
+	h := d.header
+	writeField("From", h.From)
+	writeField("To", strings.Join(h.To, ", "))
+	writeField("Cc", strings.Join(h.Cc, ", "))
+	if !h.Date.IsZero() {
+		writeField("Date", h.Date.Format(time.RFC1123Z))
+	}
+	writeField("Subject", h.Subject)
+
+	if len(d.parts) > 0 {
+		names := make([]string, len(d.parts))
+		for i, part := range d.parts {
+			names[i] = part.filename
+			if names[i] == "" {
+				names[i] = fmt.Sprintf("attachment-%d", i+1)
+			}
+		}
+		writeField("Attachments", strings.Join(names, ", "))
+	}
+
+	if d.body != "" {
+		builder.WriteString("\n")
+		builder.WriteString(d.body)
+	}
+
+	return strings.TrimSpace(builder.String()), nil
+}
+
+// ToImage renders the message page text (see Text) as an image.
+//
+// The text is laid out as a single-page PDF as for EPUB pages, the page
+// growing taller with the body, then rendered through renderer. Caching
+// follows PDFPage.ToImage, with keys derived from the message fingerprint.
+// Attachments are rendered through their own documents.
+func (p *EmailPage) ToImage(renderer image.Renderer, c cache.Cache) ([]byte, error) {
+	return imageData(p.Render(renderer, c))
+}
+
+// ToBudgetImage renders the page like ToImage and returns the image with the
+// encoding it was rendered with (see Render).
+func (p *EmailPage) ToBudgetImage(renderer image.Renderer, c cache.Cache) (*BudgetImage, error) {
+	return budgetImage(p.Render(renderer, c))
+}
+
+// Render renders the page like ToImage and returns the image with its
+// metadata (see PDFPage.Render).
+func (p *EmailPage) Render(renderer image.Renderer, c cache.Cache) (*RenderResult, error) {
+	dpi, err := p.renderDPI(renderer)
+	if err != nil {
+		return nil, err
+	}
+
+	key := imageCacheKeyAt(p.doc.fingerprint, 1, renderer, dpi)
+	filename := imageFilename(p.doc.path, 1, renderer.Settings().Format)
+
+	return renderCached(c, key, filename, renderer, dpi, func() (*BudgetImage, error) {
+		pdf, err := p.pdf()
+		if err != nil {
+			return nil, err
+		}
+		return renderPDFData(renderer, pdf, 1, dpi)
+	})
+}
+
+// WriteImage renders the page like ToImage and writes the image to w,
+// streaming it from the renderer when possible (see StreamPage).
+func (p *EmailPage) WriteImage(w io.Writer, renderer image.Renderer, c cache.Cache) error {
+	return writeImage(w, renderer, c, func(s image.StreamRenderer) error {
+		dpi, err := p.renderDPI(renderer)
+		if err != nil {
+			return err
+		}
+		pdf, err := p.pdf()
+		if err != nil {
+			return err
+		}
+		return streamPDFData(w, s, pdf, 1, dpi)
+	}, func() (*RenderResult, error) {
+		return p.Render(renderer, c)
+	})
+}
+
+// pdf lays out the page text as the single-page PDF rendered by ToImage.
+func (p *EmailPage) pdf() (pagePDF, error) {
+	text, err := p.Text()
+	if err != nil {
+		return pagePDF{}, err
+	}
+	return writeTextPDF(text), nil
+}
+
+// renderDPI returns the DPI at which renderer renders the page: fitted to the
+// generated page when the renderer has fit limits (see config.ImageConfig),
+// otherwise the configured DPI.
+func (p *EmailPage) renderDPI(renderer image.Renderer) (float64, error) {
+	if !image.HasFitLimits(renderer.Settings()) {
+		return float64(renderer.Settings().DPI), nil
+	}
+
+	pdf, err := p.pdf()
+	if err != nil {
+		return 0, err
+	}
+	return fitDPI(renderer, pdf.width, pdf.height)
+}
+
+var headerDecoder = &mime.WordDecoder{CharsetReader: charsetReader}
+
+func parseEmailHeader(h mail.Header) EmailHeader {
+	header := EmailHeader{
+		To:        decodeAddresses(h.Get("To")),
+		Cc:        decodeAddresses(h.Get("Cc")),
+		Subject:   decodeHeader(h.Get("Subject")),
+		MessageID: strings.Trim(h.Get("Message-Id"), "<> "),
+	}
+	if from := decodeAddresses(h.Get("From")); len(from) > 0 {
+		header.From = from[0]
+	}
+	if date, err := h.Date(); err == nil {
+		header.Date = date
+	}
+	return header
+}
+
+func decodeHeader(value string) string {
+	decoded, err := headerDecoder.DecodeHeader(value)
+	if err != nil {
+		return strings.TrimSpace(value)
+	}
+	return strings.TrimSpace(decoded)
+}
+
+// decodeAddresses formats an address list header as "Name <address>"
+// entries, falling back to the decoded raw value when it does not parse.
+func decodeAddresses(value string) []string {
+	if strings.TrimSpace(value) == "" {
+		return nil
+	}
+
+	parser := mail.AddressParser{WordDecoder: headerDecoder}
+	list, err := parser.ParseList(value)
+	if err != nil {
+		return []string{decodeHeader(value)}
+	}
+
+	addresses := make([]string, len(list))
+	for i, a := range list {
+		if a.Name != "" {
+			addresses[i] = a.Name + " <" + a.Address + ">"
+		} else {
+			addresses[i] = a.Address
+		}
+	}
+	return addresses
+}
+
+// emailParser collects the body alternatives and attachments of a MIME tree.
+type emailParser struct {
+	plain *string
+	html  *string
+	parts []emailPart
+}
+
+func (p *emailParser) walk(header textproto.MIMEHeader, body []byte, depth int) error {
+	if depth > maxMIMEDepth {
+		return fmt.Errorf("MIME nesting exceeds %d levels", maxMIMEDepth)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		mediaType, params = "text/plain", map[string]string{}
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		reader := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+		for {
+			part, err := reader.NextRawPart()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			data, err := io.ReadAll(part)
+			if err != nil {
+				return err
+			}
+			if err := p.walk(part.Header, data, depth+1); err != nil {
+				return err
+			}
+		}
+	}
+
+	data, err := decodeTransfer(header.Get("Content-Transfer-Encoding"), body)
+	if err != nil {
+		return err
+	}
+
+	disposition, dispParams, _ := mime.ParseMediaType(header.Get("Content-Disposition"))
+	filename := dispParams["filename"]
+	if filename == "" {
+		filename = params["name"]
+	}
+	filename = decodeHeader(filename)
+
+	isText := mediaType == "text/plain" || mediaType == "text/html"
+	if disposition != "attachment" && filename == "" && isText {
+		text := decodeCharset(data, params["charset"])
+		if mediaType == "text/plain" && p.plain == nil {
+			p.plain = &text
+		} else if mediaType == "text/html" && p.html == nil {
+			p.html = &text
+		}
+		return nil
+	}
+
+	p.parts = append(p.parts, emailPart{filename: filename, contentType: mediaType, data: data})
+	return nil
+}
+
+func decodeTransfer(encoding string, data []byte) ([]byte, error) {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "base64":
+		decoded, err := io.ReadAll(base64.NewDecoder(base64.StdEncoding, bytes.NewReader(bytes.TrimSpace(data))))
+		if err != nil {
+			return nil, fmt.Errorf("invalid base64 content: %w", err)
+		}
+		return decoded, nil
+	case "quoted-printable":
+		decoded, err := io.ReadAll(quotedprintable.NewReader(bytes.NewReader(data)))
+		if err != nil {
+			return nil, fmt.Errorf("invalid quoted-printable content: %w", err)
+		}
+		return decoded, nil
+	default:
+		return data, nil
+	}
+}
+
+// decodeCharset converts text in the given charset to UTF-8. Latin-1 and
+// Windows-1252 are converted byte for byte; other charsets are assumed to be
+// UTF-8 compatible.
+func decodeCharset(data []byte, charset string) string {
+	switch strings.ToLower(charset) {
+	case "iso-8859-1", "latin1", "windows-1252", "cp1252":
+		runes := make([]rune, len(data))
+		for i, b := range data {
+			runes[i] = rune(b)
+		}
+		return string(runes)
+	default:
+		if !utf8.Valid(data) {
+			return strings.ToValidUTF8(string(data), "�")
+		}
+		return string(data)
+	}
+}
+
+func charsetReader(charset string, input io.Reader) (io.Reader, error) {
+	data, err := io.ReadAll(input)
+	if err != nil {
+		return nil, err
+	}
+	return strings.NewReader(decodeCharset(data, charset)), nil
+}
+
+var (
+	htmlHidden = regexp.MustCompile(`(?is)<(script|style|head)\b.*?</(script|style|head)\s*>`)
+	htmlBreak  = regexp.MustCompile(`(?i)<br\s*/?>|</(p|div|tr|li|h[1-6]|table|blockquote)\s*>`)
+	htmlTag    = regexp.MustCompile(`(?s)<[^>]*>`)
+	blankLines = regexp.MustCompile(`\n{3,}`)
+)
+
+// htmlToText reduces an HTML body to plain text: scripts and styles are
+// removed, block ends become line breaks, tags are stripped, and entities
+// are decoded.
+func htmlToText(s string) string {
+	s = htmlHidden.ReplaceAllString(s, "")
+	s = htmlBreak.ReplaceAllString(s, "\n")
+	s = htmlTag.ReplaceAllString(s, "")
+	s = html.UnescapeString(s)
+
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = strings.Join(strings.Fields(line), " ")
+	}
+	return strings.TrimSpace(blankLines.ReplaceAllString(strings.Join(lines, "\n"), "\n\n"))
+}
@@ -0,0 +1,533 @@
+package document
+
+import (
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/JaimeStill/document-context/pkg/cache"
+	"github.com/JaimeStill/document-context/pkg/config"
+	"github.com/JaimeStill/document-context/pkg/image"
+)
+
+// OutlineEntry is an entry of a document outline (table of contents).
+type OutlineEntry struct {
+	// Title is the entry label.
+	Title string
+
+	// Level is the nesting depth of the entry, starting at 1.
+	Level int
+
+	// Target is the referenced content document path within the package,
+	// including any fragment (e.g., "OEBPS/ch02.xhtml#sec3").
+	Target string
+
+	// Page is the first page of the target content document, or 0 if the
+	// target is not part of the reading order.
+	Page int
+}
+
+// EPUBDocument is an EPUB e-book whose pages are the chapters of its reading
+// order (the OPF spine), split into chunks of bounded length.
+type EPUBDocument struct {
+	path        string
+	fingerprint string
+	renderKey   string
+	title       string
+	pages       []epubPage
+	outline     []OutlineEntry
+	mu          sync.Mutex
+}
+
+// epubPage is the text of a page and the spine item it belongs to.
+type epubPage struct {
+	chapter int
+	href    string
+	text    string
+}
+
+// OpenEPUB opens an EPUB document using the default EPUBConfig.
+func OpenEPUB(path string) (*EPUBDocument, error) {
+	return OpenEPUBWithConfig(path, config.DefaultEPUBConfig())
+}
+
+// OpenEPUBWithConfig opens an EPUB 2 or EPUB 3 e-book.
+//
+// Configuration is finalized (defaults applied) before use. The package
+// document is located through META-INF/container.xml, and the XHTML content
+// documents of its spine are read in reading order. Each chapter's readable
+// text is extracted as for HTML documents (headings marked with their level,
+// list items bulleted) and split into pages of at most cfg.MaxChars
+// characters at paragraph boundaries; chapters without text produce a single
+// empty page so every chapter remains addressable.
+//
+// The outline is read from the EPUB 3 navigation document (its "toc" nav) or,
+// for EPUB 2, from the NCX referenced by the spine. The fingerprint is the
+// SHA-256 of the file.
+//
+// Returns an error if the file is not a readable EPUB package.
+func OpenEPUBWithConfig(path string, cfg config.EPUBConfig) (*EPUBDocument, error) {
+	cfg.Finalize()
+
+	pkg, err := openOOXML(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open EPUB: %w", err)
+	}
+	defer pkg.Close()
+
+	opfPath, err := epubRootfile(pkg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open EPUB: %w", err)
+	}
+
+	data, err := pkg.read(opfPath)
+	if err != nil {
+		return nil, err
+	}
+	opf, err := parseXML(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", opfPath, err)
+	}
+
+	manifest := make(map[string]*xmlNode)
+	if m := opf.child("manifest"); m != nil {
+		for i := range m.Nodes {
+			if item := &m.Nodes[i]; item.XMLName.Local == "item" {
+				manifest[item.attr("id")] = item
+			}
+		}
+	}
+
+	doc := &EPUBDocument{
+		path:  path,
+		title: strings.TrimSpace(opf.child("metadata", "title").textContent()),
+	}
+
+	chapterPages := make(map[string]int)
+	spine := opf.child("spine")
+	if spine != nil {
+		chapter := 0
+		for _, ref := range spine.Nodes {
+			item := manifest[ref.attr("idref")]
+			if ref.XMLName.Local != "itemref" || item == nil || !isXHTML(item.attr("media-type")) {
+				continue
+			}
+
+			href := epubResolve(opfPath, item.attr("href"))
+			content, err := pkg.read(href)
+			if err != nil {
+				return nil, err
+			}
+
+			chapter++
+			chapterPages[href] = len(doc.pages) + 1
+			for _, text := range chunkText(htmlReadableText(decodeHTML(content)), cfg.MaxChars) {
+				doc.pages = append(doc.pages, epubPage{chapter: chapter, href: href, text: text})
+			}
+		}
+	}
+
+	if len(doc.pages) == 0 {
+		return nil, fmt.Errorf("EPUB has no content documents in its spine")
+	}
+
+	if doc.outline, err = readEPUBOutline(pkg, opfPath, manifest, spine); err != nil {
+		return nil, err
+	}
+	for i, entry := range doc.outline {
+		target, _, _ := strings.Cut(entry.Target, "#")
+		doc.outline[i].Page = chapterPages[target]
+	}
+
+	if doc.fingerprint, err = contentFingerprint(path); err != nil {
+		return nil, err
+	}
+	// Pages depend on the chunk size as well as the content, so rendered
+	// pages are keyed by both.
+	doc.renderKey = fmt.Sprintf("%s/epub?max_chars=%d", doc.fingerprint, cfg.MaxChars)
+	return doc, nil
+}
+
+// epubRootfile returns the package document path named by the container.
+func epubRootfile(pkg *ooxmlPackage) (string, error) {
+	data, err := pkg.read("META-INF/container.xml")
+	if err != nil {
+		return "", err
+	}
+	container, err := parseXML(data)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse container: %w", err)
+	}
+
+	rootfile := container.child("rootfiles", "rootfile").attr("full-path")
+	if rootfile == "" {
+		return "", fmt.Errorf("container names no package document")
+	}
+	return strings.TrimPrefix(rootfile, "/"), nil
+}
+
+// epubResolve resolves an href relative to the package part base, keeping
+// any fragment.
+func epubResolve(base, href string) string {
+	href, fragment, hasFragment := strings.Cut(href, "#")
+	if unescaped, err := url.PathUnescape(href); err == nil {
+		href = unescaped
+	}
+	resolved := path.Join(path.Dir(base), href)
+	if hasFragment {
+		resolved += "#" + fragment
+	}
+	return resolved
+}
+
+func isXHTML(mediaType string) bool {
+	return mediaType == "application/xhtml+xml" || mediaType == "text/html"
+}
+
+// chunkText splits readable text into pages of at most maxChars characters,
+// breaking between paragraphs and, where possible, keeping headings with the
+// paragraph that follows. Paragraphs longer than maxChars are broken between
+// words.
+func chunkText(text string, maxChars int) []string {
+	var blocks []string
+	for _, block := range strings.Split(text, "\n\n") {
+		if utf8.RuneCountInString(block) > maxChars {
+			blocks = append(blocks, wrapWords(block, maxChars)...)
+		} else {
+			blocks = append(blocks, block)
+		}
+	}
+
+	var chunks []string
+	var current []string
+	size := 0
+	for _, block := range blocks {
+		n := utf8.RuneCountInString(block)
+		if len(current) > 0 && size+2+n > maxChars {
+			// Move a trailing heading to the next page when it fits there
+			// with the block it introduces.
+			last := current[len(current)-1]
+			carried := utf8.RuneCountInString(last)
+			if len(current) > 1 && headingLevel(last) > 0 && carried+2+n <= maxChars {
+				chunks = append(chunks, strings.Join(current[:len(current)-1], "\n\n"))
+				current, size = []string{last}, carried
+			} else {
+				chunks = append(chunks, strings.Join(current, "\n\n"))
+				current, size = nil, 0
+			}
+		}
+		if len(current) > 0 {
+			size += 2
+		}
+		current = append(current, block)
+		size += n
+	}
+	return append(chunks, strings.Join(current, "\n\n"))
+}
+
+// navNode is an element of a navigation document, keeping its raw content
+// for labels with inline markup.
+type navNode struct {
+	XMLName xml.Name
+	Attrs   []xml.Attr `xml:",any,attr"`
+	Inner   string     `xml:",innerxml"`
+	Nodes   []navNode  `xml:",any"`
+}
+
+func (n *navNode) attr(local string) string {
+	for _, a := range n.Attrs {
+		if a.Name.Local == local {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// children returns the child elements with the given local name.
+func (n *navNode) children(local string) []*navNode {
+	var nodes []*navNode
+	for i := range n.Nodes {
+		if n.Nodes[i].XMLName.Local == local {
+			nodes = append(nodes, &n.Nodes[i])
+		}
+	}
+	return nodes
+}
+
+// find returns the first descendant element (or n itself) for which match
+// returns true, or nil.
+func (n *navNode) find(match func(*navNode) bool) *navNode {
+	if match(n) {
+		return n
+	}
+	for i := range n.Nodes {
+		if found := n.Nodes[i].find(match); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// label returns the text of the element with markup removed.
+func (n *navNode) label() string {
+	return strings.Join(strings.Fields(html.UnescapeString(htmlTag.ReplaceAllString(n.Inner, ""))), " ")
+}
+
+// parseNav parses a navigation document leniently, accepting HTML entities
+// and unclosed void elements.
+func parseNav(data []byte) (*navNode, error) {
+	dec := xml.NewDecoder(strings.NewReader(string(data)))
+	dec.Strict = false
+	dec.AutoClose = xml.HTMLAutoClose
+	dec.Entity = xml.HTMLEntity
+	dec.CharsetReader = charsetReader
+
+	var root navNode
+	if err := dec.Decode(&root); err != nil {
+		return nil, err
+	}
+	return &root, nil
+}
+
+// readEPUBOutline reads the table of contents from the EPUB 3 navigation
+// document, falling back to the EPUB 2 NCX. A package without either has an
+// empty outline.
+func readEPUBOutline(pkg *ooxmlPackage, opfPath string, manifest map[string]*xmlNode, spine *xmlNode) ([]OutlineEntry, error) {
+	var navHref, ncxHref string
+	for _, item := range manifest {
+		if slices.Contains(strings.Fields(item.attr("properties")), "nav") {
+			navHref = epubResolve(opfPath, item.attr("href"))
+		}
+	}
+	if item := manifest[spine.attr("toc")]; item != nil {
+		ncxHref = epubResolve(opfPath, item.attr("href"))
+	}
+
+	var outline []OutlineEntry
+	switch {
+	case navHref != "":
+		data, err := pkg.read(navHref)
+		if err != nil {
+			return nil, err
+		}
+		root, err := parseNav(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", navHref, err)
+		}
+
+		nav := root.find(func(n *navNode) bool {
+			return n.XMLName.Local == "nav" && slices.Contains(strings.Fields(n.attr("type")), "toc")
+		})
+		if nav == nil {
+			nav = root.find(func(n *navNode) bool { return n.XMLName.Local == "nav" })
+		}
+		if nav != nil {
+			for _, ol := range nav.children("ol") {
+				outline = navList(outline, ol, navHref, 1)
+			}
+		}
+
+	case ncxHref != "":
+		data, err := pkg.read(ncxHref)
+		if err != nil {
+			return nil, err
+		}
+		root, err := parseNav(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", ncxHref, err)
+		}
+		for _, navMap := range root.children("navMap") {
+			outline = navPoints(outline, navMap, ncxHref, 1)
+		}
+	}
+
+	return outline, nil
+}
+
+// navList appends the entries of an EPUB 3 navigation list.
+func navList(outline []OutlineEntry, ol *navNode, base string, level int) []OutlineEntry {
+	for _, li := range ol.children("li") {
+		for _, label := range li.Nodes {
+			switch label.XMLName.Local {
+			case "a":
+				outline = append(outline, OutlineEntry{
+					Title:  label.label(),
+					Level:  level,
+					Target: epubResolve(base, label.attr("href")),
+				})
+			case "span":
+				outline = append(outline, OutlineEntry{Title: label.label(), Level: level})
+			}
+		}
+		for _, nested := range li.children("ol") {
+			outline = navList(outline, nested, base, level+1)
+		}
+	}
+	return outline
+}
+
+// navPoints appends the entries of an EPUB 2 NCX navigation map.
+func navPoints(outline []OutlineEntry, parent *navNode, base string, level int) []OutlineEntry {
+	for _, point := range parent.children("navPoint") {
+		entry := OutlineEntry{Level: level}
+		for _, label := range point.children("navLabel") {
+			for _, text := range label.children("text") {
+				entry.Title = text.label()
+			}
+		}
+		for _, content := range point.children("content") {
+			entry.Target = epubResolve(base, content.attr("src"))
+		}
+		outline = append(outline, entry)
+		outline = navPoints(outline, point, base, level+1)
+	}
+	return outline
+}
+
+func (d *EPUBDocument) PageCount() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return len(d.pages)
+}
+
+// Fingerprint returns the SHA-256 of the EPUB file computed at open time.
+func (d *EPUBDocument) Fingerprint() string {
+	return d.fingerprint
+}
+
+// Title returns the publication title from the package metadata, or an
+// empty string.
+func (d *EPUBDocument) Title() string {
+	return d.title
+}
+
+// Outline returns the table of contents in document order. Entries of nested
+// lists follow their parent with a greater Level.
+func (d *EPUBDocument) Outline() []OutlineEntry {
+	return slices.Clone(d.outline)
+}
+
+func (d *EPUBDocument) ExtractPage(pageNum int) (Page, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if pageNum < 1 || pageNum > len(d.pages) {
+		return nil, fmt.Errorf("page %d out of range [1-%d]", pageNum, len(d.pages))
+	}
+	return &EPUBPage{doc: d, number: pageNum, page: d.pages[pageNum-1]}, nil
+}
+
+func (d *EPUBDocument) ExtractAllPages() ([]Page, error) {
+	pages := make([]Page, 0, d.PageCount())
+	for i := 1; i <= d.PageCount(); i++ {
+		page, err := d.ExtractPage(i)
+		if err != nil {
+			return nil, fmt.Errorf("failed to extract page %d: %w", i, err)
+		}
+		pages = append(pages, page)
+	}
+	return pages, nil
+}
+
+// Close releases the parsed document content.
+func (d *EPUBDocument) Close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.pages = nil
+	d.outline = nil
+	return nil
+}
+
+// EPUBPage is a page of an EPUBDocument: a chapter, or a chunk of one.
+type EPUBPage struct {
+	doc    *EPUBDocument
+	number int
+	page   epubPage
+}
+
+func (p *EPUBPage) Number() int {
+	return p.number
+}
+
+// Chapter returns the position of the page's chapter in the reading order,
+// starting at 1.
+func (p *EPUBPage) Chapter() int {
+	return p.page.chapter
+}
+
+// Href returns the path of the page's content document within the package.
+func (p *EPUBPage) Href() string {
+	return p.page.href
+}
+
+// Text returns the readable text of the page, with headings on their own
+// lines marked with their level ("## Heading").
+func (p *EPUBPage) Text() (string, error) {
+	return p.page.text, nil
+}
+
+// ToImage renders the page text as an image.
+//
+// The text is laid out as a single-page PDF (headings in bold, paragraphs
+// wrapped to a Letter-width page), then rendered through renderer. Caching
+// follows PDFPage.ToImage, with keys derived from the EPUB fingerprint, the
+// MaxChars the book was paginated with, and the page number.
+func (p *EPUBPage) ToImage(renderer image.Renderer, c cache.Cache) ([]byte, error) {
+	return imageData(p.Render(renderer, c))
+}
+
+// ToBudgetImage renders the page like ToImage and returns the image with the
+// encoding it was rendered with (see Render).
+func (p *EPUBPage) ToBudgetImage(renderer image.Renderer, c cache.Cache) (*BudgetImage, error) {
+	return budgetImage(p.Render(renderer, c))
+}
+
+// Render renders the page like ToImage and returns the image with its
+// metadata (see PDFPage.Render).
+func (p *EPUBPage) Render(renderer image.Renderer, c cache.Cache) (*RenderResult, error) {
+	dpi, err := p.renderDPI(renderer)
+	if err != nil {
+		return nil, err
+	}
+
+	key := imageCacheKeyAt(p.doc.renderKey, p.number, renderer, dpi)
+	filename := imageFilename(p.doc.path, p.number, renderer.Settings().Format)
+
+	return renderCached(c, key, filename, renderer, dpi, func() (*BudgetImage, error) {
+		return renderPDFData(renderer, writeTextPDF(p.page.text), p.number, dpi)
+	})
+}
+
+// WriteImage renders the page like ToImage and writes the image to w,
+// streaming it from the renderer when possible (see StreamPage).
+func (p *EPUBPage) WriteImage(w io.Writer, renderer image.Renderer, c cache.Cache) error {
+	return writeImage(w, renderer, c, func(s image.StreamRenderer) error {
+		dpi, err := p.renderDPI(renderer)
+		if err != nil {
+			return err
+		}
+		return streamPDFData(w, s, writeTextPDF(p.page.text), p.number, dpi)
+	}, func() (*RenderResult, error) {
+		return p.Render(renderer, c)
+	})
+}
+
+// renderDPI returns the DPI at which renderer renders the page: fitted to the
+// generated page, which grows taller with the text, when the renderer has fit
+// limits (see config.ImageConfig), otherwise the configured DPI.
+func (p *EPUBPage) renderDPI(renderer image.Renderer) (float64, error) {
+	if !image.HasFitLimits(renderer.Settings()) {
+		return float64(renderer.Settings().DPI), nil
+	}
+
+	pdf := writeTextPDF(p.page.text)
+	return fitDPI(renderer, pdf.width, pdf.height)
+}
@@ -0,0 +1,154 @@
+package document
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/JaimeStill/document-context/pkg/cache"
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+)
+
+// WritePDF writes a standalone PDF containing the selected pages to w.
+//
+// Pages are written in the order given and may repeat. Resources shared by the
+// selected pages (fonts, images) are carried over; resources used only by
+// unselected pages are dropped.
+//
+// Parameters:
+//   - w: Destination for the PDF bytes
+//   - pages: 1-indexed page numbers to include (at least one)
+//
+// Returns an error if no pages are selected, a page is out of range, the
+// document is closed, or the PDF cannot be written.
+func (d *PDFDocument) WritePDF(w io.Writer, pages ...int) error {
+	if err := d.validatePages(pages); err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	closed := d.ctx == nil
+	d.mu.Unlock()
+	if closed {
+		return fmt.Errorf("document is closed")
+	}
+
+	f, err := os.Open(d.source())
+	if err != nil {
+		return fmt.Errorf("failed to open PDF: %w", err)
+	}
+	defer f.Close()
+
+	selected := make([]string, len(pages))
+	for i, n := range pages {
+		selected[i] = strconv.Itoa(n)
+	}
+
+	pdfcpuLog.RLock()
+	err = api.Collect(f, w, selected, nil)
+	pdfcpuLog.RUnlock()
+	if err != nil {
+		return fmt.Errorf("failed to extract pages %s: %w", pageList(pages, ","), err)
+	}
+
+	return nil
+}
+
+// ExtractPDF returns a standalone PDF containing the selected pages.
+//
+// This method supports optional caching in the same way as PDFPage.ToImage. If
+// a cache is provided and contains the extracted PDF, it returns the cached
+// data immediately. Otherwise, it writes the PDF and stores the result.
+//
+// Parameters:
+//   - pages: 1-indexed page numbers to include, in output order
+//   - c: Optional cache for storing extracted PDFs. Pass nil to disable caching.
+//
+// The cache key is generated from the document fingerprint and the ordered page
+// selection, so the same excerpt of the same content always shares an entry.
+//
+// Returns the PDF bytes, or an error if extraction or caching fails.
+func (d *PDFDocument) ExtractPDF(pages []int, c cache.Cache) ([]byte, error) {
+	if err := d.validatePages(pages); err != nil {
+		return nil, err
+	}
+
+	key := d.buildPDFCacheKey(pages)
+
+	if c != nil {
+		entry, err := c.Get(key)
+		if err == nil {
+			return entry.Data, nil
+		}
+		if !errors.Is(err, cache.ErrCacheEntryNotFound) {
+			return nil, err
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := d.WritePDF(&buf, pages...); err != nil {
+		return nil, err
+	}
+	data := buf.Bytes()
+
+	if c != nil {
+		baseName := filepath.Base(d.path)
+		nameWithoutExt := strings.TrimSuffix(baseName, filepath.Ext(baseName))
+
+		entry := &cache.CacheEntry{
+			Key:      key,
+			Data:     data,
+			Filename: fmt.Sprintf("%s.%s.pdf", nameWithoutExt, pageList(pages, "-")),
+		}
+
+		if err := c.Set(entry); err != nil {
+			return nil, err
+		}
+	}
+
+	return data, nil
+}
+
+// ToPDF returns a standalone single-page PDF containing this page.
+//
+// Parameters:
+//   - c: Optional cache for storing the extracted PDF. Pass nil to disable caching.
+//
+// Returns the PDF bytes, or an error if extraction or caching fails.
+func (p *PDFPage) ToPDF(c cache.Cache) ([]byte, error) {
+	return p.doc.ExtractPDF([]int{p.number}, c)
+}
+
+func (d *PDFDocument) validatePages(pages []int) error {
+	if len(pages) == 0 {
+		return fmt.Errorf("no pages selected")
+	}
+	for _, n := range pages {
+		if n < 1 || n > d.pageCount {
+			return fmt.Errorf("page %d out of range [1-%d]", n, d.pageCount)
+		}
+	}
+	return nil
+}
+
+// buildPDFCacheKey generates a deterministic cache key for a page selection.
+//
+// Key format (before hashing):
+//
+//	sha256:9f86d081.../pages=1,3,5.pdf
+func (d *PDFDocument) buildPDFCacheKey(pages []int) string {
+	return cache.GenerateKey(fmt.Sprintf("%s/pages=%s.pdf", d.fingerprint, pageList(pages, ",")))
+}
+
+func pageList(pages []int, sep string) string {
+	parts := make([]string, len(pages))
+	for i, n := range pages {
+		parts[i] = strconv.Itoa(n)
+	}
+	return strings.Join(parts, sep)
+}
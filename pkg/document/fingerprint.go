@@ -0,0 +1,108 @@
+package document
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/JaimeStill/document-context/pkg/config"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+// contentFingerprint computes the SHA-256 hash of the file at path.
+//
+// The returned fingerprint is prefixed with the mode that produced it
+// ("sha256:<hex>") so fingerprints from different modes never collide.
+func contentFingerprint(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open document for fingerprint: %w", err)
+	}
+	defer f.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return "", fmt.Errorf("failed to hash document: %w", err)
+	}
+
+	return "sha256:" + hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// dataFingerprint computes the content fingerprint of document bytes already
+// in memory, matching contentFingerprint for the same bytes.
+func dataFingerprint(data []byte) string {
+	hash := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(hash[:])
+}
+
+// fastFingerprint derives a fingerprint from the PDF trailer ID array, file
+// size and modification time without reading the full document.
+//
+// When the document has no ID array, the absolute path is used in its place so
+// the fingerprint remains stable for an unchanged file.
+func fastFingerprint(path string, ctx *model.Context) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to stat document for fingerprint: %w", err)
+	}
+
+	identity := pdfID(ctx)
+	if identity == "" {
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return "", fmt.Errorf("failed to normalize path: %w", err)
+		}
+		identity = abs
+	}
+
+	input := fmt.Sprintf("%s|%d|%d", identity, info.Size(), info.ModTime().UnixNano())
+	hash := sha256.Sum256([]byte(input))
+
+	return "fast:" + hex.EncodeToString(hash[:]), nil
+}
+
+// pdfID returns the hex encoded trailer ID array of a PDF, or an empty string
+// if the document does not declare one.
+func pdfID(ctx *model.Context) string {
+	if ctx == nil || ctx.XRefTable == nil || len(ctx.XRefTable.ID) == 0 {
+		return ""
+	}
+
+	var id []byte
+	for _, o := range ctx.XRefTable.ID {
+		switch v := o.(type) {
+		case types.HexLiteral:
+			b, err := v.Bytes()
+			if err != nil {
+				return ""
+			}
+			id = append(id, b...)
+		case types.StringLiteral:
+			b, err := types.Unescape(v.Value())
+			if err != nil {
+				return ""
+			}
+			id = append(id, b...)
+		}
+	}
+
+	return hex.EncodeToString(id)
+}
+
+// fingerprintPDF computes the fingerprint of a PDF using the configured mode.
+// Content fingerprints hash data, the bytes the document was parsed from, so
+// the file is not read again.
+func fingerprintPDF(path string, data []byte, ctx *model.Context, mode config.FingerprintMode) (string, error) {
+	switch mode {
+	case config.FingerprintContent:
+		return dataFingerprint(data), nil
+	case config.FingerprintFast:
+		return fastFingerprint(path, ctx)
+	default:
+		return "", fmt.Errorf("unsupported fingerprint mode: %s", mode)
+	}
+}
@@ -0,0 +1,492 @@
+package document
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+// pdfFont holds the subset of font information needed to decode shown strings
+// into Unicode text and to estimate glyph advances.
+type pdfFont struct {
+	name         string
+	composite    bool
+	toUnicode    map[string]string
+	differences  map[byte]rune
+	widths       map[int]float64
+	defaultWidth float64
+	builtinTeX   bool
+}
+
+// glyph is a single decoded character code from a shown string.
+type glyph struct {
+	code  int
+	text  string
+	width float64
+	space bool
+}
+
+// decode splits a shown string into glyphs using the font's code width
+// (two bytes for composite fonts, one byte otherwise).
+func (f *pdfFont) decode(s []byte) []glyph {
+	step := 1
+	if f.composite {
+		step = 2
+	}
+
+	glyphs := make([]glyph, 0, len(s)/step)
+	for i := 0; i+step <= len(s); i += step {
+		raw := s[i : i+step]
+		code := int(raw[0])
+		if step == 2 {
+			code = int(raw[0])<<8 | int(raw[1])
+		}
+
+		glyphs = append(glyphs, glyph{
+			code:  code,
+			text:  f.unicode(raw, code),
+			width: f.width(code),
+			space: step == 1 && code == 32,
+		})
+	}
+	return glyphs
+}
+
+func (f *pdfFont) width(code int) float64 {
+	if w, ok := f.widths[code]; ok {
+		return w / 1000
+	}
+	return f.defaultWidth / 1000
+}
+
+func (f *pdfFont) unicode(raw []byte, code int) string {
+	if f.toUnicode != nil {
+		if s, ok := f.toUnicode[string(raw)]; ok {
+			return s
+		}
+	}
+
+	if f.composite {
+		if code < 32 {
+			return ""
+		}
+		return string(rune(code))
+	}
+
+	b := byte(code)
+	if r, ok := f.differences[b]; ok {
+		return string(r)
+	}
+
+	if f.builtinTeX {
+		if s, ok := texLigatures[b]; ok {
+			return s
+		}
+	}
+
+	if r, ok := winAnsiHigh[b]; ok {
+		return string(r)
+	}
+
+	if b < 32 {
+		return ""
+	}
+
+	return string(rune(b))
+}
+
+// texLigatures maps the OT1 codes TeX text fonts use for ligatures and dashes
+// when the font declares no encoding. Typewriter fonts use ASCII positions and
+// are excluded.
+var texLigatures = map[byte]string{
+	0x0B: "ff",
+	0x0C: "fi",
+	0x0D: "fl",
+	0x0E: "ffi",
+	0x0F: "ffl",
+	0x7B: "–",
+	0x7C: "—",
+}
+
+// winAnsiHigh maps the WinAnsiEncoding code points that differ from Latin-1.
+var winAnsiHigh = map[byte]rune{
+	0x80: '€', 0x82: '‚', 0x83: 'ƒ', 0x84: '„',
+	0x85: '…', 0x86: '†', 0x87: '‡', 0x88: 'ˆ',
+	0x89: '‰', 0x8A: 'Š', 0x8B: '‹', 0x8C: 'Œ',
+	0x8E: 'Ž', 0x91: '‘', 0x92: '’', 0x93: '“',
+	0x94: '”', 0x95: '•', 0x96: '–', 0x97: '—',
+	0x98: '˜', 0x99: '™', 0x9A: 'š', 0x9B: '›',
+	0x9C: 'œ', 0x9E: 'ž', 0x9F: 'Ÿ',
+}
+
+// glyphNames maps common Adobe glyph names used in /Differences arrays that
+// cannot be derived from the name itself.
+var glyphNames = map[string]rune{
+	"space": ' ', "exclam": '!', "quotedbl": '"', "numbersign": '#',
+	"dollar": '$', "percent": '%', "ampersand": '&', "quotesingle": '\'',
+	"parenleft": '(', "parenright": ')', "asterisk": '*', "plus": '+',
+	"comma": ',', "hyphen": '-', "period": '.', "slash": '/',
+	"zero": '0', "one": '1', "two": '2', "three": '3', "four": '4',
+	"five": '5', "six": '6', "seven": '7', "eight": '8', "nine": '9',
+	"colon": ':', "semicolon": ';', "less": '<', "equal": '=',
+	"greater": '>', "question": '?', "at": '@', "bracketleft": '[',
+	"backslash": '\\', "bracketright": ']', "asciicircum": '^',
+	"underscore": '_', "grave": '`', "braceleft": '{', "bar": '|',
+	"braceright": '}', "asciitilde": '~', "bullet": '•',
+	"endash": '–', "emdash": '—', "quoteleft": '‘',
+	"quoteright": '’', "quotedblleft": '“', "quotedblright": '”',
+	"ellipsis": '…', "fi": 'ﬁ', "fl": 'ﬂ', "ff": 'ﬀ',
+	"ffi": 'ﬃ', "ffl": 'ﬄ', "dotlessi": 'ı',
+	"copyright": '©', "registered": '®', "trademark": '™',
+	"degree": '°', "section": '§', "paragraph": '¶',
+	"minus": '−', "multiply": '×', "divide": '÷',
+}
+
+// glyphRune resolves an Adobe glyph name to a rune.
+func glyphRune(name string) (rune, bool) {
+	if r, ok := glyphNames[name]; ok {
+		return r, true
+	}
+	if len(name) == 1 {
+		return rune(name[0]), true
+	}
+	if strings.HasPrefix(name, "uni") && len(name) == 7 {
+		if v, err := strconv.ParseUint(name[3:], 16, 32); err == nil {
+			return rune(v), true
+		}
+	}
+	return 0, false
+}
+
+// fontCache resolves and caches fonts for a resource dictionary.
+type fontCache struct {
+	ctx   *model.Context
+	fonts map[string]*pdfFont
+}
+
+func newFontCache(ctx *model.Context) *fontCache {
+	return &fontCache{
+		ctx:   ctx,
+		fonts: make(map[string]*pdfFont),
+	}
+}
+
+// lookup returns the font registered under name in resources, loading
+// indirectly referenced fonts once. Unresolvable fonts fall back to a generic single-byte font so
+// that extraction continues.
+func (fc *fontCache) lookup(resources types.Dict, name string) *pdfFont {
+	var fontRef types.Object
+	if fonts := fc.dict(resources, "Font"); fonts != nil {
+		fontRef, _ = fonts.Find(name)
+	}
+
+	ref, ok := fontRef.(types.IndirectRef)
+	if !ok {
+		return fc.load(fontRef)
+	}
+
+	if f, ok := fc.fonts[ref.String()]; ok {
+		return f
+	}
+
+	f := fc.load(fontRef)
+	fc.fonts[ref.String()] = f
+	return f
+}
+
+func (fc *fontCache) dict(d types.Dict, key string) types.Dict {
+	if d == nil {
+		return nil
+	}
+	o, ok := d.Find(key)
+	if !ok {
+		return nil
+	}
+	o, err := fc.ctx.Dereference(o)
+	if err != nil {
+		return nil
+	}
+	dict, _ := o.(types.Dict)
+	return dict
+}
+
+func (fc *fontCache) load(ref types.Object) *pdfFont {
+	font := &pdfFont{defaultWidth: 500}
+
+	o, err := fc.ctx.Dereference(ref)
+	if err != nil {
+		return font
+	}
+	d, ok := o.(types.Dict)
+	if !ok {
+		return font
+	}
+
+	if n := d.NameEntry("BaseFont"); n != nil {
+		font.name = *n
+		if i := strings.IndexByte(font.name, '+'); i == 6 {
+			font.name = font.name[i+1:]
+		}
+	}
+
+	subtype := ""
+	if n := d.NameEntry("Subtype"); n != nil {
+		subtype = *n
+	}
+
+	if tu, ok := d.Find("ToUnicode"); ok {
+		font.toUnicode = fc.loadToUnicode(tu)
+	}
+
+	if subtype == "Type0" {
+		font.composite = true
+		font.defaultWidth = 1000
+		fc.loadCIDWidths(d, font)
+		return font
+	}
+
+	fc.loadSimpleWidths(d, font)
+	fc.loadEncoding(d, font)
+
+	isTeX := strings.HasPrefix(font.name, "CM") || strings.HasPrefix(font.name, "LM")
+	if isTeX && !strings.HasPrefix(font.name, "CMTT") {
+		if _, ok := d.Find("Encoding"); !ok {
+			font.builtinTeX = true
+		}
+	}
+
+	return font
+}
+
+func (fc *fontCache) number(o types.Object) (float64, bool) {
+	v, err := fc.ctx.DereferenceNumber(o)
+	return v, err == nil
+}
+
+func (fc *fontCache) array(o types.Object) types.Array {
+	arr, err := fc.ctx.DereferenceArray(o)
+	if err != nil {
+		return nil
+	}
+	return arr
+}
+
+func (fc *fontCache) loadSimpleWidths(d types.Dict, font *pdfFont) {
+	if desc := fc.dict(d, "FontDescriptor"); desc != nil {
+		if mw, ok := desc.Find("MissingWidth"); ok {
+			if v, ok := fc.number(mw); ok && v > 0 {
+				font.defaultWidth = v
+			}
+		}
+	}
+
+	first := 0
+	if fcObj, ok := d.Find("FirstChar"); ok {
+		if v, ok := fc.number(fcObj); ok {
+			first = int(v)
+		}
+	}
+
+	wObj, ok := d.Find("Widths")
+	if !ok {
+		if strings.Contains(font.name, "Courier") {
+			font.defaultWidth = 600
+		}
+		return
+	}
+
+	widths := fc.array(wObj)
+	font.widths = make(map[int]float64, len(widths))
+	for i, w := range widths {
+		if v, ok := fc.number(w); ok {
+			font.widths[first+i] = v
+		}
+	}
+}
+
+func (fc *fontCache) loadCIDWidths(d types.Dict, font *pdfFont) {
+	descendants := fc.array(d["DescendantFonts"])
+	if len(descendants) == 0 {
+		return
+	}
+	o, err := fc.ctx.Dereference(descendants[0])
+	if err != nil {
+		return
+	}
+	cid, ok := o.(types.Dict)
+	if !ok {
+		return
+	}
+
+	if dw, ok := cid.Find("DW"); ok {
+		if v, ok := fc.number(dw); ok {
+			font.defaultWidth = v
+		}
+	}
+
+	wObj, ok := cid.Find("W")
+	if !ok {
+		return
+	}
+
+	w := fc.array(wObj)
+	font.widths = make(map[int]float64)
+	for i := 0; i < len(w); {
+		start, ok := fc.number(w[i])
+		if !ok || i+1 >= len(w) {
+			return
+		}
+		if list := fc.array(w[i+1]); list != nil {
+			for j, v := range list {
+				if width, ok := fc.number(v); ok {
+					font.widths[int(start)+j] = width
+				}
+			}
+			i += 2
+			continue
+		}
+		if i+2 >= len(w) {
+			return
+		}
+		end, ok1 := fc.number(w[i+1])
+		width, ok2 := fc.number(w[i+2])
+		if ok1 && ok2 {
+			for c := int(start); c <= int(end); c++ {
+				font.widths[c] = width
+			}
+		}
+		i += 3
+	}
+}
+
+func (fc *fontCache) loadEncoding(d types.Dict, font *pdfFont) {
+	enc := fc.dict(d, "Encoding")
+	if enc == nil {
+		return
+	}
+
+	diffs := fc.array(enc["Differences"])
+	if len(diffs) == 0 {
+		return
+	}
+
+	font.differences = make(map[byte]rune)
+	code := 0
+	for _, item := range diffs {
+		switch v := item.(type) {
+		case types.Integer:
+			code = v.Value()
+		case types.Name:
+			if r, ok := glyphRune(v.Value()); ok && code >= 0 && code < 256 {
+				font.differences[byte(code)] = r
+			}
+			code++
+		}
+	}
+}
+
+// loadToUnicode parses a ToUnicode CMap stream (bfchar and bfrange sections).
+func (fc *fontCache) loadToUnicode(o types.Object) map[string]string {
+	sd, _, err := fc.ctx.DereferenceStreamDict(o)
+	if err != nil || sd == nil {
+		return nil
+	}
+	if err := sd.Decode(); err != nil {
+		return nil
+	}
+	return parseToUnicode(sd.Content)
+}
+
+func parseToUnicode(data []byte) map[string]string {
+	result := make(map[string]string)
+	lexer := newContentLexer(data)
+
+	var operands []contentToken
+	for tok := lexer.next(); tok != nil; tok = lexer.next() {
+		op, isOp := tok.(pdfOperator)
+		if !isOp {
+			operands = append(operands, tok)
+			continue
+		}
+
+		switch op {
+		case "endbfchar":
+			for i := 0; i+1 < len(operands); i += 2 {
+				src, ok1 := operands[i].([]byte)
+				dst, ok2 := operands[i+1].([]byte)
+				if ok1 && ok2 {
+					result[string(src)] = decodeUTF16(dst)
+				}
+			}
+		case "endbfrange":
+			for i := 0; i+2 < len(operands); i += 3 {
+				lo, ok1 := operands[i].([]byte)
+				hi, ok2 := operands[i+1].([]byte)
+				if !ok1 || !ok2 || len(lo) != len(hi) {
+					continue
+				}
+				addBFRange(result, lo, hi, operands[i+2])
+			}
+		}
+		operands = operands[:0]
+	}
+
+	return result
+}
+
+func addBFRange(result map[string]string, lo, hi []byte, dst contentToken) {
+	start := bytesToInt(lo)
+	end := bytesToInt(hi)
+	if end < start || end-start > 0xFFFF {
+		return
+	}
+
+	for code := start; code <= end; code++ {
+		src := intToBytes(code, len(lo))
+		offset := code - start
+		switch d := dst.(type) {
+		case []byte:
+			if len(d) == 0 {
+				continue
+			}
+			result[string(src)] = decodeUTF16(intToBytes(bytesToInt(d)+offset, len(d)))
+		case []any:
+			if offset < len(d) {
+				if b, ok := d[offset].([]byte); ok {
+					result[string(src)] = decodeUTF16(b)
+				}
+			}
+		}
+	}
+}
+
+func bytesToInt(b []byte) int {
+	v := 0
+	for _, c := range b {
+		v = v<<8 | int(c)
+	}
+	return v
+}
+
+func intToBytes(v, n int) []byte {
+	out := make([]byte, n)
+	for i := n - 1; i >= 0; i-- {
+		out[i] = byte(v)
+		v >>= 8
+	}
+	return out
+}
+
+func decodeUTF16(b []byte) string {
+	if len(b)%2 == 1 {
+		return string(b)
+	}
+	units := make([]uint16, 0, len(b)/2)
+	for i := 0; i+1 < len(b); i += 2 {
+		units = append(units, uint16(b[i])<<8|uint16(b[i+1]))
+	}
+	return string(utf16.Decode(units))
+}
@@ -0,0 +1,354 @@
+package document
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/JaimeStill/document-context/pkg/cache"
+	"github.com/JaimeStill/document-context/pkg/config"
+	"github.com/JaimeStill/document-context/pkg/image"
+)
+
+var (
+	htmlTitle    = regexp.MustCompile(`(?is)<title\b[^>]*>(.*?)</title\s*>`)
+	htmlCharset  = regexp.MustCompile(`(?i)<meta\b[^>]*charset\s*=\s*["']?([A-Za-z0-9_-]+)`)
+	htmlHeading  = regexp.MustCompile(`(?is)<h([1-6])\b[^>]*>(.*?)</h[1-6]\s*>`)
+	htmlListItem = regexp.MustCompile(`(?i)<li\b[^>]*>`)
+	htmlListEnd  = regexp.MustCompile(`(?i)</li\s*>`)
+	htmlBlockEnd = regexp.MustCompile(`(?i)</(p|div|ul|ol|table|blockquote|pre)\s*>`)
+)
+
+// HTMLDocument is an HTML page opened either as rendered pages, converted to
+// PDF by an external converter, or as a single page of readable text.
+type HTMLDocument struct {
+	path        string
+	fingerprint string
+	title       string
+	text        string
+	textOnly    bool
+	renderKey   string
+	tmpDir      string
+	pdf         *PDFDocument
+	closed      bool
+	mu          sync.Mutex
+}
+
+// OpenHTML opens an HTML document using the default HTMLConfig.
+func OpenHTML(path string) (*HTMLDocument, error) {
+	return OpenHTMLWithConfig(path, config.DefaultHTMLConfig())
+}
+
+// OpenHTMLWithConfig opens an HTML document using the provided configuration.
+//
+// Configuration is finalized (defaults applied) before use. The document is
+// decoded using the charset declared in its <meta> tag (UTF-8 otherwise) and
+// its readable text is extracted: scripts and styles are removed, headings
+// are kept on their own lines with ATX markers ("## Heading"), list items are
+// bulleted, and entities are decoded.
+//
+// In text-only mode (cfg.TextOnly) the document has a single page holding the
+// readable text, which cannot be rendered. Otherwise the converter is run
+// once at open time to convert the document to PDF in a temporary directory;
+// the pages of the PDF are the pages of the document. cfg.Args must contain
+// the "{input}" and "{output}" placeholders.
+//
+// Returns an error if the file cannot be read, the converter arguments are
+// invalid, or the conversion fails.
+func OpenHTMLWithConfig(path string, cfg config.HTMLConfig) (*HTMLDocument, error) {
+	cfg.Finalize()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read HTML: %w", err)
+	}
+
+	fingerprint, err := contentFingerprint(path)
+	if err != nil {
+		return nil, err
+	}
+
+	source := decodeHTML(data)
+	doc := &HTMLDocument{
+		path:        path,
+		fingerprint: fingerprint,
+		text:        htmlReadableText(source),
+		textOnly:    cfg.TextOnly,
+	}
+	if m := htmlTitle.FindStringSubmatch(source); m != nil {
+		doc.title = strings.Join(strings.Fields(html.UnescapeString(htmlTag.ReplaceAllString(m[1], ""))), " ")
+	}
+
+	if cfg.TextOnly {
+		return doc, nil
+	}
+
+	if err := doc.convert(cfg); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// decodeHTML converts HTML bytes to UTF-8 using the charset declared in a
+// <meta> tag.
+func decodeHTML(data []byte) string {
+	head := data[:min(len(data), 1024)]
+	if m := htmlCharset.FindSubmatch(head); m != nil {
+		return decodeCharset(data, string(m[1]))
+	}
+	return decodeCharset(data, "utf-8")
+}
+
+// htmlReadableText extracts the readable text of an HTML document, with
+// paragraphs separated by blank lines and headings as separate paragraphs
+// marked with their level.
+func htmlReadableText(s string) string {
+	s = htmlHidden.ReplaceAllString(s, "")
+	s = htmlHeading.ReplaceAllStringFunc(s, func(match string) string {
+		m := htmlHeading.FindStringSubmatch(match)
+		level := int(m[1][0] - '0')
+		text := strings.Join(strings.Fields(htmlTag.ReplaceAllString(m[2], " ")), " ")
+		return "\n\n" + strings.Repeat("#", level) + " " + text + "\n\n"
+	})
+	s = htmlListEnd.ReplaceAllString(s, "")
+	s = htmlBlockEnd.ReplaceAllString(s, "\n\n")
+	s = htmlListItem.ReplaceAllString(s, "\n- ")
+	return htmlToText(s)
+}
+
+// convert runs the converter over the document and opens the resulting PDF.
+func (d *HTMLDocument) convert(cfg config.HTMLConfig) error {
+	input, err := filepath.Abs(d.path)
+	if err != nil {
+		return fmt.Errorf("failed to resolve HTML path: %w", err)
+	}
+
+	var hasInput, hasOutput bool
+	for _, arg := range cfg.Args {
+		hasInput = hasInput || strings.Contains(arg, "{input}")
+		hasOutput = hasOutput || strings.Contains(arg, "{output}")
+	}
+	if !hasInput || !hasOutput {
+		return fmt.Errorf("HTML converter args must contain {input} and {output}, got %q", cfg.Args)
+	}
+
+	tmpDir, err := os.MkdirTemp("", "html-convert-*")
+	if err != nil {
+		return fmt.Errorf("failed to create conversion directory: %w", err)
+	}
+	output := filepath.Join(tmpDir, "document.pdf")
+
+	replacer := strings.NewReplacer("{input}", input, "{output}", output)
+	args := make([]string, len(cfg.Args))
+	for i, arg := range cfg.Args {
+		args[i] = replacer.Replace(arg)
+	}
+
+	var stderr bytes.Buffer
+	cmd := exec.Command(cfg.Converter, args...)
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		os.RemoveAll(tmpDir)
+		return fmt.Errorf("HTML converter failed: %w\nOutput: %s", err, stderr.String())
+	}
+
+	pdf, err := OpenPDF(output)
+	if err != nil {
+		os.RemoveAll(tmpDir)
+		return fmt.Errorf("failed to open converted HTML: %w", err)
+	}
+
+	d.tmpDir = tmpDir
+	d.pdf = pdf
+	// Converters embed timestamps in their output, so rendered pages are
+	// keyed by the HTML content and the converter invocation instead of the
+	// converted PDF.
+	d.renderKey = fmt.Sprintf("%s/html?converter=%s&args=%s",
+		d.fingerprint, filepath.Base(cfg.Converter), strings.Join(cfg.Args, " "))
+	return nil
+}
+
+func (d *HTMLDocument) PageCount() int {
+	if d.pdf == nil {
+		return 1
+	}
+	return d.pdf.PageCount()
+}
+
+// Fingerprint returns the SHA-256 of the HTML file computed at open time.
+func (d *HTMLDocument) Fingerprint() string {
+	return d.fingerprint
+}
+
+// Title returns the content of the <title> element, or an empty string.
+func (d *HTMLDocument) Title() string {
+	return d.title
+}
+
+// TextOnly reports whether the document was opened in text-only mode.
+func (d *HTMLDocument) TextOnly() bool {
+	return d.textOnly
+}
+
+// Text returns the readable text of the whole document, with headings on
+// their own lines marked with their level ("## Heading").
+func (d *HTMLDocument) Text() string {
+	return d.text
+}
+
+func (d *HTMLDocument) ExtractPage(pageNum int) (Page, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.closed {
+		return nil, fmt.Errorf("document is closed")
+	}
+
+	if d.textOnly {
+		if pageNum != 1 {
+			return nil, fmt.Errorf("page %d out of range [1-1]", pageNum)
+		}
+		return &HTMLPage{doc: d, number: 1}, nil
+	}
+
+	page, err := d.pdf.ExtractPage(pageNum)
+	if err != nil {
+		return nil, err
+	}
+	return &HTMLPage{doc: d, number: pageNum, page: page.(*PDFPage)}, nil
+}
+
+func (d *HTMLDocument) ExtractAllPages() ([]Page, error) {
+	pages := make([]Page, 0, d.PageCount())
+	for i := 1; i <= d.PageCount(); i++ {
+		page, err := d.ExtractPage(i)
+		if err != nil {
+			return nil, fmt.Errorf("failed to extract page %d: %w", i, err)
+		}
+		pages = append(pages, page)
+	}
+	return pages, nil
+}
+
+// Close releases the converted PDF and removes the conversion directory.
+func (d *HTMLDocument) Close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.closed {
+		return nil
+	}
+	d.closed = true
+
+	if d.pdf == nil {
+		return nil
+	}
+
+	err := d.pdf.Close()
+	if removeErr := os.RemoveAll(d.tmpDir); removeErr != nil && err == nil {
+		err = removeErr
+	}
+	return err
+}
+
+// HTMLPage is a page of an HTMLDocument: a page of the converted PDF, or the
+// single text page of a text-only document.
+type HTMLPage struct {
+	doc    *HTMLDocument
+	number int
+	page   *PDFPage
+}
+
+func (p *HTMLPage) Number() int {
+	return p.number
+}
+
+// Text returns the page text. Pages of converted documents return the text
+// extracted from the converted PDF page; the text-only page returns the
+// readable text of the document (see HTMLDocument.Text).
+func (p *HTMLPage) Text() (string, error) {
+	if p.page == nil {
+		return p.doc.text, nil
+	}
+	return p.page.Text()
+}
+
+// ToImage renders the converted PDF page. Rendering is not supported in
+// text-only mode and returns an error wrapping ErrRenderNotSupported.
+//
+// Cache keys derive from the HTML fingerprint and converter invocation
+// rather than the converted PDF, so repeated conversions of the same
+// document share cached images.
+func (p *HTMLPage) ToImage(renderer image.Renderer, c cache.Cache) ([]byte, error) {
+	return imageData(p.Render(renderer, c))
+}
+
+// ToBudgetImage renders the page like ToImage and returns the image with the
+// encoding it was rendered with (see Render). Returns an error wrapping
+// ErrRenderNotSupported in text-only mode.
+func (p *HTMLPage) ToBudgetImage(renderer image.Renderer, c cache.Cache) (*BudgetImage, error) {
+	return budgetImage(p.Render(renderer, c))
+}
+
+// Render renders the page like ToImage and returns the image with its
+// metadata (see PDFPage.Render).
+// Returns an error wrapping ErrRenderNotSupported in text-only mode.
+func (p *HTMLPage) Render(renderer image.Renderer, c cache.Cache) (*RenderResult, error) {
+	if p.page == nil {
+		return nil, fmt.Errorf("HTML page %d (text-only): %w", p.number, ErrRenderNotSupported)
+	}
+
+	dpi, err := p.page.renderDPI(renderer)
+	if err != nil {
+		return nil, err
+	}
+
+	key := imageCacheKeyAt(p.doc.renderKey, p.number, renderer, dpi)
+	filename := imageFilename(p.doc.path, p.number, renderer.Settings().Format)
+
+	return renderCached(c, key, filename, renderer, dpi, func() (*BudgetImage, error) {
+		return renderFileAt(renderer, p.page.doc.source(), p.number, p.number, dpi)
+	})
+}
+
+// WriteImage renders the page like ToImage and writes the image to w,
+// streaming it from the renderer when possible (see StreamPage). Returns an
+// error wrapping ErrRenderNotSupported in text-only mode.
+func (p *HTMLPage) WriteImage(w io.Writer, renderer image.Renderer, c cache.Cache) error {
+	if p.page == nil {
+		return fmt.Errorf("HTML page %d (text-only): %w", p.number, ErrRenderNotSupported)
+	}
+
+	return writeImage(w, renderer, c, func(s image.StreamRenderer) error {
+		dpi, err := p.page.renderDPI(renderer)
+		if err != nil {
+			return err
+		}
+		return streamFile(w, s, p.page.doc.source(), p.number, p.number, dpi)
+	}, func() (*RenderResult, error) {
+		return p.Render(renderer, c)
+	})
+}
+
+// ImageCacheKey returns the cache key under which ToImage stores the page
+// rendered with renderer. Returns an error wrapping ErrRenderNotSupported in
+// text-only mode.
+func (p *HTMLPage) ImageCacheKey(renderer image.Renderer) (string, error) {
+	if p.page == nil {
+		return "", fmt.Errorf("HTML page %d (text-only): %w", p.number, ErrRenderNotSupported)
+	}
+
+	dpi, err := p.page.renderDPI(renderer)
+	if err != nil {
+		return "", err
+	}
+	return imageCacheKeyAt(p.doc.renderKey, p.number, renderer, dpi), nil
+}
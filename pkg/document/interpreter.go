@@ -0,0 +1,422 @@
+package document
+
+import (
+	"math"
+	"strings"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+// maxFormDepth bounds recursion through nested form XObjects.
+const maxFormDepth = 8
+
+// ruleThickness is the maximum thickness, in user space units, of a filled
+// rectangle treated as a ruling line rather than a shaded area.
+const ruleThickness = 3.0
+
+// tjSpaceThreshold is the TJ adjustment (in thousandths of text space) beyond
+// which a positioning gap is treated as an inter-word space.
+const tjSpaceThreshold = 250
+
+// graphicsState holds the parts of the PDF graphics and text state that affect
+// where and how text is placed on the page.
+type graphicsState struct {
+	ctm         matrix
+	font        *pdfFont
+	fontSize    float64
+	charSpacing float64
+	wordSpacing float64
+	hScale      float64
+	leading     float64
+	rise        float64
+	renderMode  int
+}
+
+// pageContent is the result of interpreting a page's content streams.
+type pageContent struct {
+	box             Rect
+	spans           []TextSpan
+	textOperators   int
+	glyphs          int
+	invisibleGlyphs int
+	images          []Rect
+	rules           []Rect
+	fonts           map[string]bool
+}
+
+// pathSegment is a straight segment of the current path in user space.
+type pathSegment struct {
+	x0, y0, x1, y1 float64
+}
+
+// contentInterpreter executes content stream operators relevant to layout
+// analysis, recording positioned text, image placements, and axis-aligned
+// ruling lines.
+//
+// Curves are tracked only to keep the current point; color, clipping, and
+// shading operators are ignored.
+type contentInterpreter struct {
+	ctx     *model.Context
+	fonts   *fontCache
+	state   graphicsState
+	stack   []graphicsState
+	tm      matrix
+	tlm     matrix
+	content *pageContent
+
+	segments       []pathSegment
+	rects          []Rect
+	curX, curY     float64
+	startX, startY float64
+}
+
+func newContentInterpreter(ctx *model.Context) *contentInterpreter {
+	return &contentInterpreter{
+		ctx:   ctx,
+		fonts: newFontCache(ctx),
+		state: graphicsState{
+			ctm:    identityMatrix,
+			hScale: 1,
+		},
+		tm:      identityMatrix,
+		tlm:     identityMatrix,
+		content: &pageContent{fonts: make(map[string]bool)},
+	}
+}
+
+// run interprets data using resources to resolve fonts and XObjects.
+func (in *contentInterpreter) run(data []byte, resources types.Dict, depth int) {
+	lexer := newContentLexer(data)
+
+	var operands []any
+	for tok := lexer.next(); tok != nil; tok = lexer.next() {
+		op, ok := tok.(pdfOperator)
+		if !ok {
+			operands = append(operands, tok)
+			continue
+		}
+		in.execute(op, operands, resources, depth)
+		operands = operands[:0]
+	}
+}
+
+func numberOperand(ops []any, i int) float64 {
+	if i < 0 || i >= len(ops) {
+		return 0
+	}
+	v, _ := ops[i].(float64)
+	return v
+}
+
+func (in *contentInterpreter) execute(op pdfOperator, ops []any, resources types.Dict, depth int) {
+	switch op {
+	case "q":
+		in.stack = append(in.stack, in.state)
+	case "Q":
+		if n := len(in.stack); n > 0 {
+			in.state = in.stack[n-1]
+			in.stack = in.stack[:n-1]
+		}
+	case "cm":
+		if m, err := matrixFromOperands(ops); err == nil {
+			in.state.ctm = m.multiply(in.state.ctm)
+		}
+	case "BT":
+		in.tm = identityMatrix
+		in.tlm = identityMatrix
+	case "Tf":
+		if len(ops) >= 2 {
+			if name, ok := ops[len(ops)-2].(pdfName); ok {
+				in.state.font = in.fonts.lookup(resources, string(name))
+			}
+			in.state.fontSize = numberOperand(ops, len(ops)-1)
+		}
+	case "Tc":
+		in.state.charSpacing = numberOperand(ops, len(ops)-1)
+	case "Tw":
+		in.state.wordSpacing = numberOperand(ops, len(ops)-1)
+	case "Tz":
+		in.state.hScale = numberOperand(ops, len(ops)-1) / 100
+	case "TL":
+		in.state.leading = numberOperand(ops, len(ops)-1)
+	case "Ts":
+		in.state.rise = numberOperand(ops, len(ops)-1)
+	case "Tr":
+		in.state.renderMode = int(numberOperand(ops, len(ops)-1))
+	case "Td":
+		in.moveText(numberOperand(ops, len(ops)-2), numberOperand(ops, len(ops)-1))
+	case "TD":
+		ty := numberOperand(ops, len(ops)-1)
+		in.state.leading = -ty
+		in.moveText(numberOperand(ops, len(ops)-2), ty)
+	case "Tm":
+		if m, err := matrixFromOperands(ops); err == nil {
+			in.tm = m
+			in.tlm = m
+		}
+	case "T*":
+		in.moveText(0, -in.state.leading)
+	case "Tj":
+		if len(ops) > 0 {
+			if s, ok := ops[len(ops)-1].([]byte); ok {
+				in.showText([]any{s})
+			}
+		}
+	case "TJ":
+		if len(ops) > 0 {
+			if arr, ok := ops[len(ops)-1].([]any); ok {
+				in.showText(arr)
+			}
+		}
+	case "'":
+		in.moveText(0, -in.state.leading)
+		if len(ops) > 0 {
+			if s, ok := ops[len(ops)-1].([]byte); ok {
+				in.showText([]any{s})
+			}
+		}
+	case "\"":
+		if len(ops) >= 3 {
+			in.state.wordSpacing = numberOperand(ops, len(ops)-3)
+			in.state.charSpacing = numberOperand(ops, len(ops)-2)
+			in.moveText(0, -in.state.leading)
+			if s, ok := ops[len(ops)-1].([]byte); ok {
+				in.showText([]any{s})
+			}
+		}
+	case "Do":
+		if len(ops) > 0 {
+			if name, ok := ops[len(ops)-1].(pdfName); ok {
+				in.drawXObject(resources, string(name), depth)
+			}
+		}
+	case "EI":
+		in.drawImage()
+	case "m":
+		in.curX, in.curY = in.state.ctm.apply(numberOperand(ops, len(ops)-2), numberOperand(ops, len(ops)-1))
+		in.startX, in.startY = in.curX, in.curY
+	case "l":
+		x, y := in.state.ctm.apply(numberOperand(ops, len(ops)-2), numberOperand(ops, len(ops)-1))
+		in.segments = append(in.segments, pathSegment{in.curX, in.curY, x, y})
+		in.curX, in.curY = x, y
+	case "c", "v", "y":
+		in.curX, in.curY = in.state.ctm.apply(numberOperand(ops, len(ops)-2), numberOperand(ops, len(ops)-1))
+	case "h":
+		in.segments = append(in.segments, pathSegment{in.curX, in.curY, in.startX, in.startY})
+		in.curX, in.curY = in.startX, in.startY
+	case "re":
+		x, y := numberOperand(ops, len(ops)-4), numberOperand(ops, len(ops)-3)
+		w, h := numberOperand(ops, len(ops)-2), numberOperand(ops, len(ops)-1)
+		m := matrix{w, 0, 0, h, x, y}.multiply(in.state.ctm)
+		in.rects = append(in.rects, transformUnitSquare(m))
+		in.curX, in.curY = in.state.ctm.apply(x, y)
+		in.startX, in.startY = in.curX, in.curY
+	case "S", "s":
+		if op == "s" {
+			in.execute("h", nil, resources, depth)
+		}
+		in.paintPath(true, false)
+	case "f", "F", "f*":
+		in.paintPath(false, true)
+	case "B", "B*", "b", "b*":
+		if op == "b" || op == "b*" {
+			in.execute("h", nil, resources, depth)
+		}
+		in.paintPath(true, true)
+	case "n":
+		in.paintPath(false, false)
+	}
+}
+
+// paintPath records the axis-aligned ruling lines of the current path and
+// clears it.
+//
+// Stroked segments and rectangle edges become rules. Filled rectangles become
+// a single rule only when thin enough to read as a line; larger fills are
+// shading and are ignored.
+func (in *contentInterpreter) paintPath(stroke, fill bool) {
+	const tolerance = 0.5
+
+	if stroke {
+		for _, seg := range in.segments {
+			horizontal := math.Abs(seg.y1-seg.y0) <= tolerance
+			vertical := math.Abs(seg.x1-seg.x0) <= tolerance
+			if horizontal == vertical {
+				continue
+			}
+			in.content.rules = append(in.content.rules, Rect{
+				X0: min(seg.x0, seg.x1),
+				Y0: min(seg.y0, seg.y1),
+				X1: max(seg.x0, seg.x1),
+				Y1: max(seg.y0, seg.y1),
+			})
+		}
+	}
+
+	for _, b := range in.rects {
+		switch {
+		case fill && (b.Height() <= ruleThickness || b.Width() <= ruleThickness):
+			in.content.rules = append(in.content.rules, b)
+		case stroke:
+			in.content.rules = append(in.content.rules,
+				Rect{X0: b.X0, Y0: b.Y0, X1: b.X1, Y1: b.Y0},
+				Rect{X0: b.X0, Y0: b.Y1, X1: b.X1, Y1: b.Y1},
+				Rect{X0: b.X0, Y0: b.Y0, X1: b.X0, Y1: b.Y1},
+				Rect{X0: b.X1, Y0: b.Y0, X1: b.X1, Y1: b.Y1},
+			)
+		}
+	}
+
+	in.segments = in.segments[:0]
+	in.rects = in.rects[:0]
+}
+
+// drawImage records the placement of an image painted into the unit square
+// of the current transformation matrix.
+func (in *contentInterpreter) drawImage() {
+	in.content.images = append(in.content.images, transformUnitSquare(in.state.ctm))
+}
+
+func transformUnitSquare(m matrix) Rect {
+	x0, y0 := m.apply(0, 0)
+	x1, y1 := m.apply(1, 0)
+	x2, y2 := m.apply(0, 1)
+	x3, y3 := m.apply(1, 1)
+	return Rect{
+		X0: min(x0, x1, x2, x3),
+		Y0: min(y0, y1, y2, y3),
+		X1: max(x0, x1, x2, x3),
+		Y1: max(y0, y1, y2, y3),
+	}
+}
+
+func (in *contentInterpreter) moveText(tx, ty float64) {
+	in.tlm = matrix{1, 0, 0, 1, tx, ty}.multiply(in.tlm)
+	in.tm = in.tlm
+}
+
+// showText places the strings and positioning adjustments of a text-showing
+// operator, recording a single span for the operator.
+func (in *contentInterpreter) showText(items []any) {
+	font := in.state.font
+	if font == nil {
+		font = &pdfFont{defaultWidth: 500}
+	}
+
+	fontSize := in.state.fontSize
+	hScale := in.state.hScale
+
+	tmc := in.tm.multiply(in.state.ctm)
+	trm := matrix{fontSize * hScale, 0, 0, fontSize, 0, in.state.rise}.multiply(tmc)
+	startX, startY := trm.apply(0, 0)
+	size := fontSize * math.Hypot(tmc[2], tmc[3])
+
+	invisible := in.state.renderMode == 3 || in.state.renderMode == 7
+	in.content.textOperators++
+	if font.name != "" {
+		in.content.fonts[font.name] = true
+	}
+
+	var text []byte
+	for _, item := range items {
+		switch v := item.(type) {
+		case []byte:
+			for _, g := range font.decode(v) {
+				text = append(text, g.text...)
+				if strings.TrimSpace(g.text) != "" {
+					if invisible {
+						in.content.invisibleGlyphs++
+					} else {
+						in.content.glyphs++
+					}
+				}
+				advance := g.width*fontSize + in.state.charSpacing
+				if g.space {
+					advance += in.state.wordSpacing
+				}
+				in.tm = matrix{1, 0, 0, 1, advance * hScale, 0}.multiply(in.tm)
+			}
+		case float64:
+			if v < -tjSpaceThreshold && len(text) > 0 && text[len(text)-1] != ' ' {
+				text = append(text, ' ')
+			}
+			in.tm = matrix{1, 0, 0, 1, -v / 1000 * fontSize * hScale, 0}.multiply(in.tm)
+		}
+	}
+
+	if len(text) == 0 {
+		return
+	}
+
+	end := matrix{fontSize * hScale, 0, 0, fontSize, 0, in.state.rise}.multiply(in.tm).multiply(in.state.ctm)
+	endX, _ := end.apply(0, 0)
+
+	in.content.spans = append(in.content.spans, TextSpan{
+		Text:      string(text),
+		X:         math.Min(startX, endX),
+		Y:         startY,
+		Width:     math.Abs(endX - startX),
+		FontSize:  size,
+		Font:      font.name,
+		Invisible: invisible,
+	})
+}
+
+// drawXObject interprets form XObjects in place and records the placement of
+// image XObjects. Other XObject types are ignored.
+func (in *contentInterpreter) drawXObject(resources types.Dict, name string, depth int) {
+	if depth >= maxFormDepth {
+		return
+	}
+
+	xobjects := in.fonts.dict(resources, "XObject")
+	if xobjects == nil {
+		return
+	}
+
+	ref, ok := xobjects.Find(name)
+	if !ok {
+		return
+	}
+
+	sd, _, err := in.ctx.DereferenceStreamDict(ref)
+	if err != nil || sd == nil {
+		return
+	}
+
+	subtype := sd.Dict.NameEntry("Subtype")
+	if subtype != nil && *subtype == "Image" {
+		in.drawImage()
+		return
+	}
+
+	if subtype == nil || *subtype != "Form" {
+		return
+	}
+
+	if err := sd.Decode(); err != nil {
+		return
+	}
+
+	formResources := in.fonts.dict(sd.Dict, "Resources")
+	if formResources == nil {
+		formResources = resources
+	}
+
+	saved := in.state
+	savedTM, savedTLM := in.tm, in.tlm
+
+	if arr := in.fonts.array(sd.Dict["Matrix"]); len(arr) == 6 {
+		var m matrix
+		for i := range m {
+			m[i], _ = in.fonts.number(arr[i])
+		}
+		in.state.ctm = m.multiply(in.state.ctm)
+	}
+
+	in.run(sd.Content, formResources, depth+1)
+
+	in.state = saved
+	in.tm, in.tlm = savedTM, savedTLM
+}
@@ -0,0 +1,408 @@
+package document
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+const (
+	// marginBand is the fraction of the page height, at the top and bottom of
+	// the page box, in which lines are considered running headers or footers.
+	marginBand = 0.08
+
+	// minColumnWidth is the minimum width, as a multiple of the median font
+	// size, of each side of a column split. Narrower splits are usually table
+	// columns, which read row by row rather than column by column.
+	minColumnWidth = 10.0
+
+	// sidebarRatio is the maximum width of a sidebar relative to the column it
+	// sits beside.
+	sidebarRatio = 0.5
+)
+
+// TextMode selects how page text is ordered.
+type TextMode string
+
+const (
+	// TextStream orders text as it appears in the content stream. This is the
+	// fastest mode and matches the producer's drawing order, which is often but
+	// not always the reading order.
+	TextStream TextMode = "stream"
+
+	// TextReading reconstructs human reading order from text positions:
+	// running headers first, then body columns left to right, then sidebars,
+	// then running footers.
+	TextReading TextMode = "reading"
+)
+
+// RegionKind identifies the role of a layout region.
+type RegionKind string
+
+const (
+	RegionHeader  RegionKind = "header"
+	RegionBody    RegionKind = "body"
+	RegionSidebar RegionKind = "sidebar"
+	RegionFooter  RegionKind = "footer"
+)
+
+// LayoutRegion is a block of text identified by layout analysis.
+type LayoutRegion struct {
+	// Kind is the role of the region on the page.
+	Kind RegionKind
+
+	// Bounds is the region in PDF user space.
+	Bounds Rect
+
+	// Text is the region text, with lines separated by newlines.
+	Text string
+}
+
+// LayoutPage is implemented by pages that can analyze their layout and
+// extract text in reading order.
+type LayoutPage interface {
+	TextPage
+	ExtractText(mode TextMode, exclude ...Rect) (string, error)
+	Layout() ([]LayoutRegion, error)
+}
+
+// ExtractText returns the page text ordered according to mode.
+//
+// TextStream is equivalent to Text. TextReading joins the regions returned by
+// Layout with blank lines between them. Text whose center falls within any of
+// the exclude rectangles is omitted, which removes content such as repeated
+// headers and footers before layout analysis.
+//
+// Parameters:
+//   - mode: Text ordering mode
+//   - exclude: Regions, in PDF user space, whose text is omitted
+//
+// Returns:
+//   - string: Page text
+//   - error: Unknown mode or interpretation failure
+func (p *PDFPage) ExtractText(mode TextMode, exclude ...Rect) (string, error) {
+	if mode != TextStream && mode != TextReading {
+		return "", fmt.Errorf("unsupported text mode: %q", mode)
+	}
+
+	content, err := p.interpret()
+	if err != nil {
+		return "", err
+	}
+
+	spans := content.spans
+	if len(exclude) > 0 {
+		spans = excludeSpans(spans, exclude)
+	}
+
+	if mode == TextStream {
+		return joinSpans(spans), nil
+	}
+
+	regions := analyzeLayout(spans, content.box)
+	texts := make([]string, len(regions))
+	for i, r := range regions {
+		texts[i] = r.Text
+	}
+	return strings.Join(texts, "\n\n"), nil
+}
+
+// excludeSpans returns the spans whose centers lie outside every region.
+func excludeSpans(spans []TextSpan, regions []Rect) []TextSpan {
+	kept := make([]TextSpan, 0, len(spans))
+	for _, s := range spans {
+		inside := false
+		for _, r := range regions {
+			if contains(r, spanCenter(s)) {
+				inside = true
+				break
+			}
+		}
+		if !inside {
+			kept = append(kept, s)
+		}
+	}
+	return kept
+}
+
+// Layout analyzes the positioned text of the page and returns its regions in
+// reading order.
+//
+// Lines within the top and bottom margin bands of the page box that are
+// separated from the body by a gap are running headers and footers. The body
+// is segmented by recursive XY-cut: whitespace gaps spanning the whole region
+// split it into bands (read top to bottom) or columns (read left to right),
+// preferring the wider gap. A column less than half as wide as the columns
+// beside it is a sidebar and is read after the body.
+func (p *PDFPage) Layout() ([]LayoutRegion, error) {
+	content, err := p.interpret()
+	if err != nil {
+		return nil, err
+	}
+	return analyzeLayout(content.spans, content.box), nil
+}
+
+// layoutBox is a chunk of a text line: spans on one baseline without a column
+// gap between them.
+type layoutBox struct {
+	bounds Rect
+	spans  []TextSpan
+}
+
+// layoutRegion is an intermediate region produced by XY-cut. Columns are
+// regions created by a vertical cut; bands separated only by horizontal cuts
+// are merged.
+type layoutRegion struct {
+	boxes  []layoutBox
+	column bool
+	kind   RegionKind
+}
+
+func analyzeLayout(spans []TextSpan, page Rect) []LayoutRegion {
+	var boxes []layoutBox
+	var sizes []float64
+	for _, line := range groupLines(visibleSpans(spans)) {
+		for _, c := range line.chunks {
+			box := layoutBox{spans: c.spans, bounds: Rect{X0: c.x0, X1: c.x1, Y0: math.Inf(1), Y1: math.Inf(-1)}}
+			for _, s := range c.spans {
+				b := s.Bounds()
+				box.bounds.Y0 = math.Min(box.bounds.Y0, b.Y0)
+				box.bounds.Y1 = math.Max(box.bounds.Y1, b.Y1)
+				sizes = append(sizes, s.FontSize)
+			}
+			boxes = append(boxes, box)
+		}
+	}
+	if len(boxes) == 0 {
+		return nil
+	}
+
+	sort.Float64s(sizes)
+	em := math.Max(sizes[len(sizes)/2], 1)
+
+	header, body, footer := splitMargins(boxes, page, em)
+
+	var main, sidebars []layoutRegion
+	for _, r := range xyCut(body, em) {
+		if r.kind == RegionSidebar {
+			sidebars = append(sidebars, r)
+		} else {
+			main = append(main, r)
+		}
+	}
+
+	var regions []LayoutRegion
+	add := func(kind RegionKind, boxes []layoutBox) {
+		if len(boxes) > 0 {
+			regions = append(regions, newLayoutRegion(kind, boxes))
+		}
+	}
+
+	add(RegionHeader, header)
+	for _, r := range main {
+		add(RegionBody, r.boxes)
+	}
+	for _, r := range sidebars {
+		add(RegionSidebar, r.boxes)
+	}
+	add(RegionFooter, footer)
+
+	return regions
+}
+
+func visibleSpans(spans []TextSpan) []TextSpan {
+	visible := make([]TextSpan, 0, len(spans))
+	for _, s := range spans {
+		if !s.Invisible && strings.TrimSpace(s.Text) != "" {
+			visible = append(visible, s)
+		}
+	}
+	return visible
+}
+
+func newLayoutRegion(kind RegionKind, boxes []layoutBox) LayoutRegion {
+	region := LayoutRegion{Kind: kind, Bounds: boxes[0].bounds}
+
+	var spans []TextSpan
+	for _, b := range boxes {
+		region.Bounds = union(region.Bounds, b.bounds)
+		spans = append(spans, b.spans...)
+	}
+	region.Text = joinSpans(readingOrder(spans))
+
+	return region
+}
+
+func union(a, b Rect) Rect {
+	return Rect{
+		X0: math.Min(a.X0, b.X0),
+		Y0: math.Min(a.Y0, b.Y0),
+		X1: math.Max(a.X1, b.X1),
+		Y1: math.Max(a.Y1, b.Y1),
+	}
+}
+
+// splitMargins separates running headers and footers from the body. Header
+// boxes lie entirely within the top margin band and sit at least one em above
+// every body box; footers mirror this at the bottom of the page.
+func splitMargins(boxes []layoutBox, page Rect, em float64) (header, body, footer []layoutBox) {
+	if page.Height() <= 0 {
+		return nil, boxes, nil
+	}
+
+	band := marginBand * page.Height()
+	top := page.Y1 - band
+	bottom := page.Y0 + band
+
+	var candidates []layoutBox
+	for _, b := range boxes {
+		switch {
+		case b.bounds.Y0 >= top:
+			header = append(header, b)
+		case b.bounds.Y1 <= bottom:
+			footer = append(footer, b)
+		default:
+			body = append(body, b)
+		}
+	}
+
+	bodyTop, bodyBottom := math.Inf(-1), math.Inf(1)
+	for _, b := range body {
+		bodyTop = math.Max(bodyTop, b.bounds.Y1)
+		bodyBottom = math.Min(bodyBottom, b.bounds.Y0)
+	}
+
+	candidates, header = header, nil
+	for _, b := range candidates {
+		if b.bounds.Y0-bodyTop >= em {
+			header = append(header, b)
+		} else {
+			body = append(body, b)
+		}
+	}
+
+	candidates, footer = footer, nil
+	for _, b := range candidates {
+		if bodyBottom-b.bounds.Y1 >= em {
+			footer = append(footer, b)
+		} else {
+			body = append(body, b)
+		}
+	}
+
+	return header, body, footer
+}
+
+// xyCut recursively segments boxes at the widest whitespace gap that spans
+// the whole region and returns the resulting regions in reading order.
+func xyCut(boxes []layoutBox, em float64) []layoutRegion {
+	if len(boxes) == 0 {
+		return nil
+	}
+
+	hGap, above, below := horizontalCut(boxes)
+	vGap, left, right := verticalCut(boxes, em)
+
+	switch {
+	case vGap > 0 && vGap >= hGap:
+		leftRegions := xyCut(left, em)
+		rightRegions := xyCut(right, em)
+
+		lw, rw := extent(left).Width(), extent(right).Width()
+		markColumns(leftRegions, lw < sidebarRatio*widest(rightRegions))
+		markColumns(rightRegions, rw < sidebarRatio*widest(leftRegions))
+
+		return append(leftRegions, rightRegions...)
+
+	case hGap > 0:
+		upper := xyCut(above, em)
+		lower := xyCut(below, em)
+
+		last := &upper[len(upper)-1]
+		if !last.column && !lower[0].column {
+			last.boxes = append(last.boxes, lower[0].boxes...)
+			lower = lower[1:]
+		}
+
+		return append(upper, lower...)
+
+	default:
+		return []layoutRegion{{boxes: boxes, kind: RegionBody}}
+	}
+}
+
+// markColumns flags regions as columns and, when sidebar is set, as sidebars.
+// Regions already identified as sidebars keep their kind.
+func markColumns(regions []layoutRegion, sidebar bool) {
+	for i := range regions {
+		regions[i].column = true
+		if sidebar {
+			regions[i].kind = RegionSidebar
+		}
+	}
+}
+
+// widest returns the width of the widest region.
+func widest(regions []layoutRegion) float64 {
+	var w float64
+	for _, r := range regions {
+		w = math.Max(w, extent(r.boxes).Width())
+	}
+	return w
+}
+
+func extent(boxes []layoutBox) Rect {
+	r := boxes[0].bounds
+	for _, b := range boxes[1:] {
+		r = union(r, b.bounds)
+	}
+	return r
+}
+
+// horizontalCut finds the widest vertical gap between boxes that no box
+// crosses, returning the gap and the boxes above and below it.
+func horizontalCut(boxes []layoutBox) (float64, []layoutBox, []layoutBox) {
+	sorted := append([]layoutBox(nil), boxes...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].bounds.Y1 > sorted[j].bounds.Y1 })
+
+	best, at := 0.0, 0
+	low := sorted[0].bounds.Y0
+	for i := 1; i < len(sorted); i++ {
+		if gap := low - sorted[i].bounds.Y1; gap > best {
+			best, at = gap, i
+		}
+		low = math.Min(low, sorted[i].bounds.Y0)
+	}
+
+	if at == 0 {
+		return 0, nil, nil
+	}
+	return best, sorted[:at], sorted[at:]
+}
+
+// verticalCut finds the widest horizontal gap between boxes that no box
+// crosses, provided it is at least a column gap wide and both sides are wide
+// enough to be columns.
+func verticalCut(boxes []layoutBox, em float64) (float64, []layoutBox, []layoutBox) {
+	sorted := append([]layoutBox(nil), boxes...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].bounds.X0 < sorted[j].bounds.X0 })
+
+	right := extent(sorted).X1
+
+	best, at := 0.0, 0
+	high := sorted[0].bounds.X1
+	for i := 1; i < len(sorted); i++ {
+		gap := sorted[i].bounds.X0 - high
+		wide := high-sorted[0].bounds.X0 >= minColumnWidth*em && right-sorted[i].bounds.X0 >= minColumnWidth*em
+		if gap >= columnGap*em && wide && gap > best {
+			best, at = gap, i
+		}
+		high = math.Max(high, sorted[i].bounds.X1)
+	}
+
+	if at == 0 {
+		return 0, nil, nil
+	}
+	return best, sorted[:at], sorted[at:]
+}
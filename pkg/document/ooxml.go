@@ -0,0 +1,230 @@
+package document
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+// maxPartSize bounds the uncompressed size of a single package part read into
+// memory, protecting against decompression bombs.
+const maxPartSize = 256 << 20
+
+// ooxmlPackage is an opened Office Open XML package (DOCX, XLSX, PPTX).
+type ooxmlPackage struct {
+	reader *zip.ReadCloser
+	files  map[string]*zip.File
+}
+
+// relationship is an entry of a package relationships part.
+type relationship struct {
+	ID     string `xml:"Id,attr"`
+	Type   string `xml:"Type,attr"`
+	Target string `xml:"Target,attr"`
+	Mode   string `xml:"TargetMode,attr"`
+}
+
+func openOOXML(path string) (*ooxmlPackage, error) {
+	reader, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open package: %w", err)
+	}
+
+	files := make(map[string]*zip.File, len(reader.File))
+	for _, f := range reader.File {
+		files[strings.TrimPrefix(f.Name, "/")] = f
+	}
+
+	return &ooxmlPackage{reader: reader, files: files}, nil
+}
+
+func (p *ooxmlPackage) Close() error {
+	return p.reader.Close()
+}
+
+// has reports whether the package contains the named part.
+func (p *ooxmlPackage) has(name string) bool {
+	_, ok := p.files[name]
+	return ok
+}
+
+// read returns the content of the named part.
+func (p *ooxmlPackage) read(name string) ([]byte, error) {
+	f, ok := p.files[name]
+	if !ok {
+		return nil, fmt.Errorf("package part not found: %s", name)
+	}
+	if f.UncompressedSize64 > maxPartSize {
+		return nil, fmt.Errorf("package part %s exceeds %d bytes", name, maxPartSize)
+	}
+
+	rc, err := f.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open package part %s: %w", name, err)
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(io.LimitReader(rc, maxPartSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read package part %s: %w", name, err)
+	}
+	if len(data) > maxPartSize {
+		return nil, fmt.Errorf("package part %s exceeds %d bytes", name, maxPartSize)
+	}
+	return data, nil
+}
+
+// relationships returns the relationships of the named part (or of the
+// package when name is empty), with targets resolved to part names.
+func (p *ooxmlPackage) relationships(name string) ([]relationship, error) {
+	dir, file := path.Split(name)
+	relsName := path.Join(dir, "_rels", file+".rels")
+
+	if !p.has(relsName) {
+		return nil, nil
+	}
+
+	data, err := p.read(relsName)
+	if err != nil {
+		return nil, err
+	}
+
+	var rels struct {
+		Relationships []relationship `xml:"Relationship"`
+	}
+	if err := xml.Unmarshal(data, &rels); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", relsName, err)
+	}
+
+	for i, r := range rels.Relationships {
+		if r.Mode == "External" {
+			continue
+		}
+		if strings.HasPrefix(r.Target, "/") {
+			rels.Relationships[i].Target = strings.TrimPrefix(r.Target, "/")
+		} else {
+			rels.Relationships[i].Target = path.Join(dir, r.Target)
+		}
+	}
+
+	return rels.Relationships, nil
+}
+
+// related returns the target part of the first relationship of name whose type
+// ends with typeSuffix (e.g., "/officeDocument", "/styles"), or "" if none.
+func (p *ooxmlPackage) related(name, typeSuffix string) (string, error) {
+	rels, err := p.relationships(name)
+	if err != nil {
+		return "", err
+	}
+	for _, r := range rels {
+		if strings.HasSuffix(r.Type, typeSuffix) && r.Mode != "External" {
+			return r.Target, nil
+		}
+	}
+	return "", nil
+}
+
+// mainPart returns the main document part of the package, falling back to
+// fallback when the package relationships do not name one.
+func (p *ooxmlPackage) mainPart(fallback string) (string, error) {
+	target, err := p.related("", "/officeDocument")
+	if err != nil {
+		return "", err
+	}
+	if target == "" {
+		target = fallback
+	}
+	if !p.has(target) {
+		return "", fmt.Errorf("package has no main document part")
+	}
+	return target, nil
+}
+
+// attr returns the value of the attribute with the given local name.
+func attr(e xml.StartElement, local string) string {
+	for _, a := range e.Attr {
+		if a.Name.Local == local {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// xmlNode is a generic XML element tree, used for parts whose structure is
+// navigated rather than streamed (e.g., PPTX shape trees).
+type xmlNode struct {
+	XMLName xml.Name
+	Attrs   []xml.Attr `xml:",any,attr"`
+	Text    string     `xml:",chardata"`
+	Nodes   []xmlNode  `xml:",any"`
+}
+
+// parseXML parses data into an element tree.
+func parseXML(data []byte) (*xmlNode, error) {
+	var root xmlNode
+	if err := xml.Unmarshal(data, &root); err != nil {
+		return nil, err
+	}
+	return &root, nil
+}
+
+// child returns the first child element with the given local name following
+// path, or nil if any element of the path is missing. It is safe to call on a
+// nil node.
+func (n *xmlNode) child(path ...string) *xmlNode {
+	for _, local := range path {
+		if n == nil {
+			return nil
+		}
+		var next *xmlNode
+		for i := range n.Nodes {
+			if n.Nodes[i].XMLName.Local == local {
+				next = &n.Nodes[i]
+				break
+			}
+		}
+		n = next
+	}
+	return n
+}
+
+// attr returns the value of the attribute with the given local name, or "" on
+// a nil node.
+func (n *xmlNode) attr(local string) string {
+	if n == nil {
+		return ""
+	}
+	for _, a := range n.Attrs {
+		if a.Name.Local == local {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// textContent returns the character data of the element, or "" on a nil
+// node.
+func (n *xmlNode) textContent() string {
+	if n == nil {
+		return ""
+	}
+	return n.Text
+}
+
+// nsAttr returns the value of the namespaced attribute with the given local
+// name (e.g., r:id, which differs from an unqualified id attribute).
+func (n *xmlNode) nsAttr(local string) string {
+	if n == nil {
+		return ""
+	}
+	for _, a := range n.Attrs {
+		if a.Name.Local == local && a.Name.Space != "" {
+			return a.Value
+		}
+	}
+	return ""
+}
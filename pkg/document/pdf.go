@@ -1,39 +1,86 @@
 package document
 
 import (
-	"errors"
 	"fmt"
+	"io"
 	"os"
-	"path/filepath"
-	"strings"
+	"sync"
 
 	"github.com/JaimeStill/document-context/pkg/cache"
+	"github.com/JaimeStill/document-context/pkg/config"
 	"github.com/JaimeStill/document-context/pkg/image"
-	"github.com/pdfcpu/pdfcpu/pkg/api"
 	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
 )
 
 type PDFDocument struct {
-	path      string
-	ctx       *model.Context
-	pageCount int
+	path         string
+	repairedPath string
+	warnings     []string
+	ctx          *model.Context
+	pageCount    int
+	fingerprint  string
+	mu           sync.Mutex
 }
 
+// OpenPDF opens a PDF document using the default DocumentConfig.
 func OpenPDF(path string) (*PDFDocument, error) {
-	ctx, err := api.ReadContextFile(path)
+	return OpenPDFWithConfig(path, config.DefaultDocumentConfig())
+}
+
+// OpenPDFWithConfig opens a PDF document using the provided configuration.
+//
+// Configuration is finalized (defaults applied) before use. The document's
+// content fingerprint is computed at open time using cfg.Fingerprint:
+//   - "content": SHA-256 of the document bytes, hashed from the same read the
+//     document is parsed from
+//   - "fast": PDF trailer ID array combined with file size and modification time
+//
+// The document is validated using cfg.Validation ("strict", "relaxed", or
+// "none"). When cfg.Repair is enabled, documents that cannot be parsed or fail
+// validation are repaired on a best-effort basis and opened with warnings
+// (see Warnings) instead of failing. The fingerprint always reflects the
+// original document bytes.
+//
+// Returns an error if the fingerprint or validation mode is unsupported, the
+// PDF cannot be read or repaired, or the PDF has no pages.
+func OpenPDFWithConfig(path string, cfg config.DocumentConfig) (*PDFDocument, error) {
+	cfg.Finalize()
+
+	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open PDF: %w", err)
 	}
 
+	ctx, repairedPath, warnings, err := openPDF(data, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	cleanup := func() {
+		if repairedPath != "" {
+			os.Remove(repairedPath)
+		}
+	}
+
 	pageCount := ctx.PageCount
 	if pageCount == 0 {
+		cleanup()
 		return nil, fmt.Errorf("PDF has no pages")
 	}
 
+	fingerprint, err := fingerprintPDF(path, data, ctx, cfg.Fingerprint)
+	if err != nil {
+		cleanup()
+		return nil, err
+	}
+
 	return &PDFDocument{
-		path:      path,
-		ctx:       ctx,
-		pageCount: pageCount,
+		path:         path,
+		repairedPath: repairedPath,
+		warnings:     warnings,
+		ctx:          ctx,
+		pageCount:    pageCount,
+		fingerprint:  fingerprint,
 	}, nil
 }
 
@@ -41,6 +88,11 @@ func (d *PDFDocument) PageCount() int {
 	return d.pageCount
 }
 
+// Fingerprint returns the document content fingerprint computed at open time.
+func (d *PDFDocument) Fingerprint() string {
+	return d.fingerprint
+}
+
 func (d *PDFDocument) ExtractPage(pageNum int) (Page, error) {
 	if pageNum < 1 || pageNum > d.pageCount {
 		return nil, fmt.Errorf("page %d out of range [1-%d]", pageNum, d.pageCount)
@@ -66,11 +118,30 @@ func (d *PDFDocument) ExtractAllPages() ([]Page, error) {
 	return pages, nil
 }
 
+// Close releases the parsed document and removes any repaired copy.
 func (d *PDFDocument) Close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
 	d.ctx = nil
+
+	if d.repairedPath != "" {
+		if err := os.Remove(d.repairedPath); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove repaired PDF: %w", err)
+		}
+	}
 	return nil
 }
 
+// source returns the path of the file backing the parsed document: the
+// repaired copy when the document was repaired, otherwise the original.
+func (d *PDFDocument) source() string {
+	if d.repairedPath != "" {
+		return d.repairedPath
+	}
+	return d.path
+}
+
 type PDFPage struct {
 	doc    *PDFDocument
 	number int
@@ -90,7 +161,7 @@ func (p *PDFPage) Number() int {
 //   - renderer: Image renderer implementation (e.g., ImageMagickRenderer)
 //   - c: Optional cache for storing rendered images. Pass nil to disable caching.
 //
-// The cache key is deterministically generated from the document path, page number,
+// The cache key is deterministically generated from the document fingerprint, page number,
 // and all rendering settings (format, DPI, quality, filters). The same inputs always
 // produce the same cache key, enabling reliable cache lookups.
 //
@@ -100,131 +171,142 @@ func (p *PDFPage) Number() int {
 //   - No cache (nil): Always renders page (original behavior)
 //   - Cache errors: Non-ErrCacheEntryNotFound errors are propagated
 //
+// With a byte budget configured, the image is rendered within it as described
+// for Render.
+//
 // Returns the rendered image data as bytes, or an error if rendering fails.
+// Render returns the same image together with its metadata.
 func (p *PDFPage) ToImage(renderer image.Renderer, c cache.Cache) ([]byte, error) {
-	if c != nil {
-		key, err := p.buildCacheKey(renderer)
+	return imageData(p.Render(renderer, c))
+}
+
+// ToBudgetImage renders the page like ToImage and returns the image with the
+// encoding it was rendered with (see Render).
+func (p *PDFPage) ToBudgetImage(renderer image.Renderer, c cache.Cache) (*BudgetImage, error) {
+	return budgetImage(p.Render(renderer, c))
+}
+
+// Render renders the page like ToImage and returns the image with its
+// metadata: format and MIME type, pixel dimensions, size, encoding, cache key
+// and hit, render duration, and renderer parameters (see RenderResult).
+//
+// When the renderer has a byte budget (config.ImageConfig MaxBytes) and the
+// page exceeds it, lossy formats are re-encoded at lower quality, then the
+// page is rendered at lower DPI, until it fits. The result is cached under the
+// budget-based key (see buildCacheKey), and its encoding is recorded in the
+// cache filename (e.g., "document.1.q60-dpi150.jpg") so cache hits report it.
+//
+// Returns an error wrapping ErrByteBudget if the page does not fit the budget.
+func (p *PDFPage) Render(renderer image.Renderer, c cache.Cache) (*RenderResult, error) {
+	dpi, err := p.renderDPI(renderer)
+	if err != nil {
+		return nil, err
+	}
+
+	key := imageCacheKeyAt(p.doc.fingerprint, p.number, renderer, dpi)
+	filename := imageFilename(p.doc.path, p.number, renderer.Settings().Format)
+
+	return renderCached(c, key, filename, renderer, dpi, func() (*BudgetImage, error) {
+		return renderFileAt(renderer, p.doc.source(), p.number, p.number, dpi)
+	})
+}
+
+// WriteImage renders the page like ToImage and writes the image to w,
+// streaming it from the renderer when possible (see StreamPage).
+func (p *PDFPage) WriteImage(w io.Writer, renderer image.Renderer, c cache.Cache) error {
+	return writeImage(w, renderer, c, func(s image.StreamRenderer) error {
+		dpi, err := p.renderDPI(renderer)
 		if err != nil {
-			return nil, err
+			return err
 		}
+		return streamFile(w, s, p.doc.source(), p.number, p.number, dpi)
+	}, func() (*RenderResult, error) {
+		return p.Render(renderer, c)
+	})
+}
 
-		entry, err := c.Get(key)
-		if err == nil {
-			return entry.Data, nil
-		}
-		if !errors.Is(err, cache.ErrCacheEntryNotFound) {
-			return nil, err
-		}
+// renderDPI returns the DPI at which renderer renders the page: fitted to the
+// page's media box when the renderer has fit limits (see
+// config.ImageConfig), otherwise the configured DPI.
+func (p *PDFPage) renderDPI(renderer image.Renderer) (float64, error) {
+	if !image.HasFitLimits(renderer.Settings()) {
+		return float64(renderer.Settings().DPI), nil
 	}
 
-	ext := renderer.FileExtension()
-
-	tmpFile, err := os.CreateTemp("", fmt.Sprintf("page-%d-*.%s", p.number, ext))
+	width, height, err := p.mediaSize()
 	if err != nil {
-		return nil, fmt.Errorf("failed to create temp file: %w", err)
+		return 0, err
 	}
-	tmpPath := tmpFile.Name()
-	tmpFile.Close()
-	defer os.Remove(tmpPath)
+	return fitDPI(renderer, width, height)
+}
 
-	err = renderer.Render(p.doc.path, p.number, tmpPath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to render page %d: %w", p.number, err)
+// mediaSize returns the width and height of the page's media box in points,
+// swapped when the page is rotated by 90 or 270 degrees so they describe the
+// page as displayed.
+func (p *PDFPage) mediaSize() (float64, float64, error) {
+	p.doc.mu.Lock()
+	defer p.doc.mu.Unlock()
+
+	if p.doc.ctx == nil {
+		return 0, 0, fmt.Errorf("document is closed")
 	}
 
-	imgData, err := os.ReadFile(tmpPath)
+	_, _, inherited, err := p.doc.ctx.PageDict(p.number, false)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read rendered image: %w", err)
+		return 0, 0, fmt.Errorf("failed to read page %d: %w", p.number, err)
+	}
+	if inherited.MediaBox == nil {
+		return 0, 0, fmt.Errorf("page %d has no media box", p.number)
 	}
 
-	if c != nil {
-		entry, err := p.prepareCache(imgData, renderer)
-		if err != nil {
-			return nil, err
-		}
-
-		if err := c.Set(entry); err != nil {
-			return nil, err
-		}
+	width, height := inherited.MediaBox.Width(), inherited.MediaBox.Height()
+	if (inherited.Rotate%180+180)%180 == 90 {
+		width, height = height, width
 	}
+	return width, height, nil
+}
 
-	return imgData, nil
+// ImageCacheKey returns the cache key under which ToImage stores the page
+// rendered with renderer.
+//
+// Derived artifacts of the rendered image (e.g., OCR results) build their cache
+// keys from this key so they are invalidated together with the image.
+func (p *PDFPage) ImageCacheKey(renderer image.Renderer) (string, error) {
+	return p.buildCacheKey(renderer)
 }
 
 // buildCacheKey generates a deterministic cache key from page and rendering settings.
 //
 // The cache key uniquely identifies a rendered page based on:
-//   - Document path (normalized to absolute path)
+//   - Document content fingerprint (see DocumentConfig.Fingerprint)
 //   - Page number
-//   - Image format (png, jpg)
-//   - All rendering parameters (DPI, quality, brightness, contrast, saturation, rotation)
+//   - Image format (png, jpg, webp, avif)
+//   - All rendering parameters (DPI, quality, lossless, brightness, contrast, saturation, rotation)
+//
+// With fit limits configured, the key records the DPI fitted to this page
+// (e.g., dpi=142.54) rather than the configured DPI.
 //
 // Key format (before hashing):
 //
-//	/absolute/path/to/document.pdf/1.png?dpi=300&quality=90&brightness=10
+//	sha256:9f86d081.../1.png?dpi=300&quality=90&brightness=10
 //
 // Parameters are included in deterministic order:
 //  1. Mandatory fields (alphabetically): dpi, quality
-//  2. Optional fields present (alphabetically): brightness, contrast, rotation, saturation
+//  2. lossless=true, only for lossless encoding (so existing keys are unchanged)
+//  3. max_bytes, only with a byte budget (the key records the budget, not the
+//     quality and DPI chosen to fit it)
+//  4. Renderer parameters (e.g., brightness, contrast, rotation, saturation)
+//
+// Because the key is derived from document content rather than its location,
+// replacing a document at the same path produces new keys, and the same document
+// opened from different paths shares cache entries.
 //
 // The formatted string is then hashed with SHA256 to produce a 64-character
 // hexadecimal key. The same inputs always produce the same key.
-//
-// Returns an error if the document path cannot be normalized to an absolute path.
 func (p *PDFPage) buildCacheKey(renderer image.Renderer) (string, error) {
-	absPath, err := filepath.Abs(p.doc.path)
+	dpi, err := p.renderDPI(renderer)
 	if err != nil {
-		return "", fmt.Errorf("failed to normalize path: %w", err)
+		return "", err
 	}
-
-	settings := renderer.Settings()
-
-	var builder strings.Builder
-	builder.WriteString(fmt.Sprintf("%s/%d.%s", absPath, p.number, settings.Format))
-
-	params := []string{
-		fmt.Sprintf("dpi=%d", settings.DPI),
-		fmt.Sprintf("quality=%d", settings.Quality),
-	}
-
-	params = append(params, renderer.Parameters()...)
-
-	builder.WriteString(fmt.Sprintf("?%s", strings.Join(params, "&")))
-
-	key := cache.GenerateKey(builder.String())
-	return key, nil
-}
-
-// prepareCache constructs a cache entry from rendered image data and settings.
-//
-// This method creates a complete cache entry with:
-//   - Key: Generated from document path, page number, and rendering settings
-//   - Data: The rendered image bytes
-//   - Filename: Suggested filename in format "basename.pagenum.ext"
-//
-// Filename construction:
-//   - Extracts base filename from document path (without extension)
-//   - Appends page number and output format extension
-//   - Example: "document.pdf" page 1 as PNG → "document.1.png"
-//
-// Returns a complete cache entry ready for storage, or an error if the cache
-// key cannot be generated.
-func (p *PDFPage) prepareCache(data []byte, renderer image.Renderer) (*cache.CacheEntry, error) {
-	key, err := p.buildCacheKey(renderer)
-	if err != nil {
-		return nil, err
-	}
-
-	settings := renderer.Settings()
-	baseName := filepath.Base(p.doc.path)
-	ext := filepath.Ext(baseName)
-	nameWithoutExt := strings.TrimSuffix(baseName, ext)
-
-	filename := fmt.Sprintf("%s.%d.%s", nameWithoutExt, p.number, settings.Format)
-
-	return &cache.CacheEntry{
-		Key:      key,
-		Data:     data,
-		Filename: filename,
-	}, nil
+	return imageCacheKeyAt(p.doc.fingerprint, p.number, renderer, dpi), nil
 }
@@ -0,0 +1,840 @@
+package document
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/JaimeStill/document-context/pkg/cache"
+	"github.com/JaimeStill/document-context/pkg/image"
+)
+
+// emuPerPoint is the number of English Metric Units per PDF point.
+const emuPerPoint = 12700.0
+
+// PPTXDocument is a PowerPoint presentation read natively from its Office
+// Open XML package. Each slide is a page, in presentation order; hidden
+// slides are included and reported by SlidePage.Hidden.
+type PPTXDocument struct {
+	path        string
+	fingerprint string
+	width       float64
+	height      float64
+	slides      []pptxSlide
+	mu          sync.Mutex
+}
+
+type pptxSlide struct {
+	shapes []pptxShape
+	notes  []pptxParagraph
+	hidden bool
+}
+
+// pptxShape is a text shape, table, or picture of a slide, positioned in
+// slide coordinates (points, origin at the bottom-left like PDF user space).
+type pptxShape struct {
+	// placeholder is the placeholder type (e.g., "title", "body"), "obj"
+	// for placeholders without a type, or "" for other shapes.
+	placeholder string
+	index       string
+	bounds      Rect
+	positioned  bool
+
+	paragraphs []pptxParagraph
+	table      [][]string
+	picture    bool
+}
+
+type pptxParagraph struct {
+	text  string
+	level int
+
+	// marker is the bullet ("-") or number ("3.") of list paragraphs.
+	marker string
+
+	// size is the font size of the first run, or 0 when inherited.
+	size float64
+}
+
+// emuTransform maps shape coordinates of a group to slide coordinates.
+type emuTransform struct {
+	offX, offY     float64
+	scaleX, scaleY float64
+}
+
+func (t emuTransform) apply(x, y float64) (float64, float64) {
+	return t.offX + x*t.scaleX, t.offY + y*t.scaleY
+}
+
+// OpenPPTX opens a PowerPoint (.pptx) presentation.
+//
+// Slides are parsed at open time: text shapes, tables, and pictures with their
+// positions (inherited from slide layouts and masters for placeholders),
+// slide titles from title placeholders, and speaker notes from notes slides.
+// The fingerprint is the SHA-256 of the file.
+//
+// Returns an error if the file is not a readable PPTX package.
+func OpenPPTX(path string) (*PPTXDocument, error) {
+	pkg, err := openOOXML(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open PPTX: %w", err)
+	}
+	defer pkg.Close()
+
+	main, err := pkg.mainPart("ppt/presentation.xml")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open PPTX: %w", err)
+	}
+
+	data, err := pkg.read(main)
+	if err != nil {
+		return nil, err
+	}
+	presentation, err := parseXML(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse presentation: %w", err)
+	}
+
+	rels, err := pkg.relationships(main)
+	if err != nil {
+		return nil, err
+	}
+	targets := make(map[string]string, len(rels))
+	for _, r := range rels {
+		targets[r.ID] = r.Target
+	}
+
+	doc := &PPTXDocument{path: path, width: 720, height: 540}
+	size := presentation.child("sldSz")
+	if cx, err := strconv.ParseFloat(size.attr("cx"), 64); err == nil && cx > 0 {
+		doc.width = cx / emuPerPoint
+	}
+	if cy, err := strconv.ParseFloat(size.attr("cy"), 64); err == nil && cy > 0 {
+		doc.height = cy / emuPerPoint
+	}
+
+	parser := &pptxParser{pkg: pkg, width: doc.width, height: doc.height, trees: make(map[string][]pptxShape)}
+
+	for _, id := range presentation.child("sldIdLst").Nodes {
+		target, ok := targets[id.nsAttr("id")]
+		if !ok {
+			return nil, fmt.Errorf("slide %d has no slide part", len(doc.slides)+1)
+		}
+
+		slide, err := parser.parseSlide(target)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse slide %d: %w", len(doc.slides)+1, err)
+		}
+		doc.slides = append(doc.slides, slide)
+	}
+
+	if len(doc.slides) == 0 {
+		return nil, fmt.Errorf("failed to open PPTX: presentation has no slides")
+	}
+
+	doc.fingerprint, err = contentFingerprint(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return doc, nil
+}
+
+func (d *PPTXDocument) PageCount() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return len(d.slides)
+}
+
+// Fingerprint returns the SHA-256 of the presentation file computed at open
+// time.
+func (d *PPTXDocument) Fingerprint() string {
+	return d.fingerprint
+}
+
+func (d *PPTXDocument) ExtractPage(pageNum int) (Page, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if pageNum < 1 || pageNum > len(d.slides) {
+		return nil, fmt.Errorf("page %d out of range [1-%d]", pageNum, len(d.slides))
+	}
+	return &SlidePage{doc: d, number: pageNum}, nil
+}
+
+func (d *PPTXDocument) ExtractAllPages() ([]Page, error) {
+	pages := make([]Page, 0, d.PageCount())
+	for i := 1; i <= d.PageCount(); i++ {
+		page, err := d.ExtractPage(i)
+		if err != nil {
+			return nil, fmt.Errorf("failed to extract page %d: %w", i, err)
+		}
+		pages = append(pages, page)
+	}
+	return pages, nil
+}
+
+// Close releases the parsed presentation content.
+func (d *PPTXDocument) Close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.slides = nil
+	return nil
+}
+
+// SlidePage is a slide of a PPTXDocument.
+type SlidePage struct {
+	doc    *PPTXDocument
+	number int
+}
+
+func (p *SlidePage) Number() int {
+	return p.number
+}
+
+// slide returns a copy of the slide content, or an error if the document is
+// closed.
+func (p *SlidePage) slide() (pptxSlide, error) {
+	p.doc.mu.Lock()
+	defer p.doc.mu.Unlock()
+
+	if p.doc.slides == nil {
+		return pptxSlide{}, fmt.Errorf("document is closed")
+	}
+	return p.doc.slides[p.number-1], nil
+}
+
+// Title returns the text of the slide's title placeholder, or "" if the
+// slide has no title.
+func (p *SlidePage) Title() (string, error) {
+	slide, err := p.slide()
+	if err != nil {
+		return "", err
+	}
+
+	for _, shape := range slide.shapes {
+		if shape.isTitle() {
+			return strings.Join(strings.Fields(joinParagraphs(shape.paragraphs, " ")), " "), nil
+		}
+	}
+	return "", nil
+}
+
+// Hidden reports whether the slide is hidden in slide shows.
+func (p *SlidePage) Hidden() (bool, error) {
+	slide, err := p.slide()
+	if err != nil {
+		return false, err
+	}
+	return slide.hidden, nil
+}
+
+// Text returns the slide text: the title first, then the remaining shapes
+// top to bottom and left to right. Paragraphs are separated by newlines,
+// list paragraphs keep their bullet or number, and table cells are separated
+// by tabs. Speaker notes are not included (see Notes).
+func (p *SlidePage) Text() (string, error) {
+	slide, err := p.slide()
+	if err != nil {
+		return "", err
+	}
+	return renderSlideShapes(slide.shapes, false), nil
+}
+
+// Notes returns the speaker notes of the slide, or "" if it has none.
+func (p *SlidePage) Notes() (string, error) {
+	slide, err := p.slide()
+	if err != nil {
+		return "", err
+	}
+	return renderParagraphs(slide.notes, false), nil
+}
+
+// Markdown returns the slide as Markdown.
+//
+// The title is a level-one heading, lists are indented by level, and tables
+// are rendered as GitHub-flavored Markdown tables. Speaker notes follow the
+// slide content under a "Notes:" label.
+func (p *SlidePage) Markdown() (string, error) {
+	slide, err := p.slide()
+	if err != nil {
+		return "", err
+	}
+
+	content := renderSlideShapes(slide.shapes, true)
+	if notes := renderParagraphs(slide.notes, true); notes != "" {
+		if content != "" {
+			content += "\n\n"
+		}
+		content += "**Notes:**\n\n" + notes
+	}
+	return content, nil
+}
+
+// ToImage renders the slide as an image.
+//
+// The slide is laid out directly as a single-page PDF at the presentation's
+// slide size: text shapes at their positions (titles in bold), tables as
+// ruled grids, and pictures as gray placeholders. Theme backgrounds, images,
+// and exact fonts are not reproduced. The PDF is rendered through renderer,
+// with caching as in PDFPage.ToImage.
+func (p *SlidePage) ToImage(renderer image.Renderer, c cache.Cache) ([]byte, error) {
+	return imageData(p.Render(renderer, c))
+}
+
+// ToBudgetImage renders the slide like ToImage and returns the image with
+// the encoding it was rendered with (see Render).
+func (p *SlidePage) ToBudgetImage(renderer image.Renderer, c cache.Cache) (*BudgetImage, error) {
+	return budgetImage(p.Render(renderer, c))
+}
+
+// Render renders the slide like ToImage and returns the image with its
+// metadata (see PDFPage.Render).
+func (p *SlidePage) Render(renderer image.Renderer, c cache.Cache) (*RenderResult, error) {
+	dpi, err := p.renderDPI(renderer)
+	if err != nil {
+		return nil, err
+	}
+
+	key := imageCacheKeyAt(p.doc.fingerprint, p.number, renderer, dpi)
+	filename := imageFilename(p.doc.path, p.number, renderer.Settings().Format)
+
+	return renderCached(c, key, filename, renderer, dpi, func() (*BudgetImage, error) {
+		pdf, err := p.pdf()
+		if err != nil {
+			return nil, err
+		}
+		return renderPDFData(renderer, pdf, p.number, dpi)
+	})
+}
+
+// WriteImage renders the slide like ToImage and writes the image to w,
+// streaming it from the renderer when possible (see StreamPage).
+func (p *SlidePage) WriteImage(w io.Writer, renderer image.Renderer, c cache.Cache) error {
+	return writeImage(w, renderer, c, func(s image.StreamRenderer) error {
+		dpi, err := p.renderDPI(renderer)
+		if err != nil {
+			return err
+		}
+		pdf, err := p.pdf()
+		if err != nil {
+			return err
+		}
+		return streamPDFData(w, s, pdf, p.number, dpi)
+	}, func() (*RenderResult, error) {
+		return p.Render(renderer, c)
+	})
+}
+
+// pdf lays out the slide as the single-page PDF rendered by ToImage.
+func (p *SlidePage) pdf() (pagePDF, error) {
+	slide, err := p.slide()
+	if err != nil {
+		return pagePDF{}, err
+	}
+	return writeSlidePDF(p.doc.width, p.doc.height, slide.shapes), nil
+}
+
+// renderDPI returns the DPI at which renderer renders the slide: fitted to
+// the presentation's slide size when the renderer has fit limits (see
+// config.ImageConfig), otherwise the configured DPI.
+func (p *SlidePage) renderDPI(renderer image.Renderer) (float64, error) {
+	return fitDPI(renderer, p.doc.width, p.doc.height)
+}
+
+func (s pptxShape) isTitle() bool {
+	return s.placeholder == "title" || s.placeholder == "ctrTitle"
+}
+
+// renderSlideShapes renders shapes in reading order: titles first, then by
+// position from the top-left.
+func renderSlideShapes(shapes []pptxShape, markdown bool) string {
+	ordered := make([]pptxShape, 0, len(shapes))
+	for _, s := range shapes {
+		if !s.picture {
+			ordered = append(ordered, s)
+		}
+	}
+	sort.SliceStable(ordered, func(i, j int) bool {
+		a, b := ordered[i], ordered[j]
+		if a.isTitle() != b.isTitle() {
+			return a.isTitle()
+		}
+		if a.bounds.Y1 != b.bounds.Y1 {
+			return a.bounds.Y1 > b.bounds.Y1
+		}
+		return a.bounds.X0 < b.bounds.X0
+	})
+
+	sep := "\n"
+	if markdown {
+		sep = "\n\n"
+	}
+
+	var blocks []string
+	for _, s := range ordered {
+		var text string
+		switch {
+		case s.table != nil:
+			text = renderSlideTable(s.table, markdown)
+		case s.isTitle() && markdown:
+			if title := strings.Join(strings.Fields(joinParagraphs(s.paragraphs, " ")), " "); title != "" {
+				text = "# " + title
+			}
+		default:
+			text = renderParagraphs(s.paragraphs, markdown)
+		}
+		if text != "" {
+			blocks = append(blocks, text)
+		}
+	}
+	return strings.Join(blocks, sep)
+}
+
+// renderParagraphs renders paragraphs with list markers indented by level. In
+// Markdown, paragraphs are separated by blank lines except between
+// consecutive list items.
+func renderParagraphs(paragraphs []pptxParagraph, markdown bool) string {
+	var builder strings.Builder
+	prevList := false
+	first := true
+
+	for _, para := range paragraphs {
+		if strings.TrimSpace(para.text) == "" {
+			continue
+		}
+
+		list := para.marker != ""
+		if !first {
+			if markdown && !(prevList && list) {
+				builder.WriteString("\n\n")
+			} else {
+				builder.WriteString("\n")
+			}
+		}
+		first = false
+		prevList = list
+
+		if list {
+			builder.WriteString(strings.Repeat("  ", para.level) + para.marker + " ")
+		}
+		builder.WriteString(para.text)
+	}
+	return builder.String()
+}
+
+func joinParagraphs(paragraphs []pptxParagraph, sep string) string {
+	texts := make([]string, 0, len(paragraphs))
+	for _, para := range paragraphs {
+		texts = append(texts, para.text)
+	}
+	return strings.Join(texts, sep)
+}
+
+func renderSlideTable(rows [][]string, markdown bool) string {
+	table := Table{Cells: make([][]Cell, len(rows))}
+	for i, row := range rows {
+		table.Cells[i] = make([]Cell, len(row))
+		for j, text := range row {
+			table.Cells[i][j] = Cell{Text: text}
+		}
+	}
+
+	if markdown {
+		return strings.TrimSuffix(table.Markdown(), "\n")
+	}
+
+	lines := make([]string, len(rows))
+	for i, row := range table.Rows() {
+		for j, cell := range row {
+			row[j] = strings.ReplaceAll(cell, "\n", " ")
+		}
+		lines[i] = strings.Join(row, "\t")
+	}
+	return strings.Join(lines, "\n")
+}
+
+type pptxParser struct {
+	pkg    *ooxmlPackage
+	width  float64
+	height float64
+
+	// trees caches the shapes of layouts and masters by part name.
+	trees map[string][]pptxShape
+}
+
+func (p *pptxParser) parseSlide(name string) (pptxSlide, error) {
+	root, err := p.readPart(name)
+	if err != nil {
+		return pptxSlide{}, err
+	}
+
+	slide := pptxSlide{
+		shapes: p.shapeTree(root.child("cSld", "spTree"), identityTransform()),
+		hidden: root.attr("show") == "0" || root.attr("show") == "false",
+	}
+
+	// Placeholders inherit positions from the layout, then its master.
+	layout, err := p.pkg.related(name, "/slideLayout")
+	if err != nil {
+		return pptxSlide{}, err
+	}
+	master := ""
+	if layout != "" {
+		if master, err = p.pkg.related(layout, "/slideMaster"); err != nil {
+			return pptxSlide{}, err
+		}
+	}
+	for _, part := range []string{layout, master} {
+		inherited, err := p.templateShapes(part)
+		if err != nil {
+			return pptxSlide{}, err
+		}
+		p.inheritBounds(slide.shapes, inherited)
+	}
+	p.defaultBounds(slide.shapes)
+
+	notes, err := p.pkg.related(name, "/notesSlide")
+	if err != nil {
+		return pptxSlide{}, err
+	}
+	if notes != "" {
+		root, err := p.readPart(notes)
+		if err != nil {
+			return pptxSlide{}, err
+		}
+
+		// The notes text is the body placeholder; the others hold the slide
+		// image, header, and slide number. Notes paragraphs are not
+		// bulleted by default.
+		tree := root.child("cSld", "spTree")
+		for i := range tree.Nodes {
+			node := &tree.Nodes[i]
+			if node.XMLName.Local == "sp" && node.child("nvSpPr", "nvPr", "ph").attr("type") == "body" {
+				slide.notes = append(slide.notes, parseTextBody(node.child("txBody"), false)...)
+			}
+		}
+	}
+
+	return slide, nil
+}
+
+// templateShapes returns the shapes of a slide layout or master, or nil when
+// name is "".
+func (p *pptxParser) templateShapes(name string) ([]pptxShape, error) {
+	if name == "" {
+		return nil, nil
+	}
+	if shapes, ok := p.trees[name]; ok {
+		return shapes, nil
+	}
+
+	root, err := p.readPart(name)
+	if err != nil {
+		return nil, err
+	}
+	shapes := p.shapeTree(root.child("cSld", "spTree"), identityTransform())
+
+	p.trees[name] = shapes
+	return shapes, nil
+}
+
+// inheritBounds positions placeholders without their own position from the
+// matching placeholder of the layout or master: by index, then by type.
+func (p *pptxParser) inheritBounds(shapes, inherited []pptxShape) {
+	for i := range shapes {
+		s := &shapes[i]
+		if s.positioned || s.placeholder == "" {
+			continue
+		}
+		if match := findPlaceholder(inherited, s.placeholder, s.index); match != nil && match.positioned {
+			s.bounds = match.bounds
+			s.positioned = true
+		}
+	}
+}
+
+// defaultBounds places shapes whose position could not be resolved: titles
+// in a band at the top of the slide, other shapes in the area below it.
+func (p *pptxParser) defaultBounds(shapes []pptxShape) {
+	for i := range shapes {
+		s := &shapes[i]
+		if s.positioned {
+			continue
+		}
+		if s.isTitle() {
+			s.bounds = Rect{X0: 0.05 * p.width, Y0: 0.8 * p.height, X1: 0.95 * p.width, Y1: 0.95 * p.height}
+		} else {
+			s.bounds = Rect{X0: 0.05 * p.width, Y0: 0.05 * p.height, X1: 0.95 * p.width, Y1: 0.78 * p.height}
+		}
+		s.positioned = true
+	}
+}
+
+func findPlaceholder(shapes []pptxShape, kind, index string) *pptxShape {
+	if index != "" {
+		for i := range shapes {
+			if shapes[i].placeholder != "" && shapes[i].index == index {
+				return &shapes[i]
+			}
+		}
+	}
+	for i := range shapes {
+		if shapes[i].placeholder == kind {
+			return &shapes[i]
+		}
+	}
+
+	// Masters define only generic placeholders.
+	generic := map[string]string{"ctrTitle": "title", "subTitle": "body", "obj": "body"}[kind]
+	for i := range shapes {
+		if generic != "" && shapes[i].placeholder == generic {
+			return &shapes[i]
+		}
+	}
+	return nil
+}
+
+func (p *pptxParser) readPart(name string) (*xmlNode, error) {
+	data, err := p.pkg.read(name)
+	if err != nil {
+		return nil, err
+	}
+	root, err := parseXML(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", name, err)
+	}
+	return root, nil
+}
+
+func identityTransform() emuTransform {
+	return emuTransform{scaleX: 1, scaleY: 1}
+}
+
+// shapeTree collects the shapes of a shape tree or group, in document order.
+func (p *pptxParser) shapeTree(tree *xmlNode, t emuTransform) []pptxShape {
+	if tree == nil {
+		return nil
+	}
+
+	var shapes []pptxShape
+	for i := range tree.Nodes {
+		node := &tree.Nodes[i]
+
+		switch node.XMLName.Local {
+		case "sp":
+			shape := pptxShape{}
+			p.placeholder(&shape, node.child("nvSpPr", "nvPr", "ph"))
+			p.position(&shape, node.child("spPr", "xfrm"), t)
+			shape.paragraphs = parseTextBody(node.child("txBody"), shape.bulleted())
+			shapes = append(shapes, shape)
+
+		case "pic":
+			shape := pptxShape{picture: true}
+			p.placeholder(&shape, node.child("nvPicPr", "nvPr", "ph"))
+			p.position(&shape, node.child("spPr", "xfrm"), t)
+			shapes = append(shapes, shape)
+
+		case "graphicFrame":
+			shape := pptxShape{}
+			p.placeholder(&shape, node.child("nvGraphicFramePr", "nvPr", "ph"))
+			p.position(&shape, node.child("xfrm"), t)
+			if tbl := node.child("graphic", "graphicData", "tbl"); tbl != nil {
+				shape.table = parseSlideTable(tbl)
+				shapes = append(shapes, shape)
+			}
+
+		case "grpSp":
+			shapes = append(shapes, p.shapeTree(node, groupTransform(node.child("grpSpPr", "xfrm"), t))...)
+
+		case "AlternateContent":
+			if choice := node.child("Choice"); choice != nil {
+				shapes = append(shapes, p.shapeTree(choice, t)...)
+			}
+		}
+	}
+	return shapes
+}
+
+func (p *pptxParser) placeholder(shape *pptxShape, ph *xmlNode) {
+	if ph == nil {
+		return
+	}
+	shape.placeholder = ph.attr("type")
+	if shape.placeholder == "" {
+		shape.placeholder = "obj"
+	}
+	shape.index = ph.attr("idx")
+}
+
+// position sets the bounds of shape from an xfrm element, converting EMUs in
+// group coordinates to points in PDF orientation.
+func (p *pptxParser) position(shape *pptxShape, xfrm *xmlNode, t emuTransform) {
+	off, ext := xfrm.child("off"), xfrm.child("ext")
+	if off == nil || ext == nil {
+		return
+	}
+
+	x, y := emuAttr(off, "x"), emuAttr(off, "y")
+	cx, cy := emuAttr(ext, "cx"), emuAttr(ext, "cy")
+
+	x0, y0 := t.apply(x, y)
+	x1, y1 := t.apply(x+cx, y+cy)
+
+	shape.bounds = Rect{
+		X0: x0 / emuPerPoint,
+		Y0: p.height - y1/emuPerPoint,
+		X1: x1 / emuPerPoint,
+		Y1: p.height - y0/emuPerPoint,
+	}
+	shape.positioned = true
+}
+
+// groupTransform composes the child coordinate mapping of a group with its
+// parent transform.
+func groupTransform(xfrm *xmlNode, parent emuTransform) emuTransform {
+	off, ext := xfrm.child("off"), xfrm.child("ext")
+	chOff, chExt := xfrm.child("chOff"), xfrm.child("chExt")
+	if off == nil || ext == nil {
+		return parent
+	}
+
+	sx, sy := 1.0, 1.0
+	if w := emuAttr(chExt, "cx"); w > 0 {
+		sx = emuAttr(ext, "cx") / w
+	}
+	if h := emuAttr(chExt, "cy"); h > 0 {
+		sy = emuAttr(ext, "cy") / h
+	}
+
+	return emuTransform{
+		offX:   parent.offX + parent.scaleX*(emuAttr(off, "x")-emuAttr(chOff, "x")*sx),
+		offY:   parent.offY + parent.scaleY*(emuAttr(off, "y")-emuAttr(chOff, "y")*sy),
+		scaleX: parent.scaleX * sx,
+		scaleY: parent.scaleY * sy,
+	}
+}
+
+func emuAttr(n *xmlNode, local string) float64 {
+	v, _ := strconv.ParseFloat(n.attr(local), 64)
+	return v
+}
+
+// bulleted reports whether paragraphs of the shape are bulleted unless they
+// say otherwise: body and content placeholders are, titles and free text
+// boxes are not.
+func (s pptxShape) bulleted() bool {
+	return s.placeholder == "body" || s.placeholder == "obj"
+}
+
+// parseTextBody reads the paragraphs of a text body. Numbered paragraphs
+// are counted per level, restarting when a paragraph at a shallower level or
+// an unnumbered paragraph at the same level intervenes.
+func parseTextBody(body *xmlNode, bulleted bool) []pptxParagraph {
+	if body == nil {
+		return nil
+	}
+
+	var paragraphs []pptxParagraph
+	counters := make(map[int]int)
+
+	for i := range body.Nodes {
+		node := &body.Nodes[i]
+		if node.XMLName.Local != "p" {
+			continue
+		}
+
+		para := pptxParagraph{}
+		pPr := node.child("pPr")
+		para.level, _ = strconv.Atoi(pPr.attr("lvl"))
+
+		var builder strings.Builder
+		for j := range node.Nodes {
+			run := &node.Nodes[j]
+			switch run.XMLName.Local {
+			case "r", "fld":
+				builder.WriteString(run.child("t").textContent())
+				if para.size == 0 {
+					if sz, err := strconv.ParseFloat(run.child("rPr").attr("sz"), 64); err == nil {
+						para.size = sz / 100
+					}
+				}
+			case "br":
+				builder.WriteString("\n")
+			}
+		}
+		para.text = builder.String()
+
+		for level := range counters {
+			if level > para.level {
+				delete(counters, level)
+			}
+		}
+
+		switch {
+		case pPr.child("buAutoNum") != nil:
+			if _, ok := counters[para.level]; !ok {
+				start, err := strconv.Atoi(pPr.child("buAutoNum").attr("startAt"))
+				if err != nil {
+					start = 1
+				}
+				counters[para.level] = start - 1
+			}
+			if strings.TrimSpace(para.text) != "" {
+				counters[para.level]++
+			}
+			para.marker = strconv.Itoa(counters[para.level]) + "."
+		case pPr.child("buNone") != nil:
+			delete(counters, para.level)
+		case pPr.child("buChar") != nil || bulleted:
+			delete(counters, para.level)
+			para.marker = "-"
+		default:
+			delete(counters, para.level)
+		}
+
+		paragraphs = append(paragraphs, para)
+	}
+	return paragraphs
+}
+
+// parseSlideTable reads the cell text of a DrawingML table. Cells covered by
+// a merge are empty.
+func parseSlideTable(tbl *xmlNode) [][]string {
+	var rows [][]string
+	for i := range tbl.Nodes {
+		tr := &tbl.Nodes[i]
+		if tr.XMLName.Local != "tr" {
+			continue
+		}
+
+		var row []string
+		for j := range tr.Nodes {
+			tc := &tr.Nodes[j]
+			if tc.XMLName.Local != "tc" {
+				continue
+			}
+			if tc.attr("hMerge") != "" || tc.attr("vMerge") != "" {
+				row = append(row, "")
+				continue
+			}
+			row = append(row, strings.TrimSpace(joinParagraphs(parseTextBody(tc.child("txBody"), false), "\n")))
+		}
+		rows = append(rows, row)
+	}
+
+	cols := 0
+	for _, row := range rows {
+		cols = max(cols, len(row))
+	}
+	for i := range rows {
+		for len(rows[i]) < cols {
+			rows[i] = append(rows[i], "")
+		}
+	}
+	return rows
+}
@@ -0,0 +1,216 @@
+package document
+
+import (
+	"fmt"
+	stdimage "image"
+	_ "image/gif"
+	_ "image/jpeg"
+	"image/png"
+	"io"
+	"math"
+	"os"
+
+	"github.com/JaimeStill/document-context/pkg/cache"
+	"github.com/JaimeStill/document-context/pkg/image"
+	"golang.org/x/image/draw"
+)
+
+// ImageDocument is a raster image (PNG, JPEG, or GIF) treated as a
+// single-page document, so images can be processed alongside other formats
+// (e.g., as email attachments).
+type ImageDocument struct {
+	path        string
+	fingerprint string
+	format      string
+	width       int
+	height      int
+}
+
+// OpenImage opens a PNG, JPEG, or GIF image as a single-page document.
+//
+// Only the image header is decoded at open time, to validate the format and
+// read the dimensions. The fingerprint is the SHA-256 of the file.
+//
+// Returns an error if the file cannot be read or is not a supported image.
+func OpenImage(path string) (*ImageDocument, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open image: %w", err)
+	}
+	defer f.Close()
+
+	cfg, format, err := stdimage.DecodeConfig(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	fingerprint, err := contentFingerprint(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ImageDocument{
+		path:        path,
+		fingerprint: fingerprint,
+		format:      format,
+		width:       cfg.Width,
+		height:      cfg.Height,
+	}, nil
+}
+
+func (d *ImageDocument) PageCount() int {
+	return 1
+}
+
+// Fingerprint returns the SHA-256 of the image file computed at open time.
+func (d *ImageDocument) Fingerprint() string {
+	return d.fingerprint
+}
+
+// Format returns the decoded image format ("png", "jpeg", or "gif").
+func (d *ImageDocument) Format() string {
+	return d.format
+}
+
+// Size returns the image dimensions in pixels.
+func (d *ImageDocument) Size() (width, height int) {
+	return d.width, d.height
+}
+
+func (d *ImageDocument) ExtractPage(pageNum int) (Page, error) {
+	if pageNum != 1 {
+		return nil, fmt.Errorf("page %d out of range [1-1]", pageNum)
+	}
+	return &ImagePage{doc: d}, nil
+}
+
+func (d *ImageDocument) ExtractAllPages() ([]Page, error) {
+	return []Page{&ImagePage{doc: d}}, nil
+}
+
+// Close is a no-op; the image is read from disk when rendered.
+func (d *ImageDocument) Close() error {
+	return nil
+}
+
+// ImagePage is the single page of an ImageDocument.
+type ImagePage struct {
+	doc *ImageDocument
+}
+
+func (p *ImagePage) Number() int {
+	return 1
+}
+
+// ToImage converts the image through renderer, applying its format, quality,
+// and filters. Caching follows PDFPage.ToImage, with keys derived from the
+// image fingerprint.
+//
+// When the renderer has fit limits (see config.ImageConfig), each pixel is
+// treated as a point (72 DPI) and the image is resampled to the fitted size
+// before conversion, since renderers do not resample raster input by DPI.
+func (p *ImagePage) ToImage(renderer image.Renderer, c cache.Cache) ([]byte, error) {
+	return imageData(p.Render(renderer, c))
+}
+
+// ToBudgetImage converts the image like ToImage and returns it with the
+// encoding it was rendered with (see Render).
+func (p *ImagePage) ToBudgetImage(renderer image.Renderer, c cache.Cache) (*BudgetImage, error) {
+	return budgetImage(p.Render(renderer, c))
+}
+
+// Render converts the image like ToImage and returns the image with its
+// metadata (see PDFPage.Render).
+func (p *ImagePage) Render(renderer image.Renderer, c cache.Cache) (*RenderResult, error) {
+	dpi, err := p.renderDPI(renderer)
+	if err != nil {
+		return nil, err
+	}
+
+	key := imageCacheKeyAt(p.doc.fingerprint, 1, renderer, dpi)
+	filename := imageFilename(p.doc.path, 1, renderer.Settings().Format)
+
+	return renderCached(c, key, filename, renderer, dpi, func() (*BudgetImage, error) {
+		path, err := p.source(renderer, dpi)
+		if err != nil {
+			return nil, err
+		}
+		if path != p.doc.path {
+			defer os.Remove(path)
+		}
+		return renderFileAt(renderer, path, 1, 1, dpi)
+	})
+}
+
+// WriteImage converts the image like ToImage and writes it to w, streaming
+// it from the renderer when possible (see StreamPage).
+func (p *ImagePage) WriteImage(w io.Writer, renderer image.Renderer, c cache.Cache) error {
+	return writeImage(w, renderer, c, func(s image.StreamRenderer) error {
+		dpi, err := p.renderDPI(renderer)
+		if err != nil {
+			return err
+		}
+		path, err := p.source(renderer, dpi)
+		if err != nil {
+			return err
+		}
+		if path != p.doc.path {
+			defer os.Remove(path)
+		}
+		return streamFile(w, s, path, 1, 1, dpi)
+	}, func() (*RenderResult, error) {
+		return p.Render(renderer, c)
+	})
+}
+
+// renderDPI returns the DPI at which renderer converts the image: fitted to
+// the pixel dimensions taken as points when the renderer has fit limits (see
+// config.ImageConfig), otherwise the configured DPI.
+func (p *ImagePage) renderDPI(renderer image.Renderer) (float64, error) {
+	return fitDPI(renderer, float64(p.doc.width), float64(p.doc.height))
+}
+
+// source returns the path of the image to convert at dpi: the image itself
+// without fit limits or when it already has the fitted size, otherwise a
+// temporary PNG resampled to dpi/72 of its size, which the caller removes.
+func (p *ImagePage) source(renderer image.Renderer, dpi float64) (string, error) {
+	if !image.HasFitLimits(renderer.Settings()) {
+		return p.doc.path, nil
+	}
+
+	width := max(1, int(math.Round(float64(p.doc.width)*dpi/72)))
+	height := max(1, int(math.Round(float64(p.doc.height)*dpi/72)))
+	if width == p.doc.width && height == p.doc.height {
+		return p.doc.path, nil
+	}
+
+	f, err := os.Open(p.doc.path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open image: %w", err)
+	}
+	src, _, err := stdimage.Decode(f)
+	f.Close()
+	if err != nil {
+		return "", fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	dst := stdimage.NewRGBA(stdimage.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, src.Bounds(), draw.Src, nil)
+
+	tmpFile, err := os.CreateTemp("", "image-*.png")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp file: %w", err)
+	}
+	tmpPath := tmpFile.Name()
+
+	err = png.Encode(tmpFile, dst)
+	if closeErr := tmpFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		return "", fmt.Errorf("failed to write resampled image: %w", err)
+	}
+
+	return tmpPath, nil
+}
@@ -0,0 +1,440 @@
+package document
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	stdimage "image"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/JaimeStill/document-context/pkg/cache"
+	"github.com/JaimeStill/document-context/pkg/image"
+	_ "golang.org/x/image/webp"
+)
+
+// Byte-budget search parameters (see renderWithinBudget).
+const (
+	budgetQualityStep = 10 // Quality decrease per attempt
+	budgetMinQuality  = 40 // Lowest quality tried before reducing resolution
+	budgetMinDPI      = 36 // Lowest DPI tried before giving up
+)
+
+// budgetFilenamePattern matches the encoding recorded in the filename of a
+// budgeted cache entry (e.g., "document.1.q60-dpi150.jpg").
+var budgetFilenamePattern = regexp.MustCompile(`\.q(\d+)-dpi(\d+(?:\.\d+)?)\.[^.]+$`)
+
+// renderCached returns the image stored under key in c, or renders it and
+// stores the result under key with the given filename. A nil cache always
+// renders. Cache errors other than ErrCacheEntryNotFound are propagated.
+//
+// Images rendered without a byte budget use the configured quality and dpi.
+// With a budget, the chosen encoding is recorded in the cache filename
+// (see budgetFilename) so cache hits report it; entries without a readable
+// encoding are rendered again.
+//
+// The result carries the image metadata (see newRenderResult), timed from
+// the cache lookup.
+func renderCached(c cache.Cache, key, filename string, renderer image.Renderer, dpi float64, render func() (*BudgetImage, error)) (*RenderResult, error) {
+	start := time.Now()
+	settings := renderer.Settings()
+	budgeted := settings.MaxBytes > 0
+
+	if c != nil {
+		entry, err := c.Get(key)
+		if err != nil && !errors.Is(err, cache.ErrCacheEntryNotFound) {
+			return nil, err
+		}
+		if err == nil {
+			img := &BudgetImage{
+				Data:     entry.Data,
+				Encoding: image.Encoding{DPI: dpi, Quality: settings.Quality},
+			}
+
+			if !budgeted {
+				return newRenderResult(renderer, img, key, true, start), nil
+			}
+			if enc, ok := parseBudgetFilename(entry.Filename); ok {
+				img.Encoding = enc
+				return newRenderResult(renderer, img, key, true, start), nil
+			}
+		}
+	}
+
+	img, err := render()
+	if err != nil {
+		return nil, err
+	}
+
+	if c != nil {
+		if budgeted {
+			filename = budgetFilename(filename, img.Encoding)
+		}
+		if err := c.Set(&cache.CacheEntry{Key: key, Data: img.Data, Filename: filename}); err != nil {
+			return nil, err
+		}
+	}
+
+	return newRenderResult(renderer, img, key, false, start), nil
+}
+
+// newRenderResult describes img, rendered by renderer and cached under key,
+// with its duration measured from start.
+//
+// The format is parsed from the renderer settings, falling back to the
+// configured name for formats ParseImageFormat does not recognize, and the
+// dimensions are read from the image header (see imageSize).
+func newRenderResult(renderer image.Renderer, img *BudgetImage, key string, hit bool, start time.Time) *RenderResult {
+	settings := renderer.Settings()
+
+	format, err := ParseImageFormat(settings.Format)
+	if err != nil {
+		format = ImageFormat(settings.Format)
+	}
+	mimeType, _ := format.MimeType()
+	width, height := imageSize(img.Data)
+
+	return &RenderResult{
+		Data:       img.Data,
+		Format:     format,
+		MimeType:   mimeType,
+		Width:      width,
+		Height:     height,
+		Size:       len(img.Data),
+		Encoding:   img.Encoding,
+		CacheKey:   key,
+		CacheHit:   hit,
+		Duration:   time.Since(start),
+		Parameters: renderer.Parameters(),
+	}
+}
+
+// imageSize returns the pixel dimensions recorded in the header of an
+// encoded image: PNG, JPEG, GIF, and WebP through their decoders, AVIF from
+// its image spatial extents ('ispe') property. Returns zeros for other or
+// malformed data.
+func imageSize(data []byte) (int, int) {
+	if cfg, _, err := stdimage.DecodeConfig(bytes.NewReader(data)); err == nil {
+		return cfg.Width, cfg.Height
+	}
+
+	// An AVIF file starts with an 'ftyp' box; the first 'ispe' property box
+	// (size, type, version and flags, then 32-bit width and height) describes
+	// the primary image.
+	if len(data) < 12 || string(data[4:8]) != "ftyp" {
+		return 0, 0
+	}
+	i := bytes.Index(data, []byte("ispe"))
+	if i < 4 || len(data) < i+16 {
+		return 0, 0
+	}
+	return int(binary.BigEndian.Uint32(data[i+8:])), int(binary.BigEndian.Uint32(data[i+12:]))
+}
+
+// imageData returns the data of a rendered image, for ToImage methods
+// wrapping Render.
+func imageData(result *RenderResult, err error) ([]byte, error) {
+	if err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
+
+// budgetImage returns the data and encoding of a rendered image, for
+// ToBudgetImage methods wrapping Render.
+func budgetImage(result *RenderResult, err error) (*BudgetImage, error) {
+	if err != nil {
+		return nil, err
+	}
+	return &BudgetImage{Data: result.Data, Encoding: result.Encoding}, nil
+}
+
+// renderFileAt renders page pageNum of the document at path at the given
+// DPI, within the renderer's byte budget when one is configured (see
+// renderWithinBudget). number is the page number reported in errors and
+// temporary file names.
+func renderFileAt(renderer image.Renderer, path string, pageNum, number int, dpi float64) (*BudgetImage, error) {
+	return renderWithinBudget(renderer, dpi, func(enc image.Encoding) ([]byte, error) {
+		return renderEncoded(renderer, path, pageNum, number, enc)
+	})
+}
+
+// renderWithinBudget renders with render at the configured quality and the
+// given DPI. When the renderer has a byte budget (config.ImageConfig
+// MaxBytes) and the output exceeds it, the page is rendered again:
+//  1. Lossy formats lower quality in steps of budgetQualityStep, down to
+//     budgetMinQuality (or the configured quality, if lower)
+//  2. The DPI is then reduced, assuming size proportional to pixel count,
+//     by a factor of 0.5-0.9 per attempt
+//
+// Returns the first image within the budget with its encoding, or an error
+// wrapping ErrByteBudget when the DPI would fall below budgetMinDPI.
+func renderWithinBudget(renderer image.Renderer, dpi float64, render func(image.Encoding) ([]byte, error)) (*BudgetImage, error) {
+	settings := renderer.Settings()
+	enc := image.Encoding{DPI: dpi, Quality: settings.Quality}
+
+	data, err := render(enc)
+	if err != nil {
+		return nil, err
+	}
+
+	fits := func() bool {
+		return settings.MaxBytes <= 0 || len(data) <= settings.MaxBytes
+	}
+
+	if image.IsLossy(settings) {
+		for !fits() && enc.Quality > budgetMinQuality {
+			enc.Quality = max(enc.Quality-budgetQualityStep, budgetMinQuality)
+			if data, err = render(enc); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for !fits() {
+		scale := math.Sqrt(float64(settings.MaxBytes)/float64(len(data))) * 0.95
+		next := math.Floor(enc.DPI*min(max(scale, 0.5), 0.9)*100) / 100
+		if next < budgetMinDPI {
+			return nil, fmt.Errorf("%w: %d bytes at quality %d and %g DPI, limit %d",
+				ErrByteBudget, len(data), enc.Quality, enc.DPI, settings.MaxBytes)
+		}
+
+		enc.DPI = next
+		if data, err = render(enc); err != nil {
+			return nil, err
+		}
+	}
+
+	return &BudgetImage{Data: data, Encoding: enc}, nil
+}
+
+// renderEncoded renders page pageNum of the document at path with enc and
+// returns the image data. number is the page number reported in errors and
+// temporary file names.
+//
+// An image.StreamRenderer renders into memory. Other renderers render
+// through a temporary output file: the configured encoding through Render,
+// other DPIs through an image.FitRenderer, and other qualities through an
+// image.EncodingRenderer.
+func renderEncoded(renderer image.Renderer, path string, pageNum, number int, enc image.Encoding) ([]byte, error) {
+	if stream, ok := renderer.(image.StreamRenderer); ok {
+		var buf bytes.Buffer
+		if err := stream.RenderTo(path, pageNum, &buf, enc); err != nil {
+			return nil, fmt.Errorf("failed to render page %d: %w", number, err)
+		}
+		return buf.Bytes(), nil
+	}
+
+	settings := renderer.Settings()
+
+	render := renderer.Render
+	switch {
+	case enc.Quality != settings.Quality:
+		encoder, ok := renderer.(image.EncodingRenderer)
+		if !ok {
+			return nil, fmt.Errorf("renderer cannot render at quality %d: byte budgets require an image.EncodingRenderer", enc.Quality)
+		}
+		render = func(inputPath string, pageNum int, outputPath string) error {
+			return encoder.RenderWith(inputPath, pageNum, outputPath, enc)
+		}
+	case enc.DPI != float64(settings.DPI):
+		fit, ok := renderer.(image.FitRenderer)
+		if !ok {
+			return nil, fmt.Errorf("renderer cannot render at %g DPI: fit limits and byte budgets require an image.FitRenderer", enc.DPI)
+		}
+		render = func(inputPath string, pageNum int, outputPath string) error {
+			return fit.RenderAt(inputPath, pageNum, outputPath, enc.DPI)
+		}
+	}
+
+	ext := renderer.FileExtension()
+
+	tmpFile, err := os.CreateTemp("", fmt.Sprintf("page-%d-*.%s", number, ext))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp file: %w", err)
+	}
+	tmpPath := tmpFile.Name()
+	tmpFile.Close()
+	defer os.Remove(tmpPath)
+
+	if err := render(path, pageNum, tmpPath); err != nil {
+		return nil, fmt.Errorf("failed to render page %d: %w", number, err)
+	}
+
+	data, err := os.ReadFile(tmpPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rendered image: %w", err)
+	}
+	return data, nil
+}
+
+// renderPDFData renders a generated single-page PDF, written to a temporary
+// file for the renderer, at the given DPI (see renderFileAt). number is the
+// page number reported in errors and temporary file names.
+func renderPDFData(renderer image.Renderer, pdf pagePDF, number int, dpi float64) (*BudgetImage, error) {
+	path, err := writeTempPDF(pdf.data, number)
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(path)
+
+	return renderFileAt(renderer, path, 1, number, dpi)
+}
+
+// writeTempPDF writes an in-memory PDF to a temporary file for a renderer
+// and returns its path. The caller removes the file.
+func writeTempPDF(data []byte, number int) (string, error) {
+	tmpFile, err := os.CreateTemp("", fmt.Sprintf("page-%d-*.pdf", number))
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp file: %w", err)
+	}
+	tmpPath := tmpFile.Name()
+
+	_, err = tmpFile.Write(data)
+	if closeErr := tmpFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		return "", fmt.Errorf("failed to write page PDF: %w", err)
+	}
+
+	return tmpPath, nil
+}
+
+// writeImage writes a page image to w for WriteImage methods.
+//
+// Without a cache or byte budget, an image.StreamRenderer renders straight
+// to w through stream. Otherwise the image is rendered (or read from the
+// cache) through render, whose result must be complete before it can be
+// cached or checked against the budget, and then written to w.
+func writeImage(w io.Writer, renderer image.Renderer, c cache.Cache, stream func(image.StreamRenderer) error, render func() (*RenderResult, error)) error {
+	if s, ok := renderer.(image.StreamRenderer); ok && c == nil && renderer.Settings().MaxBytes <= 0 {
+		return stream(s)
+	}
+
+	img, err := render()
+	if err != nil {
+		return err
+	}
+
+	if _, err := w.Write(img.Data); err != nil {
+		return fmt.Errorf("failed to write image: %w", err)
+	}
+	return nil
+}
+
+// streamFile renders page pageNum of the document at path at the given DPI
+// and the configured quality to w. number is the page number reported in
+// errors.
+func streamFile(w io.Writer, renderer image.StreamRenderer, path string, pageNum, number int, dpi float64) error {
+	enc := image.Encoding{DPI: dpi, Quality: renderer.Settings().Quality}
+	if err := renderer.RenderTo(path, pageNum, w, enc); err != nil {
+		return fmt.Errorf("failed to render page %d: %w", number, err)
+	}
+	return nil
+}
+
+// streamPDFData renders a generated single-page PDF, written to a temporary
+// file for the renderer, at the given DPI to w.
+func streamPDFData(w io.Writer, renderer image.StreamRenderer, pdf pagePDF, number int, dpi float64) error {
+	path, err := writeTempPDF(pdf.data, number)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(path)
+
+	return streamFile(w, renderer, path, 1, number, dpi)
+}
+
+// fitDPI returns the DPI at which renderer renders a page of the given size,
+// in PDF points: the DPI fitting the renderer's fit limits when configured,
+// otherwise its configured DPI. Returns an error when fit limits are
+// configured but the renderer does not implement image.FitRenderer.
+func fitDPI(renderer image.Renderer, width, height float64) (float64, error) {
+	settings := renderer.Settings()
+	if !image.HasFitLimits(settings) {
+		return float64(settings.DPI), nil
+	}
+
+	fit, ok := renderer.(image.FitRenderer)
+	if !ok {
+		return 0, fmt.Errorf("fit limits require a renderer implementing image.FitRenderer")
+	}
+	return fit.FitDPI(width, height), nil
+}
+
+// imageCacheKeyAt generates the cache key of a rendered page from the
+// document fingerprint, page number, rendering settings, and the DPI the page
+// renders at, which replaces the configured DPI (see PDFPage.buildCacheKey
+// for the key format). Whole DPIs are formatted without a fractional part, so
+// pages rendered at the configured DPI keep the same keys.
+func imageCacheKeyAt(fingerprint string, page int, renderer image.Renderer, dpi float64) string {
+	settings := renderer.Settings()
+
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("%s/%d.%s", fingerprint, page, settings.Format))
+
+	params := []string{
+		fmt.Sprintf("dpi=%s", strconv.FormatFloat(dpi, 'f', -1, 64)),
+		fmt.Sprintf("quality=%d", settings.Quality),
+	}
+
+	if settings.Lossless {
+		params = append(params, "lossless=true")
+	}
+
+	if settings.MaxBytes > 0 {
+		params = append(params, fmt.Sprintf("max_bytes=%d", settings.MaxBytes))
+	}
+
+	params = append(params, renderer.Parameters()...)
+
+	builder.WriteString(fmt.Sprintf("?%s", strings.Join(params, "&")))
+
+	return cache.GenerateKey(builder.String())
+}
+
+// imageFilename returns the suggested cache filename of a rendered page:
+// the document base name without extension, the page number, and the image
+// format (e.g., "document.1.png").
+func imageFilename(path string, page int, format string) string {
+	baseName := filepath.Base(path)
+	nameWithoutExt := strings.TrimSuffix(baseName, filepath.Ext(baseName))
+	return fmt.Sprintf("%s.%d.%s", nameWithoutExt, page, format)
+}
+
+// budgetFilename records enc in the filename of a budgeted cache entry,
+// before its extension (e.g., "document.1.jpg" rendered at quality 60 and
+// 150 DPI becomes "document.1.q60-dpi150.jpg").
+func budgetFilename(filename string, enc image.Encoding) string {
+	ext := filepath.Ext(filename)
+	return fmt.Sprintf("%s.q%d-dpi%s%s", strings.TrimSuffix(filename, ext), enc.Quality,
+		strconv.FormatFloat(enc.DPI, 'f', -1, 64), ext)
+}
+
+// parseBudgetFilename returns the encoding recorded by budgetFilename.
+// Returns false if filename does not record one.
+func parseBudgetFilename(filename string) (image.Encoding, bool) {
+	match := budgetFilenamePattern.FindStringSubmatch(filename)
+	if match == nil {
+		return image.Encoding{}, false
+	}
+
+	quality, err := strconv.Atoi(match[1])
+	if err != nil {
+		return image.Encoding{}, false
+	}
+	dpi, err := strconv.ParseFloat(match[2], 64)
+	if err != nil {
+		return image.Encoding{}, false
+	}
+	return image.Encoding{DPI: dpi, Quality: quality}, true
+}
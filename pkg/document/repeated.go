@@ -0,0 +1,273 @@
+package document
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	// repeatTolerance is the distance, in user space units, within which the
+	// top and bottom edges of lines on different pages are considered the same
+	// position.
+	repeatTolerance = 3.0
+
+	// minRepeatFraction is the fraction of pages on which a line must recur to
+	// be considered repeated. Half the pages admits headers that alternate
+	// between odd and even pages.
+	minRepeatFraction = 0.5
+)
+
+var digitRun = regexp.MustCompile(`[0-9]+`)
+
+// TextLine is a line segment of page text: spans sharing a baseline without a
+// column gap between them.
+type TextLine struct {
+	Text   string
+	Bounds Rect
+}
+
+// LinePage is implemented by pages that can report their text lines and page
+// region, as required by repeated content detection.
+type LinePage interface {
+	LayoutPage
+	TextLines() ([]TextLine, error)
+	Box() (Rect, error)
+}
+
+// TextLines returns the visible text of the page as line segments, top to
+// bottom and left to right. Lines are split where the gap between spans is
+// wide enough to separate columns.
+func (p *PDFPage) TextLines() ([]TextLine, error) {
+	content, err := p.interpret()
+	if err != nil {
+		return nil, err
+	}
+
+	var lines []TextLine
+	for _, line := range groupLines(visibleSpans(content.spans)) {
+		for _, c := range line.chunks {
+			bounds := c.spans[0].Bounds()
+			for _, s := range c.spans[1:] {
+				bounds = union(bounds, s.Bounds())
+			}
+			lines = append(lines, TextLine{Text: joinSpans(c.spans), Bounds: bounds})
+		}
+	}
+	return lines, nil
+}
+
+// Box returns the visible page region in PDF user space: the crop box when
+// present, otherwise the media box.
+func (p *PDFPage) Box() (Rect, error) {
+	p.doc.mu.Lock()
+	defer p.doc.mu.Unlock()
+
+	if p.doc.ctx == nil {
+		return Rect{}, fmt.Errorf("document is closed")
+	}
+
+	_, _, inherited, err := p.doc.ctx.PageDict(p.number, false)
+	if err != nil {
+		return Rect{}, fmt.Errorf("failed to read page %d: %w", p.number, err)
+	}
+	return pageBox(inherited), nil
+}
+
+// Occurrence is a single appearance of a repeated line.
+type Occurrence struct {
+	Page   int
+	Text   string
+	Bounds Rect
+}
+
+// RepeatedLine is a text line that recurs at the same position across pages,
+// such as a letterhead, running title, or page number footer.
+type RepeatedLine struct {
+	// Pattern is the line text with every run of digits replaced by "#", so
+	// that "Page 3 of 10" and "Page 4 of 10" share the pattern "Page # of #".
+	Pattern string
+
+	// Kind is RegionHeader for lines in the top margin band of the page and
+	// RegionFooter for lines in the bottom margin band.
+	Kind RegionKind
+
+	// Occurrences lists each appearance in page order.
+	Occurrences []Occurrence
+}
+
+// Pages returns the page numbers on which the line appears.
+func (l *RepeatedLine) Pages() []int {
+	pages := make([]int, 0, len(l.Occurrences))
+	for _, o := range l.Occurrences {
+		if len(pages) == 0 || pages[len(pages)-1] != o.Page {
+			pages = append(pages, o.Page)
+		}
+	}
+	return pages
+}
+
+// RepeatedContent is the result of repeated line detection over a document.
+type RepeatedContent struct {
+	// Lines lists the repeated lines, top of the page first.
+	Lines []RepeatedLine
+
+	boxes map[int]Rect
+}
+
+// FindRepeatedContent finds text lines that recur in the same position on at
+// least half of the document's pages (and on at least two pages).
+//
+// Only lines lying entirely within the top or bottom margin band of the page
+// box (8% of its height, as for layout analysis) are considered, so body
+// lines that recur at the same position, such as numbered items or repeated
+// section labels, are not mistaken for headers or footers.
+//
+// Lines match when their text is equal after replacing digit runs with "#" and
+// their top and bottom edges are within a few points with overlapping
+// horizontal extents, so centered page numbers of varying width still match.
+// Single-page documents have no repeated content.
+//
+// Parameters:
+//   - doc: Document whose pages implement LinePage
+//
+// Returns:
+//   - *RepeatedContent: Repeated lines with per-page bounding boxes
+//   - error: Page extraction or text analysis failure, or unsupported pages
+func FindRepeatedContent(doc Document) (*RepeatedContent, error) {
+	pages, err := doc.ExtractAllPages()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &RepeatedContent{boxes: make(map[int]Rect, len(pages))}
+	groups := make(map[string][]*RepeatedLine)
+	var order []*RepeatedLine
+
+	for _, page := range pages {
+		lp, ok := page.(LinePage)
+		if !ok {
+			return nil, fmt.Errorf("page %d does not support text line analysis", page.Number())
+		}
+
+		box, err := lp.Box()
+		if err != nil {
+			return nil, fmt.Errorf("failed to analyze page %d: %w", page.Number(), err)
+		}
+		result.boxes[page.Number()] = box
+
+		lines, err := lp.TextLines()
+		if err != nil {
+			return nil, fmt.Errorf("failed to analyze page %d: %w", page.Number(), err)
+		}
+
+		band := marginBand * box.Height()
+		for _, line := range lines {
+			var kind RegionKind
+			switch {
+			case line.Bounds.Y0 >= box.Y1-band:
+				kind = RegionHeader
+			case line.Bounds.Y1 <= box.Y0+band:
+				kind = RegionFooter
+			default:
+				continue
+			}
+
+			pattern := strings.Join(strings.Fields(digitRun.ReplaceAllString(line.Text, "#")), " ")
+			occurrence := Occurrence{Page: page.Number(), Text: line.Text, Bounds: line.Bounds}
+
+			var match *RepeatedLine
+			for _, candidate := range groups[pattern] {
+				if candidate.Kind == kind && samePosition(candidate.Occurrences[0].Bounds, line.Bounds) {
+					match = candidate
+					break
+				}
+			}
+			if match == nil {
+				match = &RepeatedLine{Pattern: pattern, Kind: kind}
+				groups[pattern] = append(groups[pattern], match)
+				order = append(order, match)
+			}
+			match.Occurrences = append(match.Occurrences, occurrence)
+		}
+	}
+
+	required := max(2, int(math.Ceil(minRepeatFraction*float64(len(pages)))))
+
+	for _, line := range order {
+		if len(line.Pages()) < required {
+			continue
+		}
+		result.Lines = append(result.Lines, *line)
+	}
+
+	sort.SliceStable(result.Lines, func(i, j int) bool {
+		return result.Lines[i].Occurrences[0].Bounds.Y1 > result.Lines[j].Occurrences[0].Bounds.Y1
+	})
+
+	return result, nil
+}
+
+func samePosition(a, b Rect) bool {
+	return math.Abs(a.Y0-b.Y0) <= repeatTolerance &&
+		math.Abs(a.Y1-b.Y1) <= repeatTolerance &&
+		math.Min(a.X1, b.X1) > math.Max(a.X0, b.X0)
+}
+
+// Regions returns the bounding boxes of repeated lines on the given page, in
+// PDF user space. Pass them to LayoutPage.ExtractText to omit the lines.
+func (r *RepeatedContent) Regions(page int) []Rect {
+	var regions []Rect
+	for _, line := range r.Lines {
+		for _, o := range line.Occurrences {
+			if o.Page == page {
+				regions = append(regions, o.Bounds)
+			}
+		}
+	}
+	return regions
+}
+
+// ContentBox returns the region of the page between its repeated headers and
+// footers, in PDF user space. The box can be used to crop rendered images:
+// at a given DPI, user space coordinates scale by DPI/72 and image rows run
+// down from the top edge of the page box.
+//
+// Pages without repeated lines return the full page box; unknown pages return
+// an empty Rect.
+func (r *RepeatedContent) ContentBox(page int) Rect {
+	box, ok := r.boxes[page]
+	if !ok {
+		return Rect{}
+	}
+
+	for _, line := range r.Lines {
+		for _, o := range line.Occurrences {
+			if o.Page != page {
+				continue
+			}
+			if line.Kind == RegionHeader {
+				box.Y1 = math.Min(box.Y1, o.Bounds.Y0)
+			} else {
+				box.Y0 = math.Max(box.Y0, o.Bounds.Y1)
+			}
+		}
+	}
+
+	return box
+}
+
+// Text returns the text of page with repeated lines removed.
+//
+// Parameters:
+//   - page: Page to extract text from
+//   - mode: Text ordering mode
+//
+// Returns:
+//   - string: Page text without repeated headers and footers
+//   - error: Unknown mode or interpretation failure
+func (r *RepeatedContent) Text(page LayoutPage, mode TextMode) (string, error) {
+	return page.ExtractText(mode, r.Regions(page.Number())...)
+}
@@ -0,0 +1,234 @@
+package document
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// slideInset is the text inset of slide shapes, in points (PowerPoint's
+	// default of 0.1 inch).
+	slideInset = 7.2
+
+	// slideLevelIndent is the indentation per list level, in points.
+	slideLevelIndent = 27.0
+
+	// slideCharWidth approximates the average Helvetica glyph width as a
+	// fraction of the font size, used to wrap text.
+	slideCharWidth = 0.5
+
+	// slideMinScale bounds how far text is shrunk to fit its shape.
+	slideMinScale = 0.5
+)
+
+// slideLine is a wrapped line of slide text.
+type slideLine struct {
+	text   string
+	marker string
+	indent float64
+	size   float64
+}
+
+// writeSlidePDF lays out a slide as a single-page PDF of width by height
+// points. Text is set in Helvetica (Helvetica-Bold for titles), wrapped to
+// its shape and shrunk when it overflows; tables are drawn as ruled grids and
+// pictures as gray placeholders.
+func writeSlidePDF(width, height float64, shapes []pptxShape) pagePDF {
+	var content strings.Builder
+
+	for _, s := range shapes {
+		b := s.bounds
+		switch {
+		case s.picture:
+			fmt.Fprintf(&content, "0.92 g 0.6 G 0.5 w %g %g %g %g re B\n", b.X0, b.Y0, b.Width(), b.Height())
+		case s.table != nil:
+			writeSlideTable(&content, s.table, b)
+		default:
+			writeSlideText(&content, s, b)
+		}
+	}
+
+	stream := content.String()
+
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %g %g] "+
+			"/Resources << /Font << /F1 4 0 R /F2 5 0 R >> >> /Contents 6 0 R >>", width, height),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(stream), stream),
+	}
+
+	return pagePDF{data: assemblePDF(objects), width: width, height: height}
+}
+
+func writeSlideText(content *strings.Builder, s pptxShape, b Rect) {
+	available := b.Height() - 2*slideInset
+
+	scale := 1.0
+	lines := wrapSlideText(s, b.Width()-2*slideInset, scale)
+	if needed := linesHeight(lines); needed > available && needed > 0 {
+		scale = max(slideMinScale, available/needed)
+		lines = wrapSlideText(s, b.Width()-2*slideInset, scale)
+	}
+
+	font := "/F1"
+	if s.isTitle() {
+		font = "/F2"
+	}
+
+	top := b.Y1 - slideInset
+	if s.isTitle() {
+		top -= max(0, (available-linesHeight(lines))/2)
+	}
+
+	content.WriteString("0 g\n")
+	for _, line := range lines {
+		top -= line.size * 1.2
+		x := b.X0 + slideInset + line.indent
+		if line.marker != "" {
+			fmt.Fprintf(content, "BT %s %g Tf %g %g Td (%s) Tj ET\n",
+				font, line.size, x-1.5*line.size, top+0.25*line.size, pdfString(line.marker))
+		}
+		if line.text != "" {
+			fmt.Fprintf(content, "BT %s %g Tf %g %g Td (%s) Tj ET\n",
+				font, line.size, x, top+0.25*line.size, pdfString(line.text))
+		}
+	}
+}
+
+// wrapSlideText breaks the paragraphs of a shape into lines that fit width,
+// with font sizes multiplied by scale.
+func wrapSlideText(s pptxShape, width, scale float64) []slideLine {
+	var lines []slideLine
+
+	for _, para := range s.paragraphs {
+		size := para.size
+		if size == 0 {
+			size = defaultSlideFontSize(s.placeholder, para.level)
+		}
+		size *= scale
+
+		indent := float64(para.level) * slideLevelIndent
+		if para.marker != "" {
+			indent += 1.5 * size
+		}
+		chars := max(1, int((width-indent)/(size*slideCharWidth)))
+
+		marker := para.marker
+		for _, text := range strings.Split(para.text, "\n") {
+			for _, wrapped := range wrapWords(text, chars) {
+				lines = append(lines, slideLine{text: wrapped, marker: marker, indent: indent, size: size})
+				marker = ""
+			}
+		}
+	}
+	return lines
+}
+
+// defaultSlideFontSize returns the font size of text whose size is inherited
+// from the slide master, using PowerPoint's default theme sizes.
+func defaultSlideFontSize(placeholder string, level int) float64 {
+	switch placeholder {
+	case "ctrTitle":
+		return 40
+	case "title":
+		return 36
+	case "subTitle":
+		return 24
+	case "body", "obj":
+		return math.Max(12, 24-4*float64(level))
+	default:
+		return 18
+	}
+}
+
+func linesHeight(lines []slideLine) float64 {
+	height := 0.0
+	for _, line := range lines {
+		height += line.size * 1.2
+	}
+	return height
+}
+
+// wrapWords breaks text into lines of at most chars characters at spaces,
+// splitting words longer than a line.
+func wrapWords(text string, chars int) []string {
+	words := strings.Fields(text)
+	if len(words) == 0 {
+		return []string{""}
+	}
+
+	var lines []string
+	current := ""
+	for _, word := range words {
+		for utf8.RuneCountInString(word) > chars {
+			if current != "" {
+				lines = append(lines, current)
+				current = ""
+			}
+			runes := []rune(word)
+			lines = append(lines, string(runes[:chars]))
+			word = string(runes[chars:])
+		}
+
+		switch {
+		case current == "":
+			current = word
+		case utf8.RuneCountInString(current)+1+utf8.RuneCountInString(word) <= chars:
+			current += " " + word
+		default:
+			lines = append(lines, current)
+			current = word
+		}
+	}
+	if current != "" {
+		lines = append(lines, current)
+	}
+	return lines
+}
+
+func writeSlideTable(content *strings.Builder, rows [][]string, b Rect) {
+	if len(rows) == 0 || len(rows[0]) == 0 {
+		return
+	}
+
+	rowHeight := b.Height() / float64(len(rows))
+	colWidth := b.Width() / float64(len(rows[0]))
+	size := min(12, rowHeight*0.6)
+	chars := max(1, int((colWidth-2*tableCellPadding)/(size*slideCharWidth)))
+
+	content.WriteString("0.5 w 0.6 G\n")
+	for i := 0; i <= len(rows); i++ {
+		y := b.Y1 - float64(i)*rowHeight
+		fmt.Fprintf(content, "%g %g m %g %g l S\n", b.X0, y, b.X1, y)
+	}
+	for j := 0; j <= len(rows[0]); j++ {
+		x := b.X0 + float64(j)*colWidth
+		fmt.Fprintf(content, "%g %g m %g %g l S\n", x, b.Y0, x, b.Y1)
+	}
+
+	content.WriteString("0 g\n")
+	for i, row := range rows {
+		font := "/F1"
+		if i == 0 {
+			font = "/F2"
+		}
+		baseline := b.Y1 - float64(i)*rowHeight - rowHeight/2 - 0.35*size
+
+		for j, cell := range row {
+			text := strings.Join(strings.Fields(cell), " ")
+			if text == "" {
+				continue
+			}
+			if runes := []rune(text); len(runes) > chars {
+				text = string(runes[:chars])
+			}
+			fmt.Fprintf(content, "BT %s %g Tf %g %g Td (%s) Tj ET\n",
+				font, size, b.X0+float64(j)*colWidth+tableCellPadding, baseline, pdfString(text))
+		}
+	}
+}
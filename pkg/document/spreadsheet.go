@@ -0,0 +1,460 @@
+package document
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/JaimeStill/document-context/pkg/cache"
+	"github.com/JaimeStill/document-context/pkg/config"
+	"github.com/JaimeStill/document-context/pkg/image"
+)
+
+// maxSheetCells bounds the number of cells of a single sheet held in memory.
+const maxSheetCells = 10_000_000
+
+// SpreadsheetDocument is a spreadsheet (XLSX, CSV, or TSV) whose sheets are
+// divided into pages.
+//
+// Each sheet produces at least one page. Sheets larger than the configured
+// limits are split into row chunks of at most MaxRows data rows and column
+// chunks of at most MaxColumns columns; pages of a sheet are ordered by row
+// chunk, then column chunk. When HeaderRow is enabled, the first row of the
+// sheet is repeated at the top of every page.
+type SpreadsheetDocument struct {
+	path        string
+	fingerprint string
+	renderKey   string
+	sheets      []sheet
+	pages       []sheetRange
+	header      bool
+	mu          sync.Mutex
+}
+
+// sheet is a named grid of cell values. Rows may be shorter than the widest
+// row; missing cells are empty.
+type sheet struct {
+	name string
+	rows [][]string
+	cols int
+}
+
+// sheetRange identifies the cells shown on a page: rows [row0, row1) and
+// columns [col0, col1) of a sheet.
+type sheetRange struct {
+	sheet      int
+	row0, row1 int
+	col0, col1 int
+}
+
+// OpenXLSX opens an Excel workbook using the default SpreadsheetConfig.
+func OpenXLSX(path string) (*SpreadsheetDocument, error) {
+	return OpenXLSXWithConfig(path, config.DefaultSpreadsheetConfig())
+}
+
+// OpenXLSXWithConfig opens an Excel (.xlsx) workbook.
+//
+// Every worksheet becomes one or more pages. Cell values are read as
+// displayed text: shared and inline strings, numbers as stored, booleans as
+// TRUE/FALSE, error values (e.g., #DIV/0!), and date-formatted numbers as
+// ISO 8601 dates and times. Formula cells use their cached values.
+//
+// Returns an error if the file is not a readable workbook or a sheet exceeds
+// the in-memory cell limit.
+func OpenXLSXWithConfig(path string, cfg config.SpreadsheetConfig) (*SpreadsheetDocument, error) {
+	sheets, err := readXLSX(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open XLSX: %w", err)
+	}
+	return newSpreadsheet(path, sheets, cfg)
+}
+
+// OpenCSV opens a comma-separated values file using the default
+// SpreadsheetConfig.
+func OpenCSV(path string) (*SpreadsheetDocument, error) {
+	return OpenCSVWithConfig(path, config.DefaultSpreadsheetConfig())
+}
+
+// OpenCSVWithConfig opens a comma-separated values file as a single sheet
+// named after the file. Records may have varying numbers of fields.
+func OpenCSVWithConfig(path string, cfg config.SpreadsheetConfig) (*SpreadsheetDocument, error) {
+	return openDelimited(path, ',', cfg)
+}
+
+// OpenTSV opens a tab-separated values file using the default
+// SpreadsheetConfig.
+func OpenTSV(path string) (*SpreadsheetDocument, error) {
+	return OpenTSVWithConfig(path, config.DefaultSpreadsheetConfig())
+}
+
+// OpenTSVWithConfig opens a tab-separated values file as a single sheet
+// named after the file.
+func OpenTSVWithConfig(path string, cfg config.SpreadsheetConfig) (*SpreadsheetDocument, error) {
+	return openDelimited(path, '\t', cfg)
+}
+
+func openDelimited(path string, comma rune, cfg config.SpreadsheetConfig) (*SpreadsheetDocument, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read spreadsheet: %w", err)
+	}
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = comma
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse spreadsheet: %w", err)
+	}
+
+	base := filepath.Base(path)
+	s, err := newSheet(strings.TrimSuffix(base, filepath.Ext(base)), rows)
+	if err != nil {
+		return nil, err
+	}
+
+	return newSpreadsheet(path, []sheet{s}, cfg)
+}
+
+// newSheet trims trailing empty rows and columns and enforces the cell limit.
+func newSheet(name string, rows [][]string) (sheet, error) {
+	s := sheet{name: name}
+
+	last := -1
+	for i, row := range rows {
+		for j := len(row) - 1; j >= 0; j-- {
+			if strings.TrimSpace(row[j]) != "" {
+				s.cols = max(s.cols, j+1)
+				last = i
+				break
+			}
+		}
+	}
+	s.rows = rows[:last+1]
+
+	if len(s.rows)*s.cols > maxSheetCells {
+		return sheet{}, fmt.Errorf("sheet %q exceeds %d cells", name, maxSheetCells)
+	}
+	return s, nil
+}
+
+func newSpreadsheet(path string, sheets []sheet, cfg config.SpreadsheetConfig) (*SpreadsheetDocument, error) {
+	cfg.Finalize()
+
+	if len(sheets) == 0 {
+		return nil, fmt.Errorf("spreadsheet has no sheets")
+	}
+
+	fingerprint, err := contentFingerprint(path)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &SpreadsheetDocument{
+		path:        path,
+		fingerprint: fingerprint,
+		sheets:      sheets,
+		header:      *cfg.HeaderRow,
+	}
+
+	// Pages depend on the pagination settings as well as the content, so
+	// rendered pages are keyed by both.
+	doc.renderKey = fmt.Sprintf("%s/sheet?max_rows=%d&max_columns=%d&header_row=%t",
+		fingerprint, cfg.MaxRows, cfg.MaxColumns, doc.header)
+
+	for i, s := range sheets {
+		first := 0
+		if doc.header && len(s.rows) > 0 {
+			first = 1
+		}
+
+		for row0 := first; ; row0 += cfg.MaxRows {
+			row1 := min(row0+cfg.MaxRows, len(s.rows))
+			for col0 := 0; ; col0 += cfg.MaxColumns {
+				col1 := min(col0+cfg.MaxColumns, s.cols)
+				doc.pages = append(doc.pages, sheetRange{sheet: i, row0: row0, row1: row1, col0: col0, col1: col1})
+				if col1 >= s.cols {
+					break
+				}
+			}
+			if row1 >= len(s.rows) {
+				break
+			}
+		}
+	}
+
+	return doc, nil
+}
+
+func (d *SpreadsheetDocument) PageCount() int {
+	return len(d.pages)
+}
+
+// Fingerprint returns the SHA-256 of the spreadsheet file computed at open
+// time.
+func (d *SpreadsheetDocument) Fingerprint() string {
+	return d.fingerprint
+}
+
+// SheetNames returns the names of the sheets in workbook order.
+func (d *SpreadsheetDocument) SheetNames() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	names := make([]string, len(d.sheets))
+	for i, s := range d.sheets {
+		names[i] = s.name
+	}
+	return names
+}
+
+func (d *SpreadsheetDocument) ExtractPage(pageNum int) (Page, error) {
+	if pageNum < 1 || pageNum > len(d.pages) {
+		return nil, fmt.Errorf("page %d out of range [1-%d]", pageNum, len(d.pages))
+	}
+	return &SheetPage{doc: d, number: pageNum, area: d.pages[pageNum-1]}, nil
+}
+
+func (d *SpreadsheetDocument) ExtractAllPages() ([]Page, error) {
+	pages := make([]Page, 0, len(d.pages))
+	for i := 1; i <= len(d.pages); i++ {
+		page, err := d.ExtractPage(i)
+		if err != nil {
+			return nil, fmt.Errorf("failed to extract page %d: %w", i, err)
+		}
+		pages = append(pages, page)
+	}
+	return pages, nil
+}
+
+// Close releases the spreadsheet content.
+func (d *SpreadsheetDocument) Close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.sheets = nil
+	return nil
+}
+
+// SheetPage is a page of a SpreadsheetDocument: a block of rows and columns
+// from one sheet.
+type SheetPage struct {
+	doc    *SpreadsheetDocument
+	number int
+	area   sheetRange
+}
+
+func (p *SheetPage) Number() int {
+	return p.number
+}
+
+// Sheet returns the name of the sheet the page belongs to.
+func (p *SheetPage) Sheet() string {
+	p.doc.mu.Lock()
+	defer p.doc.mu.Unlock()
+
+	if p.doc.sheets == nil {
+		return ""
+	}
+	return p.doc.sheets[p.area.sheet].name
+}
+
+// Range returns the data cells of the page in A1 notation (e.g., "A52:T101").
+// The header row, when enabled, is not included. Pages without data rows
+// return "".
+func (p *SheetPage) Range() string {
+	a := p.area
+	if a.row1 <= a.row0 || a.col1 <= a.col0 {
+		return ""
+	}
+	return fmt.Sprintf("%s%d:%s%d", columnName(a.col0), a.row0+1, columnName(a.col1-1), a.row1)
+}
+
+// columnName returns the spreadsheet column letters of a zero-based index.
+func columnName(col int) string {
+	name := ""
+	for col++; col > 0; col = (col - 1) / 26 {
+		name = string(rune('A'+(col-1)%26)) + name
+	}
+	return name
+}
+
+// Table returns the cells of the page, with the header row first when the
+// document repeats headers. Every row has the same number of cells.
+func (p *SheetPage) Table() (*Table, error) {
+	rows, err := p.rows()
+	if err != nil {
+		return nil, err
+	}
+
+	table := &Table{Cells: make([][]Cell, len(rows))}
+	for i, row := range rows {
+		table.Cells[i] = make([]Cell, len(row))
+		for j, text := range row {
+			table.Cells[i][j] = Cell{Text: text}
+		}
+	}
+	return table, nil
+}
+
+func (p *SheetPage) rows() ([][]string, error) {
+	p.doc.mu.Lock()
+	defer p.doc.mu.Unlock()
+
+	if p.doc.sheets == nil {
+		return nil, fmt.Errorf("document is closed")
+	}
+
+	s := p.doc.sheets[p.area.sheet]
+	a := p.area
+
+	slice := func(row []string) []string {
+		cells := make([]string, a.col1-a.col0)
+		for j := range cells {
+			if a.col0+j < len(row) {
+				cells[j] = row[a.col0+j]
+			}
+		}
+		return cells
+	}
+
+	var rows [][]string
+	if p.doc.header && len(s.rows) > 0 && a.col1 > a.col0 {
+		rows = append(rows, slice(s.rows[0]))
+	}
+	for i := a.row0; i < a.row1; i++ {
+		rows = append(rows, slice(s.rows[i]))
+	}
+	return rows, nil
+}
+
+// Markdown returns the page as a GitHub-flavored Markdown table. The first
+// row is the table header.
+func (p *SheetPage) Markdown() (string, error) {
+	table, err := p.Table()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(table.Markdown(), "\n"), nil
+}
+
+// CSV returns the page as RFC 4180 CSV.
+func (p *SheetPage) CSV() (string, error) {
+	table, err := p.Table()
+	if err != nil {
+		return "", err
+	}
+	return table.CSV()
+}
+
+// Text returns the page as tab-separated rows.
+func (p *SheetPage) Text() (string, error) {
+	rows, err := p.rows()
+	if err != nil {
+		return "", err
+	}
+
+	lines := make([]string, len(rows))
+	for i, row := range rows {
+		for j, cell := range row {
+			row[j] = strings.Join(strings.Fields(cell), " ")
+		}
+		lines[i] = strings.TrimRight(strings.Join(row, "\t"), "\t")
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// ToImage renders the page as a ruled table image.
+//
+// The table is laid out as a single-page PDF titled with the sheet name and
+// range, then rendered through renderer, so images follow the renderer's
+// format, DPI, and filters. Caching follows PDFPage.ToImage, with keys derived
+// from the spreadsheet fingerprint, its pagination settings (MaxRows,
+// MaxColumns, and HeaderRow), and the page number.
+func (p *SheetPage) ToImage(renderer image.Renderer, c cache.Cache) ([]byte, error) {
+	return imageData(p.Render(renderer, c))
+}
+
+// ToBudgetImage renders the page like ToImage and returns the image with the
+// encoding it was rendered with (see Render).
+func (p *SheetPage) ToBudgetImage(renderer image.Renderer, c cache.Cache) (*BudgetImage, error) {
+	return budgetImage(p.Render(renderer, c))
+}
+
+// Render renders the page like ToImage and returns the image with its
+// metadata (see PDFPage.Render).
+func (p *SheetPage) Render(renderer image.Renderer, c cache.Cache) (*RenderResult, error) {
+	dpi, err := p.renderDPI(renderer)
+	if err != nil {
+		return nil, err
+	}
+
+	key := imageCacheKeyAt(p.doc.renderKey, p.number, renderer, dpi)
+	filename := imageFilename(p.doc.path, p.number, renderer.Settings().Format)
+
+	return renderCached(c, key, filename, renderer, dpi, func() (*BudgetImage, error) {
+		pdf, err := p.pdf()
+		if err != nil {
+			return nil, err
+		}
+		return renderPDFData(renderer, pdf, p.number, dpi)
+	})
+}
+
+// WriteImage renders the page like ToImage and writes the image to w,
+// streaming it from the renderer when possible (see StreamPage).
+func (p *SheetPage) WriteImage(w io.Writer, renderer image.Renderer, c cache.Cache) error {
+	return writeImage(w, renderer, c, func(s image.StreamRenderer) error {
+		dpi, err := p.renderDPI(renderer)
+		if err != nil {
+			return err
+		}
+		pdf, err := p.pdf()
+		if err != nil {
+			return err
+		}
+		return streamPDFData(w, s, pdf, p.number, dpi)
+	}, func() (*RenderResult, error) {
+		return p.Render(renderer, c)
+	})
+}
+
+// pdf lays out the page as the single-page table PDF rendered by ToImage,
+// titled with the sheet name and range.
+func (p *SheetPage) pdf() (pagePDF, error) {
+	rows, err := p.rows()
+	if err != nil {
+		return pagePDF{}, err
+	}
+
+	title := p.Sheet()
+	if r := p.Range(); r != "" {
+		title += " (" + r + ")"
+	}
+
+	return writeTablePDF(rows, p.doc.header, title), nil
+}
+
+// renderDPI returns the DPI at which renderer renders the page: fitted to the
+// generated table page, which grows with the rows and columns, when the
+// renderer has fit limits (see config.ImageConfig), otherwise the configured
+// DPI.
+func (p *SheetPage) renderDPI(renderer image.Renderer) (float64, error) {
+	if !image.HasFitLimits(renderer.Settings()) {
+		return float64(renderer.Settings().DPI), nil
+	}
+
+	pdf, err := p.pdf()
+	if err != nil {
+		return 0, err
+	}
+	return fitDPI(renderer, pdf.width, pdf.height)
+}
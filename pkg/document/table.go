@@ -0,0 +1,558 @@
+package document
+
+import (
+	"bytes"
+	"encoding/csv"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// ruleSnap is the distance, in user space units, within which ruling line
+	// coordinates are considered equal and lines are considered to touch.
+	ruleSnap = 2.0
+
+	// columnGap is the horizontal gap, as a multiple of the font size, that
+	// separates columns of whitespace-aligned tables. Word spaces are well
+	// below this; column gutters are well above it.
+	columnGap = 1.2
+
+	// minStreamRows is the minimum number of consecutive multi-column lines
+	// that form a table without ruling lines.
+	minStreamRows = 3
+
+	// maxRowSpacing is the maximum baseline distance between consecutive rows
+	// of a whitespace-aligned table, as a multiple of the font size.
+	maxRowSpacing = 2.5
+
+	// maxMeanCellLength is the mean cell length, in characters, above which
+	// whitespace-aligned text is treated as a multi-column page layout rather
+	// than a table.
+	maxMeanCellLength = 40
+)
+
+// Cell is a single table cell.
+type Cell struct {
+	// Text is the cell content. Multi-line cells contain newlines.
+	Text string
+
+	// Bounds is the cell region in PDF user space.
+	Bounds Rect
+}
+
+// Table is a grid of cells detected on a page.
+type Table struct {
+	// Bounds is the table region in PDF user space.
+	Bounds Rect
+
+	// Cells holds the table content in row-major order, top to bottom and
+	// left to right. Every row has the same number of cells.
+	Cells [][]Cell
+
+	// Ruled reports whether the table was detected from ruling lines. Tables
+	// detected from whitespace alignment alone are less reliable.
+	Ruled bool
+}
+
+// TablePage is implemented by pages that can detect tables in their content.
+type TablePage interface {
+	Page
+	Tables() ([]Table, error)
+}
+
+// Rows returns the cell text of the table, row by row.
+func (t *Table) Rows() [][]string {
+	rows := make([][]string, len(t.Cells))
+	for i, row := range t.Cells {
+		rows[i] = make([]string, len(row))
+		for j, cell := range row {
+			rows[i][j] = cell.Text
+		}
+	}
+	return rows
+}
+
+// Markdown renders the table as a GitHub-flavored Markdown table.
+//
+// The first row is used as the header. Pipe characters are escaped and line
+// breaks within cells are rendered as <br>.
+func (t *Table) Markdown() string {
+	if len(t.Cells) == 0 {
+		return ""
+	}
+
+	var builder strings.Builder
+	writeRow := func(cells []string) {
+		builder.WriteString("|")
+		for _, cell := range cells {
+			cell = strings.ReplaceAll(cell, "|", `\|`)
+			cell = strings.ReplaceAll(cell, "\n", "<br>")
+			builder.WriteString(" " + cell + " |")
+		}
+		builder.WriteString("\n")
+	}
+
+	rows := t.Rows()
+	writeRow(rows[0])
+
+	builder.WriteString("|")
+	for range rows[0] {
+		builder.WriteString(" --- |")
+	}
+	builder.WriteString("\n")
+
+	for _, row := range rows[1:] {
+		writeRow(row)
+	}
+
+	return builder.String()
+}
+
+// WriteCSV writes the table to w as RFC 4180 CSV.
+func (t *Table) WriteCSV(w io.Writer) error {
+	return csv.NewWriter(w).WriteAll(t.Rows())
+}
+
+// CSV returns the table as RFC 4180 CSV.
+func (t *Table) CSV() (string, error) {
+	var buf bytes.Buffer
+	if err := t.WriteCSV(&buf); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// Tables detects tables on the page and returns them in reading order.
+//
+// Two strategies are combined:
+//   - Ruled: horizontal and vertical ruling lines drawn in the content stream
+//     that intersect to form a grid. Text is assigned to the grid cell
+//     containing its center.
+//   - Whitespace: runs of at least three consecutive lines whose text splits
+//     into aligned columns separated by wide gaps. Applied to text outside
+//     ruled tables, this covers tables drawn with only horizontal rules or no
+//     rules at all. Runs of long cells are treated as multi-column page
+//     layout rather than tables.
+//
+// Merged cells are not reconstructed: their text appears in the grid cell
+// containing it and neighboring cells are empty.
+func (p *PDFPage) Tables() ([]Table, error) {
+	content, err := p.interpret()
+	if err != nil {
+		return nil, err
+	}
+	return detectTables(content.spans, content.rules), nil
+}
+
+func detectTables(spans []TextSpan, rules []Rect) []Table {
+	visible := visibleSpans(spans)
+
+	tables := ruledTables(visible, rules)
+
+	bounds := make([]Rect, len(tables))
+	for i, t := range tables {
+		bounds[i] = t.Bounds
+	}
+
+	tables = append(tables, streamTables(excludeSpans(visible, bounds))...)
+
+	sort.SliceStable(tables, func(i, j int) bool {
+		if tables[i].Bounds.Y1 != tables[j].Bounds.Y1 {
+			return tables[i].Bounds.Y1 > tables[j].Bounds.Y1
+		}
+		return tables[i].Bounds.X0 < tables[j].Bounds.X0
+	})
+
+	return tables
+}
+
+type point struct {
+	x, y float64
+}
+
+func spanCenter(s TextSpan) point {
+	return point{x: s.X + s.Width/2, y: s.Y + 0.3*s.FontSize}
+}
+
+func contains(r Rect, p point) bool {
+	return p.x >= r.X0 && p.x <= r.X1 && p.y >= r.Y0 && p.y <= r.Y1
+}
+
+// ruleLine is a horizontal (at pos, spanning lo-hi along X) or vertical (at
+// pos, spanning lo-hi along Y) ruling line.
+type ruleLine struct {
+	pos, lo, hi float64
+}
+
+func ruledTables(spans []TextSpan, rules []Rect) []Table {
+	var hs, vs []ruleLine
+	for _, r := range rules {
+		switch {
+		case r.Height() <= ruleThickness && r.Width() > r.Height():
+			hs = append(hs, ruleLine{pos: (r.Y0 + r.Y1) / 2, lo: r.X0, hi: r.X1})
+		case r.Width() <= ruleThickness && r.Height() > r.Width():
+			vs = append(vs, ruleLine{pos: (r.X0 + r.X1) / 2, lo: r.Y0, hi: r.Y1})
+		}
+	}
+	hs = mergeRuleLines(hs)
+	vs = mergeRuleLines(vs)
+
+	parent := make([]int, len(hs)+len(vs))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	for i, h := range hs {
+		for j, v := range vs {
+			if v.pos >= h.lo-ruleSnap && v.pos <= h.hi+ruleSnap &&
+				h.pos >= v.lo-ruleSnap && h.pos <= v.hi+ruleSnap {
+				parent[find(i)] = find(len(hs) + j)
+			}
+		}
+	}
+
+	groups := make(map[int][]int)
+	var order []int
+	for i := range parent {
+		root := find(i)
+		if _, ok := groups[root]; !ok {
+			order = append(order, root)
+		}
+		groups[root] = append(groups[root], i)
+	}
+
+	var tables []Table
+	for _, root := range order {
+		var ys, xs []float64
+		for _, i := range groups[root] {
+			if i < len(hs) {
+				ys = append(ys, hs[i].pos)
+			} else {
+				xs = append(xs, vs[i-len(hs)].pos)
+			}
+		}
+
+		ys = clusterValues(ys)
+		xs = clusterValues(xs)
+		if len(ys) < 2 || len(xs) < 2 || (len(ys) < 3 && len(xs) < 3) {
+			continue
+		}
+
+		// Rows run top to bottom, so boundaries are ordered by descending Y.
+		sort.Sort(sort.Reverse(sort.Float64Slice(ys)))
+
+		if table, ok := buildRuledTable(spans, xs, ys); ok {
+			tables = append(tables, table)
+		}
+	}
+
+	return tables
+}
+
+func buildRuledTable(spans []TextSpan, xs, ys []float64) (Table, bool) {
+	rows, cols := len(ys)-1, len(xs)-1
+
+	assigned := make([][][]TextSpan, rows)
+	for i := range assigned {
+		assigned[i] = make([][]TextSpan, cols)
+	}
+
+	bounds := Rect{X0: xs[0], Y0: ys[rows], X1: xs[cols], Y1: ys[0]}
+	for _, s := range spans {
+		c := spanCenter(s)
+		if !contains(bounds, c) {
+			continue
+		}
+		row := sort.Search(rows, func(i int) bool { return ys[i+1] <= c.y })
+		col := sort.Search(cols, func(i int) bool { return xs[i+1] >= c.x })
+		if row < rows && col < cols {
+			assigned[row][col] = append(assigned[row][col], s)
+		}
+	}
+
+	cells := make([][]Cell, rows)
+	for i := range cells {
+		cells[i] = make([]Cell, cols)
+		for j := range cells[i] {
+			cells[i][j] = Cell{
+				Text:   joinSpans(readingOrder(assigned[i][j])),
+				Bounds: Rect{X0: xs[j], Y0: ys[i+1], X1: xs[j+1], Y1: ys[i]},
+			}
+		}
+	}
+
+	table := Table{Bounds: bounds, Cells: removeEmpty(cells), Ruled: true}
+	return table, len(table.Cells) > 0
+}
+
+// mergeRuleLines joins collinear lines that overlap or touch.
+func mergeRuleLines(lines []ruleLine) []ruleLine {
+	sort.Slice(lines, func(i, j int) bool {
+		if math.Abs(lines[i].pos-lines[j].pos) > ruleSnap {
+			return lines[i].pos < lines[j].pos
+		}
+		return lines[i].lo < lines[j].lo
+	})
+
+	var merged []ruleLine
+	for _, l := range lines {
+		if n := len(merged); n > 0 {
+			last := &merged[n-1]
+			if math.Abs(last.pos-l.pos) <= ruleSnap && l.lo <= last.hi+ruleSnap {
+				last.hi = math.Max(last.hi, l.hi)
+				continue
+			}
+		}
+		merged = append(merged, l)
+	}
+	return merged
+}
+
+// clusterValues sorts values and collapses runs within ruleSnap of each other
+// to their mean.
+func clusterValues(values []float64) []float64 {
+	sort.Float64s(values)
+
+	var clusters []float64
+	var sum float64
+	var count int
+	for i, v := range values {
+		if i > 0 && v-values[i-1] > ruleSnap {
+			clusters = append(clusters, sum/float64(count))
+			sum, count = 0, 0
+		}
+		sum += v
+		count++
+	}
+	if count > 0 {
+		clusters = append(clusters, sum/float64(count))
+	}
+	return clusters
+}
+
+// removeEmpty drops rows and columns in which every cell is empty.
+func removeEmpty(cells [][]Cell) [][]Cell {
+	if len(cells) == 0 {
+		return nil
+	}
+
+	keepCol := make([]bool, len(cells[0]))
+	var rows [][]Cell
+	for _, row := range cells {
+		empty := true
+		for j, cell := range row {
+			if cell.Text != "" {
+				empty = false
+				keepCol[j] = true
+			}
+		}
+		if !empty {
+			rows = append(rows, row)
+		}
+	}
+
+	for i, row := range rows {
+		kept := make([]Cell, 0, len(row))
+		for j, cell := range row {
+			if keepCol[j] {
+				kept = append(kept, cell)
+			}
+		}
+		rows[i] = kept
+	}
+
+	return rows
+}
+
+// readingOrder sorts spans top to bottom, then left to right.
+func readingOrder(spans []TextSpan) []TextSpan {
+	sorted := append([]TextSpan(nil), spans...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		size := math.Max(math.Max(a.FontSize, b.FontSize), 1)
+		if math.Abs(a.Y-b.Y) > size*0.5 {
+			return a.Y > b.Y
+		}
+		return a.X < b.X
+	})
+	return sorted
+}
+
+// textLine is a group of spans sharing a baseline, split into chunks at wide
+// horizontal gaps.
+type textLine struct {
+	y, size float64
+	chunks  []textChunk
+}
+
+type textChunk struct {
+	spans  []TextSpan
+	x0, x1 float64
+}
+
+// groupLines groups spans into lines by baseline, ordered top to bottom, and
+// splits each line into chunks separated by at least columnGap font sizes.
+func groupLines(spans []TextSpan) []textLine {
+	sorted := readingOrder(spans)
+
+	var lines []textLine
+	var current []TextSpan
+	flush := func() {
+		if len(current) > 0 {
+			lines = append(lines, newTextLine(current))
+			current = nil
+		}
+	}
+
+	for _, s := range sorted {
+		if len(current) > 0 {
+			first := current[0]
+			size := math.Max(math.Max(first.FontSize, s.FontSize), 1)
+			if math.Abs(first.Y-s.Y) > size*0.5 {
+				flush()
+			}
+		}
+		current = append(current, s)
+	}
+	flush()
+
+	return lines
+}
+
+func newTextLine(spans []TextSpan) textLine {
+	sort.SliceStable(spans, func(i, j int) bool { return spans[i].X < spans[j].X })
+
+	line := textLine{y: spans[0].Y}
+	for _, s := range spans {
+		line.size = math.Max(line.size, s.FontSize)
+	}
+
+	for _, s := range spans {
+		if n := len(line.chunks); n > 0 {
+			last := &line.chunks[n-1]
+			if s.X-last.x1 < columnGap*math.Max(line.size, 1) {
+				last.spans = append(last.spans, s)
+				last.x1 = math.Max(last.x1, s.X+s.Width)
+				continue
+			}
+		}
+		line.chunks = append(line.chunks, textChunk{
+			spans: []TextSpan{s},
+			x0:    s.X,
+			x1:    s.X + s.Width,
+		})
+	}
+
+	return line
+}
+
+func streamTables(spans []TextSpan) []Table {
+	lines := groupLines(spans)
+
+	var tables []Table
+	var run []textLine
+	flush := func() {
+		if len(run) >= minStreamRows {
+			if table, ok := buildStreamTable(run); ok {
+				tables = append(tables, table)
+			}
+		}
+		run = nil
+	}
+
+	for _, line := range lines {
+		if len(line.chunks) < 2 {
+			flush()
+			continue
+		}
+		if n := len(run); n > 0 {
+			prev := run[n-1]
+			if prev.y-line.y > maxRowSpacing*math.Max(prev.size, line.size) {
+				flush()
+			}
+		}
+		run = append(run, line)
+	}
+	flush()
+
+	return tables
+}
+
+// buildStreamTable derives columns from the union of chunk extents across the
+// rows and assigns each chunk to the column it overlaps most.
+func buildStreamTable(rows []textLine) (Table, bool) {
+	var intervals [][2]float64
+	for _, row := range rows {
+		for _, c := range row.chunks {
+			intervals = append(intervals, [2]float64{c.x0, c.x1})
+		}
+	}
+	sort.Slice(intervals, func(i, j int) bool { return intervals[i][0] < intervals[j][0] })
+
+	var columns [][2]float64
+	for _, iv := range intervals {
+		if n := len(columns); n > 0 && iv[0] <= columns[n-1][1] {
+			columns[n-1][1] = math.Max(columns[n-1][1], iv[1])
+			continue
+		}
+		columns = append(columns, iv)
+	}
+	if len(columns) < 2 {
+		return Table{}, false
+	}
+
+	table := Table{Bounds: Rect{X0: columns[0][0], X1: columns[len(columns)-1][1], Y0: math.Inf(1), Y1: math.Inf(-1)}}
+
+	for _, row := range rows {
+		top := row.y + 0.8*row.size
+		bottom := row.y - 0.2*row.size
+		table.Bounds.Y0 = math.Min(table.Bounds.Y0, bottom)
+		table.Bounds.Y1 = math.Max(table.Bounds.Y1, top)
+
+		texts := make([][]string, len(columns))
+		for _, c := range row.chunks {
+			best, bestOverlap := 0, -1.0
+			for j, col := range columns {
+				overlap := math.Min(c.x1, col[1]) - math.Max(c.x0, col[0])
+				if overlap > bestOverlap {
+					best, bestOverlap = j, overlap
+				}
+			}
+			texts[best] = append(texts[best], joinSpans(c.spans))
+		}
+
+		cells := make([]Cell, len(columns))
+		for j, col := range columns {
+			cells[j] = Cell{
+				Text:   strings.Join(texts[j], " "),
+				Bounds: Rect{X0: col[0], Y0: bottom, X1: col[1], Y1: top},
+			}
+		}
+		table.Cells = append(table.Cells, cells)
+	}
+
+	filled, length := 0, 0
+	for _, row := range table.Cells {
+		for _, cell := range row {
+			if cell.Text != "" {
+				filled++
+				length += utf8.RuneCountInString(cell.Text)
+			}
+		}
+	}
+	if length > maxMeanCellLength*filled {
+		return Table{}, false
+	}
+
+	return table, true
+}
@@ -0,0 +1,195 @@
+package document
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// tableFontSize is the font size of rendered table text, in points.
+	tableFontSize = 9.0
+
+	// tableCellPadding is the horizontal and vertical cell padding, in points.
+	tableCellPadding = 4.0
+
+	// tableMaxCellChars is the number of characters of a cell shown in a
+	// rendered table; longer values are truncated with "...".
+	tableMaxCellChars = 40
+
+	// tableMargin is the page margin around a rendered table, in points.
+	tableMargin = 24.0
+)
+
+// writeTablePDF lays out rows as a single-page PDF with a ruled grid, sized to
+// fit the table. Text is set in Courier (Courier-Bold for the header row when
+// header is true) so column widths follow character counts. The optional
+// title is drawn above the table.
+//
+// The standard fonts use WinAnsiEncoding, so characters outside it (Latin-1
+// plus typographic characters such as "€", curly quotes, and dashes) are
+// replaced with "?".
+func writeTablePDF(rows [][]string, header bool, title string) pagePDF {
+	charWidth := tableFontSize * 0.6
+	rowHeight := tableFontSize + 2*tableCellPadding
+
+	cols := 0
+	for _, row := range rows {
+		cols = max(cols, len(row))
+	}
+
+	widths := make([]float64, cols)
+	for j := range widths {
+		chars := 1
+		for _, row := range rows {
+			if j < len(row) {
+				chars = max(chars, utf8.RuneCountInString(truncateCell(row[j])))
+			}
+		}
+		widths[j] = float64(chars)*charWidth + 2*tableCellPadding
+	}
+
+	tableWidth := 0.0
+	for _, w := range widths {
+		tableWidth += w
+	}
+	tableHeight := float64(len(rows)) * rowHeight
+
+	titleHeight := 0.0
+	if title != "" {
+		titleHeight = rowHeight
+	}
+
+	pageWidth := max(tableWidth, float64(utf8.RuneCountInString(title))*charWidth) + 2*tableMargin
+	pageHeight := tableHeight + titleHeight + 2*tableMargin
+
+	var content strings.Builder
+	top := pageHeight - tableMargin
+
+	if title != "" {
+		fmt.Fprintf(&content, "BT /F2 %g Tf %g %g Td (%s) Tj ET\n",
+			tableFontSize, tableMargin, top-tableFontSize-tableCellPadding/2, pdfString(title))
+		top -= titleHeight
+	}
+
+	content.WriteString("0.5 w 0.6 G\n")
+	for i := 0; i <= len(rows); i++ {
+		y := top - float64(i)*rowHeight
+		fmt.Fprintf(&content, "%g %g m %g %g l S\n", tableMargin, y, tableMargin+tableWidth, y)
+	}
+	x := tableMargin
+	for j := 0; j <= cols; j++ {
+		fmt.Fprintf(&content, "%g %g m %g %g l S\n", x, top, x, top-tableHeight)
+		if j < cols {
+			x += widths[j]
+		}
+	}
+
+	for i, row := range rows {
+		font := "/F1"
+		if header && i == 0 {
+			font = "/F2"
+		}
+		baseline := top - float64(i+1)*rowHeight + tableCellPadding + 0.2*tableFontSize
+
+		x := tableMargin
+		for j, cell := range row {
+			if cell != "" {
+				fmt.Fprintf(&content, "BT %s %g Tf %g %g Td (%s) Tj ET\n",
+					font, tableFontSize, x+tableCellPadding, baseline, pdfString(truncateCell(cell)))
+			}
+			x += widths[j]
+		}
+	}
+
+	stream := content.String()
+
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %g %g] "+
+			"/Resources << /Font << /F1 4 0 R /F2 5 0 R >> >> /Contents 6 0 R >>", pageWidth, pageHeight),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Courier-Bold /Encoding /WinAnsiEncoding >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(stream), stream),
+	}
+
+	return pagePDF{data: assemblePDF(objects), width: pageWidth, height: pageHeight}
+}
+
+// pagePDF is a generated single-page PDF with the size of its page in
+// points, which fit limits are applied to when the page is rendered (see
+// fitDPI).
+type pagePDF struct {
+	data          []byte
+	width, height float64
+}
+
+// assemblePDF writes numbered objects (starting at 1, the first being the
+// catalog) with a cross-reference table and trailer.
+func assemblePDF(objects []string) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+
+	return buf.Bytes()
+}
+
+// truncateCell shortens a cell to a single line of at most tableMaxCellChars
+// characters.
+func truncateCell(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if utf8.RuneCountInString(s) <= tableMaxCellChars {
+		return s
+	}
+	runes := []rune(s)
+	return string(runes[:tableMaxCellChars-3]) + "..."
+}
+
+// winAnsiCodes maps the characters WinAnsiEncoding places outside Latin-1
+// (e.g., "€", curly quotes, and dashes) to their codes, inverting winAnsiHigh.
+var winAnsiCodes = func() map[rune]byte {
+	codes := make(map[rune]byte, len(winAnsiHigh))
+	for code, r := range winAnsiHigh {
+		codes[r] = code
+	}
+	return codes
+}()
+
+// pdfString escapes s for use in a PDF literal string set in a font with
+// WinAnsiEncoding, encoding characters above ASCII as octal escapes of their
+// WinAnsi codes and replacing characters outside the encoding with "?".
+func pdfString(s string) string {
+	var builder strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			builder.WriteByte('\\')
+			builder.WriteRune(r)
+		case r >= 0x20 && r < 0x7f:
+			builder.WriteRune(r)
+		case r >= 0xa0 && r <= 0xff:
+			fmt.Fprintf(&builder, "\\%03o", r)
+		default:
+			if code, ok := winAnsiCodes[r]; ok {
+				fmt.Fprintf(&builder, "\\%03o", code)
+			} else {
+				builder.WriteByte('?')
+			}
+		}
+	}
+	return builder.String()
+}
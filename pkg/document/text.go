@@ -0,0 +1,146 @@
+package document
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+)
+
+// Rect is an axis-aligned rectangle in PDF user space, where the origin is the
+// bottom-left corner of the page and Y increases upward.
+type Rect struct {
+	X0, Y0, X1, Y1 float64
+}
+
+// Width returns the horizontal extent of the rectangle.
+func (r Rect) Width() float64 {
+	return r.X1 - r.X0
+}
+
+// Height returns the vertical extent of the rectangle.
+func (r Rect) Height() float64 {
+	return r.Y1 - r.Y0
+}
+
+// TextSpan is a run of text placed by a single text-showing operator.
+//
+// Positions are in PDF user space. X and Y identify the start of the text
+// baseline; Width is the horizontal advance of the run. Invisible spans use a
+// text render mode that paints nothing, as in OCR text layers over scans.
+type TextSpan struct {
+	Text      string
+	X         float64
+	Y         float64
+	Width     float64
+	FontSize  float64
+	Font      string
+	Invisible bool
+}
+
+// Bounds returns an approximate bounding box for the span, extending the
+// baseline by typical ascender and descender proportions of the font size.
+func (s TextSpan) Bounds() Rect {
+	return Rect{
+		X0: s.X,
+		Y0: s.Y - 0.2*s.FontSize,
+		X1: s.X + s.Width,
+		Y1: s.Y + 0.8*s.FontSize,
+	}
+}
+
+// interpret runs the content stream interpreter over the page.
+func (p *PDFPage) interpret() (*pageContent, error) {
+	p.doc.mu.Lock()
+	defer p.doc.mu.Unlock()
+
+	if p.doc.ctx == nil {
+		return nil, fmt.Errorf("document is closed")
+	}
+
+	pageDict, _, inherited, err := p.doc.ctx.PageDict(p.number, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read page %d: %w", p.number, err)
+	}
+
+	interp := newContentInterpreter(p.doc.ctx)
+	interp.content.box = pageBox(inherited)
+
+	data, err := p.doc.ctx.PageContent(pageDict, p.number)
+	if err != nil {
+		if errors.Is(err, model.ErrNoContent) {
+			return interp.content, nil
+		}
+		return nil, fmt.Errorf("failed to read page %d content: %w", p.number, err)
+	}
+
+	interp.run(data, inherited.Resources, 0)
+
+	return interp.content, nil
+}
+
+// pageBox returns the visible page region: the crop box when present,
+// otherwise the media box.
+func pageBox(attrs *model.InheritedPageAttrs) Rect {
+	box := attrs.CropBox
+	if box == nil {
+		box = attrs.MediaBox
+	}
+	if box == nil {
+		return Rect{}
+	}
+	return Rect{X0: box.LL.X, Y0: box.LL.Y, X1: box.UR.X, Y1: box.UR.Y}
+}
+
+// TextSpans returns the positioned text runs of the page in content stream order.
+//
+// Text is decoded using each font's ToUnicode map when present, falling back to
+// the font encoding. Glyph advances are computed from font width tables, so
+// positions are accurate enough for layout analysis but are not exact glyph
+// bounding boxes.
+func (p *PDFPage) TextSpans() ([]TextSpan, error) {
+	content, err := p.interpret()
+	if err != nil {
+		return nil, err
+	}
+	return content.spans, nil
+}
+
+// Text returns the page text in content stream order.
+//
+// Spans on the same baseline are joined with a space when separated by a
+// visible gap; a change of baseline starts a new line.
+func (p *PDFPage) Text() (string, error) {
+	spans, err := p.TextSpans()
+	if err != nil {
+		return "", err
+	}
+	return joinSpans(spans), nil
+}
+
+// joinSpans assembles spans into text, inferring spaces and line breaks from
+// their relative positions.
+func joinSpans(spans []TextSpan) string {
+	var builder strings.Builder
+
+	for i, span := range spans {
+		if i > 0 {
+			prev := spans[i-1]
+			size := math.Max(math.Max(prev.FontSize, span.FontSize), 1)
+
+			switch {
+			case math.Abs(span.Y-prev.Y) > size*0.5:
+				builder.WriteString("\n")
+			case span.X-(prev.X+prev.Width) > size*0.15 || span.X < prev.X:
+				if !strings.HasSuffix(prev.Text, " ") && !strings.HasPrefix(span.Text, " ") {
+					builder.WriteString(" ")
+				}
+			}
+		}
+		builder.WriteString(span.Text)
+	}
+
+	return strings.TrimSpace(builder.String())
+}
@@ -0,0 +1,100 @@
+package document
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// textPageWidth is the width of rendered text pages, in points (US
+	// Letter).
+	textPageWidth = 612.0
+
+	// textPageMinHeight is the minimum height of rendered text pages, in
+	// points; pages with more text grow taller instead of overflowing.
+	textPageMinHeight = 792.0
+
+	// textMargin is the page margin of rendered text pages, in points.
+	textMargin = 54.0
+
+	// textFontSize is the font size of body text, in points.
+	textFontSize = 11.0
+)
+
+// textHeadingSizes are the font sizes of heading levels 1-6, in points.
+var textHeadingSizes = [6]float64{20, 16, 14, 12, 12, 12}
+
+// writeTextPDF lays out readable text (paragraphs separated by blank lines,
+// headings marked with ATX markers as produced by htmlReadableText) as a
+// single-page PDF. Text is set in Helvetica (Helvetica-Bold for headings)
+// and wrapped to the page width; the page grows taller as needed so no text
+// is cut off.
+//
+// The standard fonts use WinAnsiEncoding, so characters outside it (Latin-1
+// plus typographic characters such as "€", curly quotes, and dashes) are
+// replaced with "?".
+func writeTextPDF(text string) pagePDF {
+	var lines []slideLine
+	for i, block := range strings.Split(text, "\n\n") {
+		if i > 0 {
+			lines = append(lines, slideLine{size: textFontSize / 2})
+		}
+
+		size, marker := textFontSize, ""
+		if level := headingLevel(block); level > 0 {
+			size, marker = textHeadingSizes[level-1], "#"
+			block = strings.TrimSpace(block[level:])
+		}
+
+		chars := int((textPageWidth - 2*textMargin) / (size * slideCharWidth))
+		for _, line := range strings.Split(block, "\n") {
+			for _, wrapped := range wrapWords(line, chars) {
+				lines = append(lines, slideLine{text: wrapped, marker: marker, size: size})
+			}
+		}
+	}
+
+	height := max(textPageMinHeight, linesHeight(lines)+2*textMargin)
+
+	var content strings.Builder
+	y := height - textMargin
+	for _, line := range lines {
+		y -= line.size * 1.2
+		if line.text == "" {
+			continue
+		}
+		font := "/F1"
+		if line.marker != "" {
+			font = "/F2"
+		}
+		fmt.Fprintf(&content, "BT %s %g Tf %g %g Td (%s) Tj ET\n",
+			font, line.size, textMargin, y+0.2*line.size, pdfString(line.text))
+	}
+
+	stream := content.String()
+
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %g %g] "+
+			"/Resources << /Font << /F1 4 0 R /F2 5 0 R >> >> /Contents 6 0 R >>", textPageWidth, height),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(stream), stream),
+	}
+
+	return pagePDF{data: assemblePDF(objects), width: textPageWidth, height: height}
+}
+
+// headingLevel returns the level of a block marked as a heading ("## Title"),
+// or 0 for other blocks.
+func headingLevel(block string) int {
+	level := 0
+	for level < len(block) && block[level] == '#' {
+		level++
+	}
+	if level == 0 || level > 6 || level >= len(block) || block[level] != ' ' {
+		return 0
+	}
+	return level
+}
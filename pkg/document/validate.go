@@ -0,0 +1,403 @@
+package document
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/JaimeStill/document-context/pkg/config"
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/log"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+)
+
+// ValidationReport describes how a document fares against PDF validation.
+//
+// pdfcpu stops at the first violation that fails a mode, so each mode
+// contributes at most one error message. Violations that relaxed validation
+// tolerates are reported as they are encountered.
+type ValidationReport struct {
+	// Strict reports whether the document passes strict validation.
+	Strict bool
+
+	// Relaxed reports whether the document passes relaxed validation.
+	Relaxed bool
+
+	// Repaired reports whether the document was repaired when opened.
+	Repaired bool
+
+	// Warnings lists the repairs performed when the document was opened,
+	// the strict validation error when only relaxed validation passes, and
+	// the spec violations pdfcpu digested, repaired, or skipped during
+	// relaxed validation (e.g., "digested: duplicate key \"Root\""), which
+	// are captured through pdfcpu's validation logger (see
+	// PDFDocument.Validate).
+	Warnings []string
+
+	// Errors lists violations that fail relaxed validation.
+	Errors []string
+}
+
+// Valid reports whether the document has no validation errors. Warnings do
+// not affect validity.
+func (r *ValidationReport) Valid() bool {
+	return len(r.Errors) == 0
+}
+
+// Warnings returns the problems encountered while opening the document.
+//
+// Warnings are only produced when the document was opened with repair enabled
+// and could not be read or validated as-is. An empty result means the document
+// opened cleanly.
+func (d *PDFDocument) Warnings() []string {
+	return d.warnings
+}
+
+// Repaired reports whether the document required reconstruction to open.
+func (d *PDFDocument) Repaired() bool {
+	return d.repairedPath != ""
+}
+
+// Validate checks the document against strict and relaxed validation.
+//
+// Validation runs on freshly parsed copies of the document, so it neither
+// depends on nor alters the mode the document was opened with. For repaired
+// documents, the repaired copy is validated and the repair warnings are
+// included in the report.
+//
+// pdfcpu reports tolerated violations only through its process-wide
+// validation logger (pdfcpu's log.SetValidateLogger), which has no getter, so
+// Validate takes ownership of that logger: it installs its own logger for the
+// relaxed pass and clears it afterwards, discarding any validation logger the
+// application installed. Applications relying on pdfcpu's validation logger
+// must install it again after calling Validate. PDF parsing in this package
+// waits while the logger is installed, but pdfcpu calls made elsewhere in the
+// process at the same time may add their messages to the report.
+//
+// Returns an error only if the document is closed or cannot be read.
+func (d *PDFDocument) Validate() (*ValidationReport, error) {
+	d.mu.Lock()
+	closed := d.ctx == nil
+	d.mu.Unlock()
+	if closed {
+		return nil, fmt.Errorf("document is closed")
+	}
+
+	data, err := os.ReadFile(d.source())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read PDF: %w", err)
+	}
+
+	report := &ValidationReport{Repaired: d.Repaired()}
+	report.Warnings = append(report.Warnings, d.warnings...)
+
+	strictErr := validatePDF(data, config.ValidationStrict)
+	violations, relaxedErr := validateRelaxed(data)
+
+	report.Strict = strictErr == nil
+	report.Relaxed = relaxedErr == nil
+
+	switch {
+	case relaxedErr != nil:
+		report.Errors = append(report.Errors, relaxedErr.Error())
+		if strictErr != nil && strictErr.Error() != relaxedErr.Error() {
+			report.Errors = append(report.Errors, strictErr.Error())
+		}
+	case strictErr != nil:
+		report.Warnings = append(report.Warnings, strictErr.Error())
+	}
+	report.Warnings = append(report.Warnings, violations...)
+
+	return report, nil
+}
+
+// pdfcpuLog guards pdfcpu's global loggers: parsing holds it for reading, and
+// validateRelaxed holds it exclusively while its logger is installed, so the
+// messages it captures come only from the document being validated.
+var pdfcpuLog sync.RWMutex
+
+// violationLogger is a pdfcpu logger collecting the spec violations pdfcpu
+// reports as digested, repaired, or skipped, without duplicates. Other
+// validation trace output is discarded.
+type violationLogger struct {
+	messages []string
+}
+
+func (l *violationLogger) Printf(format string, args ...any) {
+	l.record(fmt.Sprintf(format, args...))
+}
+
+func (l *violationLogger) Println(args ...any) {
+	l.record(fmt.Sprint(args...))
+}
+
+func (l *violationLogger) Fatalf(format string, args ...any) {
+	l.record(fmt.Sprintf(format, args...))
+}
+
+func (l *violationLogger) Fatalln(args ...any) {
+	l.record(fmt.Sprint(args...))
+}
+
+func (l *violationLogger) record(msg string) {
+	msg, ok := strings.CutPrefix(strings.TrimSpace(msg), "pdfcpu ")
+	if !ok {
+		return
+	}
+	for _, topic := range []string{"digested: ", "repaired: ", "skipped: "} {
+		if strings.HasPrefix(msg, topic) {
+			if !slices.Contains(l.messages, msg) {
+				l.messages = append(l.messages, msg)
+			}
+			return
+		}
+	}
+}
+
+// validateRelaxed validates data in relaxed mode, returning the violations
+// pdfcpu tolerated along the way (see violationLogger).
+func validateRelaxed(data []byte) ([]string, error) {
+	pdfcpuLog.Lock()
+	defer pdfcpuLog.Unlock()
+
+	logger := &violationLogger{}
+	log.SetValidateLogger(logger)
+	defer log.SetValidateLogger(nil)
+
+	_, err := parsePDF(data, config.ValidationRelaxed)
+	return logger.messages, err
+}
+
+// readPDF parses data and validates it using mode.
+func readPDF(data []byte, mode config.ValidationMode) (*model.Context, error) {
+	pdfcpuLog.RLock()
+	defer pdfcpuLog.RUnlock()
+
+	return parsePDF(data, mode)
+}
+
+// parsePDF implements readPDF without guarding pdfcpu's loggers.
+func parsePDF(data []byte, mode config.ValidationMode) (*model.Context, error) {
+	conf := model.NewDefaultConfiguration()
+
+	switch mode {
+	case config.ValidationStrict:
+		conf.ValidationMode = model.ValidationStrict
+	case config.ValidationRelaxed, config.ValidationNone:
+		conf.ValidationMode = model.ValidationRelaxed
+	default:
+		return nil, fmt.Errorf("unsupported validation mode: %s", mode)
+	}
+
+	ctx, err := api.ReadContext(bytes.NewReader(data), conf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read PDF: %w", err)
+	}
+
+	if mode == config.ValidationNone {
+		if err := ctx.EnsurePageCount(); err != nil {
+			return nil, fmt.Errorf("failed to read page tree: %w", err)
+		}
+		return ctx, nil
+	}
+
+	if err := api.ValidateContext(ctx); err != nil {
+		return nil, fmt.Errorf("%s validation failed: %w", mode, err)
+	}
+
+	return ctx, nil
+}
+
+func validatePDF(data []byte, mode config.ValidationMode) error {
+	_, err := readPDF(data, mode)
+	return err
+}
+
+// openPDF parses the document bytes data using cfg, repairing them if
+// enabled.
+//
+// Repair proceeds in two stages. A document that cannot be parsed has its
+// cross-reference table rebuilt from the object headers in the file and is
+// written to a temporary file. A document that parses but fails validation is
+// opened without validation. Each stage taken is recorded as a warning.
+//
+// Returns the parsed context, the path of the repaired copy (empty if no
+// rewrite was needed), and any warnings.
+func openPDF(data []byte, cfg config.DocumentConfig) (*model.Context, string, []string, error) {
+	ctx, err := readPDF(data, cfg.Validation)
+	if err == nil {
+		return ctx, "", nil, nil
+	}
+	if !cfg.Repair {
+		return nil, "", nil, fmt.Errorf("failed to open PDF: %w", err)
+	}
+
+	var (
+		warnings     []string
+		repairedPath string
+	)
+
+	if _, parseErr := readPDF(data, config.ValidationNone); parseErr != nil {
+		rebuilt, rebuildErr := rebuildXRef(data)
+		if rebuildErr != nil {
+			return nil, "", nil, fmt.Errorf("failed to open PDF: %w (repair failed: %v)", parseErr, rebuildErr)
+		}
+
+		tmp, tmpErr := os.CreateTemp("", "repaired-*.pdf")
+		if tmpErr != nil {
+			return nil, "", nil, fmt.Errorf("failed to create temp file: %w", tmpErr)
+		}
+		repairedPath = tmp.Name()
+		_, writeErr := tmp.Write(rebuilt)
+		tmp.Close()
+		if writeErr != nil {
+			os.Remove(repairedPath)
+			return nil, "", nil, fmt.Errorf("failed to write repaired PDF: %w", writeErr)
+		}
+
+		data = rebuilt
+		warnings = append(warnings, fmt.Sprintf("rebuilt cross-reference table: %v", parseErr))
+	}
+
+	ctx, validateErr := readPDF(data, cfg.Validation)
+	if validateErr != nil {
+		ctx, err = readPDF(data, config.ValidationNone)
+		if err != nil {
+			if repairedPath != "" {
+				os.Remove(repairedPath)
+			}
+			return nil, "", nil, fmt.Errorf("failed to open repaired PDF: %w", err)
+		}
+		warnings = append(warnings, fmt.Sprintf("opened without validation: %v", validateErr))
+	}
+
+	return ctx, repairedPath, warnings, nil
+}
+
+var (
+	objectHeader = regexp.MustCompile(`(\d+)[ \t\r\n]+(\d+)[ \t\r\n]+obj\b`)
+	rootEntry    = regexp.MustCompile(`/Root[ \t\r\n]*(\d+)[ \t\r\n]+(\d+)[ \t\r\n]+R`)
+	infoEntry    = regexp.MustCompile(`/Info[ \t\r\n]*(\d+)[ \t\r\n]+(\d+)[ \t\r\n]+R`)
+	catalogType  = regexp.MustCompile(`/Type[ \t\r\n]*/Catalog\b`)
+)
+
+type objectLocation struct {
+	offset     int
+	generation int
+	end        int
+}
+
+// rebuildXRef reconstructs the cross-reference table of a damaged PDF.
+//
+// Object headers ("N G obj") are located by scanning the file, skipping stream
+// data. A new cross-reference section and trailer are appended to the original
+// bytes, so object offsets remain valid. When an object number appears more
+// than once, the last definition wins, matching incremental update semantics.
+//
+// The document catalog is taken from the last /Root trailer entry, falling back
+// to the last object declaring /Type /Catalog. Objects stored only in
+// compressed object streams are not recovered.
+func rebuildXRef(data []byte) ([]byte, error) {
+	objects := make(map[int]objectLocation)
+	maxObj := 0
+
+	for pos := 0; pos < len(data); {
+		loc := objectHeader.FindSubmatchIndex(data[pos:])
+		if loc == nil {
+			break
+		}
+
+		start := pos + loc[0]
+		headerEnd := pos + loc[1]
+		num, _ := strconv.Atoi(string(data[pos+loc[2] : pos+loc[3]]))
+		gen, _ := strconv.Atoi(string(data[pos+loc[4] : pos+loc[5]]))
+		pos = headerEnd
+
+		if start > 0 && !isPDFWhitespace(data[start-1]) {
+			continue
+		}
+
+		end := skipObjectBody(data, headerEnd)
+		objects[num] = objectLocation{offset: start, generation: gen, end: end}
+		maxObj = max(maxObj, num)
+		pos = end
+	}
+
+	if len(objects) == 0 {
+		return nil, fmt.Errorf("no objects found")
+	}
+
+	root := lastReference(rootEntry, data)
+	if root == "" {
+		for num := maxObj; num > 0 && root == ""; num-- {
+			obj, ok := objects[num]
+			if ok && catalogType.Match(data[obj.offset:obj.end]) {
+				root = fmt.Sprintf("%d %d R", num, obj.generation)
+			}
+		}
+	}
+	if root == "" {
+		return nil, fmt.Errorf("document catalog not found")
+	}
+
+	var buf bytes.Buffer
+	buf.Write(data)
+	if len(data) > 0 && !isPDFWhitespace(data[len(data)-1]) {
+		buf.WriteByte('\n')
+	}
+
+	xrefOffset := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n", maxObj+1)
+	for num := 0; num <= maxObj; num++ {
+		obj, ok := objects[num]
+		if !ok {
+			buf.WriteString("0000000000 65535 f \n")
+			continue
+		}
+		fmt.Fprintf(&buf, "%010d %05d n \n", obj.offset, obj.generation)
+	}
+
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root %s", maxObj+1, root)
+	if info := lastReference(infoEntry, data); info != "" {
+		fmt.Fprintf(&buf, " /Info %s", info)
+	}
+	fmt.Fprintf(&buf, " >>\nstartxref\n%d\n%%%%EOF\n", xrefOffset)
+
+	return buf.Bytes(), nil
+}
+
+// skipObjectBody returns the offset just past the endobj keyword of the object
+// whose header ends at pos, skipping stream data so binary content is not
+// mistaken for object headers.
+func skipObjectBody(data []byte, pos int) int {
+	endObj := bytes.Index(data[pos:], []byte("endobj"))
+	stream := bytes.Index(data[pos:], []byte("stream"))
+
+	if stream >= 0 && (endObj < 0 || stream < endObj) {
+		streamEnd := bytes.Index(data[pos+stream:], []byte("endstream"))
+		if streamEnd < 0 {
+			return len(data)
+		}
+		pos += stream + streamEnd + len("endstream")
+		endObj = bytes.Index(data[pos:], []byte("endobj"))
+	}
+
+	if endObj < 0 {
+		return len(data)
+	}
+	return pos + endObj + len("endobj")
+}
+
+func lastReference(re *regexp.Regexp, data []byte) string {
+	matches := re.FindAllSubmatch(data, -1)
+	if len(matches) == 0 {
+		return ""
+	}
+	last := matches[len(matches)-1]
+	return fmt.Sprintf("%s %s R", last[1], last[2])
+}
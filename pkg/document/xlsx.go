@@ -0,0 +1,377 @@
+package document
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Worksheet dimensions defined by the SpreadsheetML format. Cell references
+// beyond them are rejected before any rows are allocated.
+const (
+	xlsxMaxRows    = 1 << 20
+	xlsxMaxColumns = 1 << 14
+)
+
+// readXLSX reads the worksheets of an XLSX workbook in workbook order.
+func readXLSX(path string) ([]sheet, error) {
+	pkg, err := openOOXML(path)
+	if err != nil {
+		return nil, err
+	}
+	defer pkg.Close()
+
+	main, err := pkg.mainPart("xl/workbook.xml")
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := pkg.read(main)
+	if err != nil {
+		return nil, err
+	}
+
+	var workbook struct {
+		Properties struct {
+			Date1904 string `xml:"date1904,attr"`
+		} `xml:"workbookPr"`
+		Sheets []struct {
+			Name string `xml:"name,attr"`
+			RID  string `xml:"id,attr"`
+		} `xml:"sheets>sheet"`
+	}
+	if err := xml.Unmarshal(data, &workbook); err != nil {
+		return nil, fmt.Errorf("failed to parse workbook: %w", err)
+	}
+
+	rels, err := pkg.relationships(main)
+	if err != nil {
+		return nil, err
+	}
+	targets := make(map[string]string, len(rels))
+	for _, r := range rels {
+		targets[r.ID] = r.Target
+	}
+
+	date1904 := workbook.Properties.Date1904
+	reader := &xlsxReader{date1904: date1904 == "1" || date1904 == "true"}
+
+	if name, err := pkg.related(main, "/sharedStrings"); err != nil {
+		return nil, err
+	} else if name != "" {
+		if err := reader.loadSharedStrings(pkg, name); err != nil {
+			return nil, err
+		}
+	}
+
+	if name, err := pkg.related(main, "/styles"); err != nil {
+		return nil, err
+	} else if name != "" {
+		if err := reader.loadStyles(pkg, name); err != nil {
+			return nil, err
+		}
+	}
+
+	sheets := make([]sheet, 0, len(workbook.Sheets))
+	for _, ws := range workbook.Sheets {
+		target, ok := targets[ws.RID]
+		if !ok {
+			return nil, fmt.Errorf("sheet %q has no worksheet part", ws.Name)
+		}
+
+		data, err := pkg.read(target)
+		if err != nil {
+			return nil, err
+		}
+
+		rows, err := reader.parseWorksheet(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse sheet %q: %w", ws.Name, err)
+		}
+
+		s, err := newSheet(ws.Name, rows)
+		if err != nil {
+			return nil, err
+		}
+		sheets = append(sheets, s)
+	}
+
+	return sheets, nil
+}
+
+type xlsxReader struct {
+	date1904 bool
+	strings  []string
+
+	// dateStyles reports, per cell style index, whether the style's number
+	// format displays dates or times.
+	dateStyles []bool
+}
+
+// loadSharedStrings reads the shared string table. Rich text runs are
+// concatenated; phonetic annotations are ignored.
+func (r *xlsxReader) loadSharedStrings(pkg *ooxmlPackage, name string) error {
+	data, err := pkg.read(name)
+	if err != nil {
+		return err
+	}
+
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	var current strings.Builder
+	inText, inPhonetic := false, false
+
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to parse shared strings: %w", err)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "si":
+				current.Reset()
+			case "t":
+				inText = !inPhonetic
+			case "rPh":
+				inPhonetic = true
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "si":
+				r.strings = append(r.strings, current.String())
+			case "t":
+				inText = false
+			case "rPh":
+				inPhonetic = false
+			}
+		case xml.CharData:
+			if inText {
+				current.Write(t)
+			}
+		}
+	}
+
+	return nil
+}
+
+// loadStyles determines which cell styles use date or time number formats.
+func (r *xlsxReader) loadStyles(pkg *ooxmlPackage, name string) error {
+	data, err := pkg.read(name)
+	if err != nil {
+		return err
+	}
+
+	var styles struct {
+		NumFmts []struct {
+			ID   int    `xml:"numFmtId,attr"`
+			Code string `xml:"formatCode,attr"`
+		} `xml:"numFmts>numFmt"`
+		CellXfs []struct {
+			NumFmtID int `xml:"numFmtId,attr"`
+		} `xml:"cellXfs>xf"`
+	}
+	if err := xml.Unmarshal(data, &styles); err != nil {
+		return fmt.Errorf("failed to parse styles: %w", err)
+	}
+
+	custom := make(map[int]string, len(styles.NumFmts))
+	for _, f := range styles.NumFmts {
+		custom[f.ID] = f.Code
+	}
+
+	r.dateStyles = make([]bool, len(styles.CellXfs))
+	for i, xf := range styles.CellXfs {
+		if code, ok := custom[xf.NumFmtID]; ok {
+			r.dateStyles[i] = isDateFormat(code)
+		} else {
+			r.dateStyles[i] = (xf.NumFmtID >= 14 && xf.NumFmtID <= 22) || (xf.NumFmtID >= 45 && xf.NumFmtID <= 47)
+		}
+	}
+
+	return nil
+}
+
+// isDateFormat reports whether a number format code displays a date or time:
+// it contains date or time tokens outside quoted literals and brackets.
+func isDateFormat(code string) bool {
+	inQuote, inBracket := false, false
+	for i := 0; i < len(code); i++ {
+		c := code[i]
+		switch {
+		case c == '"':
+			inQuote = !inQuote
+		case inQuote:
+		case c == '\\' || c == '_' || c == '*':
+			i++
+		case c == '[':
+			inBracket = true
+		case c == ']':
+			inBracket = false
+		case inBracket:
+		case strings.ContainsRune("dmyhsDMYHS", rune(c)):
+			return true
+		}
+	}
+	return false
+}
+
+// parseWorksheet reads the cell values of a worksheet into rows.
+func (r *xlsxReader) parseWorksheet(data []byte) ([][]string, error) {
+	var rows [][]string
+
+	dec := xml.NewDecoder(bytes.NewReader(data))
+
+	rowIndex := -1
+	colIndex := 0
+	var cellType, cellStyle, value string
+	var inValue, inInline, inCell bool
+	var inline strings.Builder
+
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "row":
+				if n, err := strconv.Atoi(attr(t, "r")); err == nil && n > 0 {
+					rowIndex = n - 1
+				} else {
+					rowIndex++
+				}
+				colIndex = 0
+			case "c":
+				inCell = true
+				cellType, cellStyle, value = attr(t, "t"), attr(t, "s"), ""
+				inline.Reset()
+				if col, ok := cellColumn(attr(t, "r")); ok {
+					colIndex = col
+				}
+			case "v":
+				inValue = inCell
+			case "t":
+				inInline = inCell && cellType == "inlineStr"
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "c":
+				text := r.cellText(cellType, cellStyle, value, inline.String())
+				if text != "" && rowIndex >= 0 {
+					if rowIndex >= xlsxMaxRows || colIndex >= xlsxMaxColumns {
+						return nil, fmt.Errorf("cell %s%d exceeds sheet limits", columnName(colIndex), rowIndex+1)
+					}
+					for len(rows) <= rowIndex {
+						rows = append(rows, nil)
+					}
+					for len(rows[rowIndex]) <= colIndex {
+						rows[rowIndex] = append(rows[rowIndex], "")
+					}
+					rows[rowIndex][colIndex] = text
+				}
+				inCell = false
+				colIndex++
+			case "v":
+				inValue = false
+			case "t":
+				inInline = false
+			}
+		case xml.CharData:
+			switch {
+			case inValue:
+				value += string(t)
+			case inInline:
+				inline.Write(t)
+			}
+		}
+	}
+
+	return rows, nil
+}
+
+// cellColumn returns the zero-based column of an A1 cell reference.
+func cellColumn(ref string) (int, bool) {
+	col := 0
+	n := 0
+	for _, c := range ref {
+		if c >= 'A' && c <= 'Z' {
+			col = col*26 + int(c-'A'+1)
+			n++
+			continue
+		}
+		break
+	}
+	if n == 0 {
+		return 0, false
+	}
+	return col - 1, true
+}
+
+// cellText converts a stored cell value to its displayed text.
+func (r *xlsxReader) cellText(cellType, style, value, inline string) string {
+	switch cellType {
+	case "s":
+		i, err := strconv.Atoi(value)
+		if err != nil || i < 0 || i >= len(r.strings) {
+			return ""
+		}
+		return r.strings[i]
+	case "inlineStr":
+		return inline
+	case "b":
+		if value == "1" {
+			return "TRUE"
+		}
+		return "FALSE"
+	case "str", "e":
+		return value
+	}
+
+	if value == "" {
+		return ""
+	}
+
+	if i, err := strconv.Atoi(style); err == nil && i >= 0 && i < len(r.dateStyles) && r.dateStyles[i] {
+		if serial, err := strconv.ParseFloat(value, 64); err == nil {
+			return r.formatDate(serial)
+		}
+	}
+
+	return value
+}
+
+// formatDate converts a spreadsheet serial date to ISO 8601: a date, a time
+// of day, or both, depending on the whole and fractional parts.
+func (r *xlsxReader) formatDate(serial float64) string {
+	epoch := time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+	if r.date1904 {
+		epoch = time.Date(1904, 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+
+	days := math.Floor(serial)
+	seconds := math.Round((serial - days) * 86400)
+	t := epoch.AddDate(0, 0, int(days)).Add(time.Duration(seconds) * time.Second)
+
+	switch {
+	case seconds == 0:
+		return t.Format("2006-01-02")
+	case days == 0:
+		return t.Format("15:04:05")
+	default:
+		return t.Format("2006-01-02 15:04:05")
+	}
+}
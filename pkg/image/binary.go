@@ -0,0 +1,27 @@
+package image
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// binaryVersion runs an external binary with its version flag and extracts
+// the version from the output using pattern's first capture group.
+//
+// Some tools print their version to stderr or exit non-zero after printing
+// it, so combined output is matched before the exit status is considered.
+func binaryVersion(path, flag string, pattern *regexp.Regexp) (string, error) {
+	output, err := exec.Command(path, flag).CombinedOutput()
+	if m := pattern.FindSubmatch(output); m != nil {
+		return string(m[1]), nil
+	}
+
+	name := filepath.Base(path)
+	if err != nil {
+		return "", fmt.Errorf("%s %s failed: %w\nOutput: %s", name, flag, err, string(output))
+	}
+	return "", fmt.Errorf("unrecognized %s version output: %q", name, strings.TrimSpace(string(output)))
+}
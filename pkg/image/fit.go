@@ -0,0 +1,57 @@
+package image
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/JaimeStill/document-context/pkg/config"
+)
+
+// HasFitLimits reports whether cfg sets any fit limit (MaxWidth, MaxHeight,
+// MaxLongEdge, or MaxMegapixels), in which case pages are rendered at an
+// effective DPI computed from their size rather than at cfg.DPI.
+func HasFitLimits(cfg config.ImageConfig) bool {
+	return cfg.MaxWidth > 0 || cfg.MaxHeight > 0 || cfg.MaxLongEdge > 0 || cfg.MaxMegapixels > 0
+}
+
+// FitDPI returns the largest DPI at which a page of the given size, in PDF
+// points (1/72 inch), renders within every fit limit of cfg.
+//
+// Each configured limit bounds the DPI independently and the smallest bound
+// wins, so output touches the tightest limit exactly: a Letter page
+// (612x792 pt) with MaxLongEdge 1568 renders at 142.54 DPI, 1212x1568 px.
+// Smaller pages are scaled up to the limit. The result is truncated to two
+// decimal places, keeping output within the limits and cache keys stable.
+//
+// Returns cfg.DPI when no fit limit is set or the page size is not positive.
+func FitDPI(cfg config.ImageConfig, width, height float64) float64 {
+	if !HasFitLimits(cfg) || width <= 0 || height <= 0 {
+		return float64(cfg.DPI)
+	}
+
+	dpi := math.Inf(1)
+	bound := func(pixels, points float64) {
+		dpi = math.Min(dpi, pixels*72/points)
+	}
+
+	if cfg.MaxWidth > 0 {
+		bound(float64(cfg.MaxWidth), width)
+	}
+	if cfg.MaxHeight > 0 {
+		bound(float64(cfg.MaxHeight), height)
+	}
+	if cfg.MaxLongEdge > 0 {
+		bound(float64(cfg.MaxLongEdge), math.Max(width, height))
+	}
+	if cfg.MaxMegapixels > 0 {
+		bound(math.Sqrt(cfg.MaxMegapixels*1e6), math.Sqrt(width*height))
+	}
+
+	return math.Floor(dpi*100) / 100
+}
+
+// formatDPI formats a DPI for command-line arguments and cache keys,
+// without a fractional part for whole values (e.g., "300", "142.54").
+func formatDPI(dpi float64) string {
+	return strconv.FormatFloat(dpi, 'f', -1, 64)
+}
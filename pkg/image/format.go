@@ -0,0 +1,55 @@
+package image
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/JaimeStill/document-context/pkg/config"
+)
+
+// validateFormat checks a finalized configuration against the output formats
+// a renderer can encode.
+//
+// Validation rules:
+//   - Format must be one of formats ("jpeg" is accepted wherever "jpg" is)
+//   - Quality must be 1-100 for lossy formats (JPEG, AVIF, and WebP unless
+//     Lossless is set)
+//   - Lossless is only valid for WebP
+func validateFormat(cfg config.ImageConfig, formats ...string) error {
+	format := cfg.Format
+	if format == "jpeg" {
+		format = "jpg"
+	}
+
+	if !slices.Contains(formats, format) {
+		quoted := make([]string, len(formats))
+		for i, f := range formats {
+			quoted[i] = "'" + f + "'"
+		}
+		return fmt.Errorf("unsupported image format: %s (must be %s)", cfg.Format, strings.Join(quoted, ", "))
+	}
+
+	if cfg.Lossless && format != "webp" {
+		return fmt.Errorf("lossless encoding is only supported for webp, got %s", cfg.Format)
+	}
+
+	if IsLossy(cfg) && (cfg.Quality < 1 || cfg.Quality > 100) {
+		return fmt.Errorf("quality must be 1-100 for %s, got %d", cfg.Format, cfg.Quality)
+	}
+
+	return nil
+}
+
+// IsLossy reports whether the configured format is encoded lossily and
+// therefore uses Quality: JPEG, AVIF, and WebP unless Lossless is set.
+func IsLossy(cfg config.ImageConfig) bool {
+	switch cfg.Format {
+	case "jpg", "jpeg", "avif":
+		return true
+	case "webp":
+		return !cfg.Lossless
+	default:
+		return false
+	}
+}
@@ -7,7 +7,11 @@
 // accept configuration and return interface types, hiding implementation details.
 package image
 
-import "github.com/JaimeStill/document-context/pkg/config"
+import (
+	"io"
+
+	"github.com/JaimeStill/document-context/pkg/config"
+)
 
 // Renderer defines the interface for rendering document pages to image files.
 //
@@ -63,3 +67,93 @@ type Renderer interface {
 	// produce the same parameter list in the same order to ensure cache key consistency.
 	Parameters() []string
 }
+
+// CheckableRenderer is implemented by renderers that delegate to an external
+// binary and can verify it ahead of the first Render.
+//
+// Renderers locate their binary lazily, so a missing installation otherwise
+// surfaces only when a page is rendered. Services can type-assert for this
+// interface at startup to fail fast:
+//
+//	if c, ok := renderer.(image.CheckableRenderer); ok {
+//	    if err := c.Check(); err != nil {
+//	        return fmt.Errorf("renderer unavailable: %w", err)
+//	    }
+//	}
+type CheckableRenderer interface {
+	Renderer
+
+	// Check locates the renderer's binary and verifies that it reports a
+	// recognizable version.
+	//
+	// Returns an error if the binary cannot be found or its version output
+	// cannot be parsed.
+	Check() error
+
+	// Version returns the version reported by the renderer's binary
+	// (e.g., "7.1.1-29" for ImageMagick, "24.02.0" for pdftoppm).
+	//
+	// Returns an error if the binary cannot be found or its version output
+	// cannot be parsed.
+	Version() (string, error)
+}
+
+// FitRenderer is implemented by renderers that can render a page at a DPI
+// other than the configured one, enabling fit-to-dimensions rendering (see
+// config.ImageConfig fit limits).
+//
+// The document layer supplies each page's size; the renderer computes the
+// effective DPI, accounting for its own transformations such as rotation, and
+// renders at it. Documents record the effective DPI in cache keys, so pages
+// of different sizes are cached independently.
+type FitRenderer interface {
+	Renderer
+
+	// FitDPI returns the DPI at which a page of the given size, in PDF points
+	// with page rotation applied, renders within the configured fit limits.
+	// Returns the configured DPI when no fit limit is set.
+	FitDPI(width, height float64) float64
+
+	// RenderAt renders like Render, but at the given DPI instead of the
+	// configured one.
+	RenderAt(inputPath string, pageNum int, outputPath string, dpi float64) error
+}
+
+// Encoding is the quality and DPI a page is rendered with.
+type Encoding struct {
+	// DPI is the render density in dots per inch.
+	DPI float64
+
+	// Quality is the lossy encoding quality (1-100). It does not affect
+	// lossless formats (PNG, lossless WebP).
+	Quality int
+}
+
+// EncodingRenderer is implemented by renderers that can render a page with a
+// quality and DPI other than the configured ones, enabling byte-budget
+// encoding (see config.ImageConfig MaxBytes).
+//
+// The document layer searches for the highest quality and resolution whose
+// output fits the budget, rendering each candidate through RenderWith.
+type EncodingRenderer interface {
+	Renderer
+
+	// RenderWith renders like Render, but with the quality and DPI of enc
+	// instead of the configured ones.
+	RenderWith(inputPath string, pageNum int, outputPath string, enc Encoding) error
+}
+
+// StreamRenderer is implemented by renderers that can write the encoded
+// image to an io.Writer instead of an output file, avoiding a temporary file
+// per render.
+//
+// Documents render through RenderTo when available, falling back to a
+// temporary output file for other renderers.
+type StreamRenderer interface {
+	Renderer
+
+	// RenderTo renders page pageNum of inputPath with the quality and DPI of
+	// enc and writes the encoded image to w. Output already written to w is
+	// not retracted when rendering fails.
+	RenderTo(inputPath string, pageNum int, w io.Writer, enc Encoding) error
+}
@@ -1,9 +1,15 @@
 package image
 
 import (
+	"bytes"
 	"fmt"
+	"io"
+	"math"
 	"os/exec"
+	"regexp"
+	"slices"
 	"strconv"
+	"strings"
 
 	"github.com/JaimeStill/document-context/pkg/config"
 )
@@ -13,11 +19,24 @@ import (
 // This internal type groups render parameters to simplify the buildImageMagickArgs
 // method signature and improve code organization.
 type renderState struct {
-	inputPath  string // Path to the input PDF file
-	pageNum    int    // Page number to render (1-indexed)
-	outputPath string // Path where the rendered image will be written
+	inputPath  string  // Path to the input PDF file
+	pageNum    int     // Page number to render (1-indexed)
+	outputPath string  // Path where the rendered image will be written
+	dpi        float64 // Render density (configured or fitted)
+	quality    int     // Lossy encoding quality (configured or budgeted)
 }
 
+// imagemagickBinaries are the executables tried, in order, when no binary is
+// configured: ImageMagick 7's "magick", then ImageMagick 6's "convert".
+var imagemagickBinaries = []string{"magick", "convert"}
+
+// imagemagickVersion extracts the version from "magick -version" output
+// (e.g., "Version: ImageMagick 7.1.1-29 Q16-HDRI x86_64 ...").
+var imagemagickVersion = regexp.MustCompile(`Version: ImageMagick (\S+)`)
+
+// imagemagickColorModes are the supported output color modes.
+var imagemagickColorModes = []string{"rgb", "grayscale", "bilevel", "palette"}
+
 // parseImageMagickConfig transforms generic ImageConfig.Options into typed ImageMagickConfig.
 //
 // This function performs the configuration composition pattern's transformation step,
@@ -28,7 +47,10 @@ type renderState struct {
 //  1. Extract "background" string (default: "white")
 //  2. Extract optional filter values from Options map
 //  3. Validate each filter value is within valid range
-//  4. Return typed ImageMagickConfig with parsed values
+//  4. Extract color mode options (see parseColorMode)
+//  5. Extract "binary" string (default: "", auto-detect)
+//  6. Extract "include_version" boolean (default: false)
+//  7. Return typed ImageMagickConfig with parsed values
 //
 // Filter validation ranges:
 //   - brightness: 0-200 (100 is neutral)
@@ -64,18 +86,99 @@ func parseImageMagickConfig(cfg config.ImageConfig) (*config.ImageMagickConfig,
 		return nil, err
 	}
 
-	return &config.ImageMagickConfig{
-		Config:     cfg,
-		Background: background,
-		Brightness: brightness,
-		Contrast:   contrast,
-		Saturation: saturation,
-		Rotation:   rotation,
-	}, nil
+	binary, err := config.ParseString(cfg.Options, "binary", "")
+	if err != nil {
+		return nil, err
+	}
+
+	includeVersion, err := config.ParseBool(cfg.Options, "include_version", false)
+	if err != nil {
+		return nil, err
+	}
+
+	imCfg := &config.ImageMagickConfig{
+		Config:         cfg,
+		Background:     background,
+		Brightness:     brightness,
+		Contrast:       contrast,
+		Saturation:     saturation,
+		Rotation:       rotation,
+		Binary:         binary,
+		IncludeVersion: includeVersion,
+	}
+
+	if err := parseColorMode(cfg.Options, imCfg); err != nil {
+		return nil, err
+	}
+
+	return imCfg, nil
+}
+
+// parseColorMode extracts the color mode options into imCfg.
+//
+// Options:
+//   - "color_mode": "rgb" (default), "grayscale", "bilevel", or "palette"
+//   - "threshold": 0-100 percent, bilevel only (default: 50)
+//   - "dither": boolean, bilevel and palette only (default: false)
+//   - "colors": 2-256, palette only (default: 16)
+//
+// Options that do not apply to the selected mode are rejected rather than
+// ignored, as is "threshold" when bilevel output is dithered.
+func parseColorMode(options map[string]any, imCfg *config.ImageMagickConfig) error {
+	defaults := config.DefaultImageMagickConfig()
+
+	mode, err := config.ParseString(options, "color_mode", defaults.ColorMode)
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(imagemagickColorModes, mode) {
+		return fmt.Errorf("color_mode must be one of %s, got %s", strings.Join(imagemagickColorModes, ", "), mode)
+	}
+
+	threshold, err := config.ParseNilIntRanged(options, "threshold", 0, 100)
+	if err != nil {
+		return err
+	}
+
+	colors, err := config.ParseNilIntRanged(options, "colors", 2, 256)
+	if err != nil {
+		return err
+	}
+
+	dither, err := config.ParseBool(options, "dither", defaults.Dither)
+	if err != nil {
+		return err
+	}
+	_, ditherSet := options["dither"]
+
+	switch {
+	case threshold != nil && mode != "bilevel":
+		return fmt.Errorf("threshold requires color_mode bilevel, got %s", mode)
+	case threshold != nil && dither:
+		return fmt.Errorf("threshold cannot be combined with dither")
+	case colors != nil && mode != "palette":
+		return fmt.Errorf("colors requires color_mode palette, got %s", mode)
+	case ditherSet && mode != "bilevel" && mode != "palette":
+		return fmt.Errorf("dither requires color_mode bilevel or palette, got %s", mode)
+	}
+
+	imCfg.ColorMode = mode
+	imCfg.Dither = dither
+	imCfg.Threshold = defaults.Threshold
+	if threshold != nil {
+		imCfg.Threshold = *threshold
+	}
+	imCfg.Colors = defaults.Colors
+	if colors != nil {
+		imCfg.Colors = *colors
+	}
+
+	return nil
 }
 
 type imagemagickRenderer struct {
 	settings config.ImageMagickConfig
+	version  string // Resolved at creation when IncludeVersion is set
 }
 
 // NewImageMagickRenderer creates a new Renderer using ImageMagick for rendering.
@@ -85,26 +188,28 @@ type imagemagickRenderer struct {
 // ensuring that invalid configurations are rejected before creating domain objects.
 //
 // Configuration is finalized (defaults applied) and then validated:
-//   - Format must be "png" or "jpg"
-//   - Quality must be 1-100 for JPEG format
+//   - Format must be "png", "jpg", "webp", or "avif"
+//   - Quality must be 1-100 for lossy formats (JPEG, AVIF, lossy WebP)
+//   - Lossless is only valid for WebP
 //   - Brightness, Contrast, Saturation must be -100 to +100 if set
 //   - Rotation must be 0 to 360 degrees if set
 //
-// The returned Renderer is safe for concurrent use and will use ImageMagick's
-// 'magick' command for rendering operations.
+// The returned Renderer is safe for concurrent use and implements
+// CheckableRenderer and StreamRenderer. It renders with the "binary" option
+// when set, otherwise with ImageMagick 7's 'magick' command, falling back to
+// ImageMagick 6's 'convert'. The binary is located when it is first needed,
+// so a missing installation surfaces from Check or Render rather than here.
 //
-// Returns an error if configuration validation fails or if ImageMagick is not installed.
+// When the "include_version" option is true, the ImageMagick version is
+// resolved immediately so it can be included in Parameters.
+//
+// Returns an error if configuration validation fails, or if include_version
+// is set and the ImageMagick version cannot be determined.
 func NewImageMagickRenderer(cfg config.ImageConfig) (Renderer, error) {
 	cfg.Finalize()
 
-	if cfg.Format != "png" && cfg.Format != "jpg" && cfg.Format != "jpeg" {
-		return nil, fmt.Errorf("unsupported image format: %s (must be 'png' or 'jpg')", cfg.Format)
-	}
-
-	if cfg.Format == "jpg" {
-		if cfg.Quality < 1 || cfg.Quality > 100 {
-			return nil, fmt.Errorf("JPEG quality must be 1-100, got %d", cfg.Quality)
-		}
+	if err := validateFormat(cfg, "png", "jpg", "webp", "avif"); err != nil {
+		return nil, err
 	}
 
 	imCfg, err := parseImageMagickConfig(cfg)
@@ -112,21 +217,48 @@ func NewImageMagickRenderer(cfg config.ImageConfig) (Renderer, error) {
 		return nil, fmt.Errorf("invalid ImageMagick options: %w", err)
 	}
 
-	return &imagemagickRenderer{
+	r := &imagemagickRenderer{
 		settings: *imCfg,
-	}, nil
+	}
+
+	if r.settings.IncludeVersion {
+		if r.version, err = r.Version(); err != nil {
+			return nil, fmt.Errorf("failed to resolve ImageMagick version: %w", err)
+		}
+	}
+
+	return r, nil
 }
 
 func (r *imagemagickRenderer) Render(inputPath string, pageNum int, outputPath string) error {
+	return r.RenderAt(inputPath, pageNum, outputPath, float64(r.settings.Config.DPI))
+}
+
+// RenderAt renders like Render at the given DPI, which becomes the
+// -density argument.
+func (r *imagemagickRenderer) RenderAt(inputPath string, pageNum int, outputPath string, dpi float64) error {
+	return r.RenderWith(inputPath, pageNum, outputPath, Encoding{DPI: dpi, Quality: r.settings.Config.Quality})
+}
+
+// RenderWith renders like Render with the DPI and quality of enc, which
+// become the -density and -quality arguments.
+func (r *imagemagickRenderer) RenderWith(inputPath string, pageNum int, outputPath string, enc Encoding) error {
+	binary, err := r.binary()
+	if err != nil {
+		return err
+	}
+
 	state := renderState{
 		inputPath:  inputPath,
 		pageNum:    pageNum,
 		outputPath: outputPath,
+		dpi:        enc.DPI,
+		quality:    enc.Quality,
 	}
 
 	args := r.buildImageMagickArgs(state)
 
-	cmd := exec.Command("magick", args...)
+	cmd := exec.Command(binary, args...)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		return fmt.Errorf("imagemagick failed: %w\nOutput: %s", err, string(output))
@@ -135,6 +267,47 @@ func (r *imagemagickRenderer) Render(inputPath string, pageNum int, outputPath s
 	return nil
 }
 
+// RenderTo renders like RenderWith, writing the image to w through
+// ImageMagick's standard output (e.g., "png:-") instead of an output file.
+func (r *imagemagickRenderer) RenderTo(inputPath string, pageNum int, w io.Writer, enc Encoding) error {
+	binary, err := r.binary()
+	if err != nil {
+		return err
+	}
+
+	state := renderState{
+		inputPath:  inputPath,
+		pageNum:    pageNum,
+		outputPath: r.settings.Config.Format + ":-",
+		dpi:        enc.DPI,
+		quality:    enc.Quality,
+	}
+
+	args := r.buildImageMagickArgs(state)
+
+	var stderr bytes.Buffer
+	cmd := exec.Command(binary, args...)
+	cmd.Stdout = w
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("imagemagick failed: %w\nOutput: %s", err, stderr.String())
+	}
+
+	return nil
+}
+
+// FitDPI returns the DPI at which a page of the given size fits the
+// configured limits once the rotation filter is applied: the limits are
+// matched against the bounding box of the rotated page.
+func (r *imagemagickRenderer) FitDPI(width, height float64) float64 {
+	if r.settings.Rotation != nil && *r.settings.Rotation%180 != 0 {
+		rad := float64(*r.settings.Rotation) * math.Pi / 180
+		sin, cos := math.Abs(math.Sin(rad)), math.Abs(math.Cos(rad))
+		width, height = width*cos+height*sin, width*sin+height*cos
+	}
+	return FitDPI(r.settings.Config, width, height)
+}
+
 func (r *imagemagickRenderer) FileExtension() string {
 	return r.settings.Config.Format
 }
@@ -153,9 +326,17 @@ func (r *imagemagickRenderer) Settings() config.ImageConfig {
 // Parameters are returned in alphabetical order for consistency:
 //  1. background (always included)
 //  2. brightness (if set)
-//  3. contrast (if set)
-//  4. rotation (if set)
-//  5. saturation (if set)
+//  3. color_mode (if not rgb)
+//  4. colors (palette mode)
+//  5. contrast (if set)
+//  6. dither (bilevel and palette modes)
+//  7. rotation (if set)
+//  8. saturation (if set)
+//  9. threshold (bilevel mode without dither)
+//  10. version (if include_version is set)
+//
+// RGB output adds no color parameters, so cache keys from before color modes
+// were introduced remain valid.
 //
 // Format: Each parameter is formatted as "key=value"
 //
@@ -169,10 +350,23 @@ func (r *imagemagickRenderer) Parameters() []string {
 		params = append(params, fmt.Sprintf("brightness=%d", *r.settings.Brightness))
 	}
 
+	mode := r.settings.ColorMode
+	if mode != "" && mode != "rgb" {
+		params = append(params, fmt.Sprintf("color_mode=%s", mode))
+	}
+
+	if mode == "palette" {
+		params = append(params, fmt.Sprintf("colors=%d", r.settings.Colors))
+	}
+
 	if r.settings.Contrast != nil {
 		params = append(params, fmt.Sprintf("contrast=%d", *r.settings.Contrast))
 	}
 
+	if mode == "bilevel" || mode == "palette" {
+		params = append(params, fmt.Sprintf("dither=%t", r.settings.Dither))
+	}
+
 	if r.settings.Rotation != nil {
 		params = append(params, fmt.Sprintf("rotation=%d", *r.settings.Rotation))
 	}
@@ -181,9 +375,55 @@ func (r *imagemagickRenderer) Parameters() []string {
 		params = append(params, fmt.Sprintf("saturation=%d", *r.settings.Saturation))
 	}
 
+	if mode == "bilevel" && !r.settings.Dither {
+		params = append(params, fmt.Sprintf("threshold=%d", r.settings.Threshold))
+	}
+
+	if r.settings.IncludeVersion {
+		params = append(params, fmt.Sprintf("version=%s", r.version))
+	}
+
 	return params
 }
 
+// Check verifies that the ImageMagick binary can be located and reports a
+// recognizable version.
+func (r *imagemagickRenderer) Check() error {
+	_, err := r.Version()
+	return err
+}
+
+// Version runs "<binary> -version" and returns the ImageMagick version
+// (e.g., "7.1.1-29").
+func (r *imagemagickRenderer) Version() (string, error) {
+	binary, err := r.binary()
+	if err != nil {
+		return "", err
+	}
+	return binaryVersion(binary, "-version", imagemagickVersion)
+}
+
+// binary locates the ImageMagick executable.
+//
+// A configured binary is resolved as-is; otherwise "magick" is preferred and
+// "convert" is used for ImageMagick 6 installations.
+func (r *imagemagickRenderer) binary() (string, error) {
+	if r.settings.Binary != "" {
+		path, err := exec.LookPath(r.settings.Binary)
+		if err != nil {
+			return "", fmt.Errorf("imagemagick binary %s not found: %w", r.settings.Binary, err)
+		}
+		return path, nil
+	}
+
+	for _, name := range imagemagickBinaries {
+		if path, err := exec.LookPath(name); err == nil {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("imagemagick not found (install ImageMagick providing 'magick' or 'convert'): %w", exec.ErrNotFound)
+}
+
 // buildImageMagickArgs constructs the ImageMagick command-line arguments for rendering.
 //
 // This method builds the complete argument list for the ImageMagick 'magick' command,
@@ -195,22 +435,28 @@ func (r *imagemagickRenderer) Parameters() []string {
 //  2. Input specification: path[pageIndex]
 //  3. Operations after input: -background, -flatten
 //  4. Filters (applied sequentially): -rotate, -modulate, -brightness-contrast
-//  5. Output settings: -quality (for JPEG only)
-//  6. Output path
+//  5. Color mode reduction (see colorModeArgs)
+//  6. Output settings: -quality (lossy formats), -define webp:lossless=true
+//  7. Output path (its extension selects the encoder), or "<format>:-" to
+//     write to standard output
 //
 // Filter optimization:
 //   - Rotation: Applied only if set and non-zero
 //   - Modulate: Applied only if brightness or saturation differ from neutral (100)
 //   - Brightness-contrast: Applied only if contrast is set and non-zero
-//   - Quality: Applied only for JPEG format
+//   - Quality: Applied only for lossy formats (JPEG, AVIF, lossy WebP)
+//   - Lossless: Applied only for WebP when Lossless is set
+//
+// The same arguments are accepted by ImageMagick 7's 'magick' and
+// ImageMagick 6's 'convert'.
 //
-// Returns a string slice ready for exec.Command("magick", args...).
+// Returns a string slice ready for exec.Command(binary, args...).
 func (r *imagemagickRenderer) buildImageMagickArgs(state renderState) []string {
 	pageIndex := state.pageNum - 1
 	inputSpec := fmt.Sprintf("%s[%d]", state.inputPath, pageIndex)
 
 	args := []string{
-		"-density", strconv.Itoa(r.settings.Config.DPI),
+		"-density", formatDPI(state.dpi),
 		inputSpec,
 		"-background", r.settings.Background,
 		"-flatten",
@@ -244,11 +490,54 @@ func (r *imagemagickRenderer) buildImageMagickArgs(state renderState) []string {
 		args = append(args, "-brightness-contrast", contrast)
 	}
 
-	if r.settings.Config.Format == "jpg" || r.settings.Config.Format == "jpeg" {
-		args = append(args, "-quality", strconv.Itoa(r.settings.Config.Quality))
+	args = append(args, r.colorModeArgs()...)
+
+	if IsLossy(r.settings.Config) {
+		args = append(args, "-quality", strconv.Itoa(state.quality))
+	}
+
+	if r.settings.Config.Lossless {
+		args = append(args, "-define", "webp:lossless=true")
 	}
 
 	args = append(args, state.outputPath)
 
 	return args
 }
+
+// colorModeArgs returns the ImageMagick arguments that reduce the rendered
+// page to the configured color mode. Reduction runs after the filters so
+// brightness and contrast adjustments influence the threshold and palette.
+//
+// Modes:
+//   - rgb: no arguments
+//   - grayscale: -colorspace Gray
+//   - bilevel: -colorspace Gray -threshold N% -type Bilevel, or with dither,
+//     -colorspace Gray -dither FloydSteinberg -monochrome
+//   - palette: -dither FloydSteinberg|+dither -colors N
+func (r *imagemagickRenderer) colorModeArgs() []string {
+	switch r.settings.ColorMode {
+	case "grayscale":
+		return []string{"-colorspace", "Gray"}
+	case "bilevel":
+		if r.settings.Dither {
+			return []string{"-colorspace", "Gray", "-dither", "FloydSteinberg", "-monochrome"}
+		}
+		return []string{
+			"-colorspace", "Gray",
+			"-threshold", fmt.Sprintf("%d%%", r.settings.Threshold),
+			"-type", "Bilevel",
+		}
+	case "palette":
+		if r.settings.Dither {
+			return []string{"-dither", "FloydSteinberg", "-colors", strconv.Itoa(r.settings.Colors)}
+		}
+		return []string{"+dither", "-colors", strconv.Itoa(r.settings.Colors)}
+	default:
+		return nil
+	}
+}
+
+func init() {
+	Register("imagemagick", NewImageMagickRenderer)
+}
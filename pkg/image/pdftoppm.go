@@ -0,0 +1,282 @@
+package image
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/JaimeStill/document-context/pkg/config"
+)
+
+// pdftoppmUnsupportedOptions are ImageMagick filter and color mode options
+// that pdftoppm cannot apply. They are rejected rather than ignored so a
+// configuration never silently renders differently than requested.
+var pdftoppmUnsupportedOptions = []string{
+	"background", "brightness", "color_mode", "colors", "contrast",
+	"dither", "rotation", "saturation", "threshold",
+}
+
+// pdftoppmVersion extracts the version from "pdftoppm -v" output
+// (e.g., "pdftoppm version 24.02.0").
+var pdftoppmVersion = regexp.MustCompile(`pdftoppm version (\S+)`)
+
+// parsePdftoppmConfig transforms generic ImageConfig.Options into typed
+// PdftoppmConfig.
+//
+// Parsing process:
+//  1. Reject ImageMagick filter and color mode options pdftoppm cannot apply
+//  2. Extract "antialias" boolean (default: true)
+//  3. Extract "cropbox" boolean (default: false)
+//  4. Extract "binary" string (default: "pdftoppm")
+//  5. Extract "include_version" boolean (default: false)
+//
+// Returns an error if an unsupported option is present or an option value has
+// the wrong type.
+func parsePdftoppmConfig(cfg config.ImageConfig) (*config.PdftoppmConfig, error) {
+	for _, key := range pdftoppmUnsupportedOptions {
+		if _, ok := cfg.Options[key]; ok {
+			return nil, fmt.Errorf("%s is not supported by pdftoppm", key)
+		}
+	}
+
+	antialias, err := config.ParseBool(cfg.Options, "antialias", true)
+	if err != nil {
+		return nil, err
+	}
+
+	cropBox, err := config.ParseBool(cfg.Options, "cropbox", false)
+	if err != nil {
+		return nil, err
+	}
+
+	binary, err := config.ParseString(cfg.Options, "binary", "pdftoppm")
+	if err != nil {
+		return nil, err
+	}
+
+	includeVersion, err := config.ParseBool(cfg.Options, "include_version", false)
+	if err != nil {
+		return nil, err
+	}
+
+	return &config.PdftoppmConfig{
+		Config:         cfg,
+		Antialias:      antialias,
+		CropBox:        cropBox,
+		Binary:         binary,
+		IncludeVersion: includeVersion,
+	}, nil
+}
+
+type pdftoppmRenderer struct {
+	settings config.PdftoppmConfig
+	version  string // Resolved at creation when IncludeVersion is set
+}
+
+// NewPdftoppmRenderer creates a new Renderer using Poppler's pdftoppm for
+// rendering.
+//
+// pdftoppm rasterizes PDF pages directly with Poppler, avoiding ImageMagick's
+// Ghostscript delegate, which is slow and often disabled by security policy.
+//
+// Configuration is finalized (defaults applied) and then validated:
+//   - Format must be "png" or "jpg" (pdftoppm cannot encode WebP or AVIF)
+//   - Quality must be 1-100 for JPEG format
+//   - Options may set "antialias", "cropbox", and "include_version"
+//     (booleans) and "binary" (string); the ImageMagick filter and color
+//     mode options are rejected
+//
+// The returned Renderer implements CheckableRenderer. The binary is not
+// located until Check or Render is called, so a missing Poppler installation
+// surfaces there, unless include_version is set, in which case the Poppler
+// version is resolved immediately so it can be included in Parameters.
+//
+// Returns an error if configuration validation fails, or if include_version
+// is set and the pdftoppm version cannot be determined.
+func NewPdftoppmRenderer(cfg config.ImageConfig) (Renderer, error) {
+	cfg.Finalize()
+
+	if err := validateFormat(cfg, "png", "jpg"); err != nil {
+		return nil, err
+	}
+
+	pCfg, err := parsePdftoppmConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pdftoppm options: %w", err)
+	}
+
+	r := &pdftoppmRenderer{
+		settings: *pCfg,
+	}
+
+	if r.settings.IncludeVersion {
+		if r.version, err = r.Version(); err != nil {
+			return nil, fmt.Errorf("failed to resolve pdftoppm version: %w", err)
+		}
+	}
+
+	return r, nil
+}
+
+// Render rasterizes one page with pdftoppm.
+//
+// pdftoppm appends its own extension to the output root, so the image is
+// written next to outputPath and renamed when the extensions differ (e.g.,
+// ".jpeg" requested, ".jpg" written).
+//
+// Command: pdftoppm -f <page> -l <page> -singlefile -r <dpi> <format flags>
+// <input> <output root>
+func (r *pdftoppmRenderer) Render(inputPath string, pageNum int, outputPath string) error {
+	return r.RenderAt(inputPath, pageNum, outputPath, float64(r.settings.Config.DPI))
+}
+
+// RenderAt renders like Render at the given DPI, which becomes the -r
+// argument.
+func (r *pdftoppmRenderer) RenderAt(inputPath string, pageNum int, outputPath string, dpi float64) error {
+	return r.RenderWith(inputPath, pageNum, outputPath, Encoding{DPI: dpi, Quality: r.settings.Config.Quality})
+}
+
+// RenderWith renders like Render with the DPI and quality of enc, which
+// become the -r argument and, for JPEG, the -jpegopt quality.
+func (r *pdftoppmRenderer) RenderWith(inputPath string, pageNum int, outputPath string, enc Encoding) error {
+	binary, err := r.binary()
+	if err != nil {
+		return err
+	}
+
+	written := strings.TrimSuffix(outputPath, r.extension()) + r.extension()
+	root := strings.TrimSuffix(written, r.extension())
+
+	args := r.buildPdftoppmArgs(renderState{
+		inputPath:  inputPath,
+		pageNum:    pageNum,
+		outputPath: root,
+		dpi:        enc.DPI,
+		quality:    enc.Quality,
+	})
+
+	cmd := exec.Command(binary, args...)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("pdftoppm failed: %w\nOutput: %s", err, string(output))
+	}
+
+	if written != outputPath {
+		if err := os.Rename(written, outputPath); err != nil {
+			return fmt.Errorf("failed to move rendered image: %w", err)
+		}
+	}
+	return nil
+}
+
+// FitDPI returns the DPI at which a page of the given size fits the
+// configured limits. Documents supply the media box size, so with the
+// cropbox option the output stays within the limits but may not reach them.
+func (r *pdftoppmRenderer) FitDPI(width, height float64) float64 {
+	return FitDPI(r.settings.Config, width, height)
+}
+
+func (r *pdftoppmRenderer) FileExtension() string {
+	return r.settings.Config.Format
+}
+
+func (r *pdftoppmRenderer) Settings() config.ImageConfig {
+	return r.settings.Config
+}
+
+// Parameters returns pdftoppm-specific rendering parameters for cache key
+// generation.
+//
+// The renderer name is included so pages rendered by pdftoppm and
+// ImageMagick with otherwise identical settings never share cache entries.
+//
+// Parameters are returned in alphabetical order for consistency:
+//  1. antialias
+//  2. cropbox
+//  3. renderer
+//  4. version (if include_version is set)
+//
+// Example output: ["antialias=true", "cropbox=false", "renderer=pdftoppm"]
+func (r *pdftoppmRenderer) Parameters() []string {
+	params := []string{
+		fmt.Sprintf("antialias=%t", r.settings.Antialias),
+		fmt.Sprintf("cropbox=%t", r.settings.CropBox),
+		"renderer=pdftoppm",
+	}
+
+	if r.settings.IncludeVersion {
+		params = append(params, fmt.Sprintf("version=%s", r.version))
+	}
+
+	return params
+}
+
+// Check verifies that the pdftoppm binary can be located and reports a
+// recognizable version.
+func (r *pdftoppmRenderer) Check() error {
+	_, err := r.Version()
+	return err
+}
+
+// Version runs "pdftoppm -v" and returns the Poppler version
+// (e.g., "24.02.0").
+func (r *pdftoppmRenderer) Version() (string, error) {
+	binary, err := r.binary()
+	if err != nil {
+		return "", err
+	}
+	return binaryVersion(binary, "-v", pdftoppmVersion)
+}
+
+// binary locates the configured pdftoppm executable.
+func (r *pdftoppmRenderer) binary() (string, error) {
+	path, err := exec.LookPath(r.settings.Binary)
+	if err != nil {
+		return "", fmt.Errorf("%s not found (install poppler-utils): %w", r.settings.Binary, err)
+	}
+	return path, nil
+}
+
+// extension returns the file extension pdftoppm appends for the format.
+func (r *pdftoppmRenderer) extension() string {
+	if r.settings.Config.Format == "png" {
+		return ".png"
+	}
+	return ".jpg"
+}
+
+// buildPdftoppmArgs constructs the pdftoppm command-line arguments for
+// rendering a single page to state.outputPath, used as the output root.
+func (r *pdftoppmRenderer) buildPdftoppmArgs(state renderState) []string {
+	page := strconv.Itoa(state.pageNum)
+
+	args := []string{
+		"-f", page,
+		"-l", page,
+		"-singlefile",
+		"-r", formatDPI(state.dpi),
+	}
+
+	if r.settings.Config.Format == "png" {
+		args = append(args, "-png")
+	} else {
+		args = append(args, "-jpeg", "-jpegopt", fmt.Sprintf("quality=%d", state.quality))
+	}
+
+	if !r.settings.Antialias {
+		args = append(args, "-aa", "no", "-aaVector", "no")
+	}
+
+	if r.settings.CropBox {
+		args = append(args, "-cropbox")
+	}
+
+	return append(args, state.inputPath, state.outputPath)
+}
+
+func init() {
+	Register("pdftoppm", NewPdftoppmRenderer)
+}
@@ -0,0 +1,107 @@
+package image
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/JaimeStill/document-context/pkg/config"
+)
+
+// DefaultRenderer is the renderer created by Create when
+// ImageConfig.Renderer is empty.
+const DefaultRenderer = "imagemagick"
+
+// Factory is a function that creates a Renderer instance from configuration.
+//
+// Renderer implementations register their factory functions using Register.
+// The factory receives the ImageConfig, including implementation-specific
+// Options, and returns a configured Renderer or an error if the configuration
+// is invalid. Transformation functions such as NewImageMagickRenderer have
+// this signature.
+type Factory func(cfg config.ImageConfig) (Renderer, error)
+
+type registry struct {
+	factories map[string]Factory
+	mu        sync.RWMutex
+}
+
+var register = &registry{
+	factories: make(map[string]Factory),
+}
+
+// Register registers a renderer factory function under the given name.
+//
+// Renderer implementations should call Register in their init() functions to
+// make themselves available for creation via Create. If a factory is
+// registered multiple times with the same name, the latest registration
+// silently overwrites the previous one.
+//
+// Register panics if name is empty or factory is nil.
+//
+// Example:
+//
+//	func init() {
+//	    image.Register("mupdf", NewMuPDFRenderer)
+//	}
+func Register(name string, factory Factory) {
+	if name == "" {
+		panic("image: Register name is empty")
+	}
+	if factory == nil {
+		panic("image: Register factory is nil")
+	}
+
+	register.mu.Lock()
+	defer register.mu.Unlock()
+	register.factories[name] = factory
+}
+
+// Create instantiates a Renderer using the registered factory for the given
+// configuration.
+//
+// The configuration's Renderer field selects the implementation; an empty
+// name selects DefaultRenderer. Create returns an error if the name is
+// unknown, or if the factory function returns an error during renderer
+// creation.
+//
+// Example:
+//
+//	renderer, err := image.Create(config.ImageConfig{
+//	    Renderer: "pdftoppm",
+//	    Format:   "png",
+//	    DPI:      150,
+//	})
+func Create(cfg config.ImageConfig) (Renderer, error) {
+	name := cfg.Renderer
+	if name == "" {
+		name = DefaultRenderer
+	}
+
+	register.mu.RLock()
+	factory, ok := register.factories[name]
+	register.mu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unknown renderer: %s", name)
+	}
+
+	return factory(cfg)
+}
+
+// ListRenderers returns the names of all registered renderer implementations
+// in alphabetical order.
+//
+// This function is useful for discovering available renderers and for
+// validation or help text generation.
+func ListRenderers() []string {
+	register.mu.RLock()
+	defer register.mu.RUnlock()
+
+	names := make([]string, 0, len(register.factories))
+	for name := range register.factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
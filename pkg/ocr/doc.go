@@ -0,0 +1,35 @@
+// Package ocr provides interfaces and implementations for recognizing text in rendered pages.
+//
+// This package defines the OCREngine interface which abstracts optical character
+// recognition, allowing different engines to be used interchangeably. The Tesseract
+// implementation invokes the tesseract command-line tool, following the library's
+// strategy of leveraging proven external binaries rather than reimplementing them.
+//
+// # Recognition Results
+//
+// Engines return a Result containing the recognized text along with word-level
+// bounding boxes and confidence scores. Boxes are in pixel coordinates of the
+// recognized image, with the origin at the top-left corner.
+//
+// # Page Recognition and Caching
+//
+// RecognizePage renders a page through Page.ToImage and runs an engine over the
+// rendered image. Results are cached as JSON under keys derived from the page's
+// rendered image key combined with the engine parameters, so a change to either
+// the rendering settings or the OCR settings produces a new cache entry.
+//
+// Example usage:
+//
+//	engine, err := ocr.NewTesseractEngine(config.OCRConfig{
+//	    Languages: []string{"eng", "deu"},
+//	})
+//	if err != nil {
+//	    return err
+//	}
+//
+//	result, err := ocr.RecognizePage(page, renderer, engine, cache)
+//	if err != nil {
+//	    return err
+//	}
+//	fmt.Println(result.Text)
+package ocr
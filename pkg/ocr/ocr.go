@@ -0,0 +1,184 @@
+package ocr
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/JaimeStill/document-context/pkg/cache"
+	"github.com/JaimeStill/document-context/pkg/config"
+	"github.com/JaimeStill/document-context/pkg/document"
+	"github.com/JaimeStill/document-context/pkg/image"
+)
+
+// Box is a rectangle in image pixel coordinates with the origin at the
+// top-left corner.
+type Box struct {
+	Left   int `json:"left"`
+	Top    int `json:"top"`
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+// Word is a single recognized word with its location and confidence.
+type Word struct {
+	// Text is the recognized word.
+	Text string `json:"text"`
+
+	// Box is the word bounding box in image pixels.
+	Box Box `json:"box"`
+
+	// Confidence is the engine's confidence in the recognition (0-100).
+	Confidence float64 `json:"confidence"`
+
+	// Block, Paragraph, and Line identify the layout elements containing the
+	// word, numbered in reading order as reported by the engine.
+	Block     int `json:"block"`
+	Paragraph int `json:"paragraph"`
+	Line      int `json:"line"`
+}
+
+// Result contains the text recognized in an image.
+type Result struct {
+	// Text is the recognized text. Words on a line are separated by spaces,
+	// lines by newlines, and paragraphs by blank lines.
+	Text string `json:"text"`
+
+	// Words lists recognized words in reading order.
+	Words []Word `json:"words"`
+
+	// Confidence is the mean word confidence (0-100), or 0 if no words were
+	// recognized.
+	Confidence float64 `json:"confidence"`
+}
+
+// OCREngine defines the interface for recognizing text in images.
+//
+// Engine instances are immutable once created and safe for concurrent use.
+type OCREngine interface {
+	// Recognize extracts text from encoded image data (PNG, JPEG, etc.).
+	//
+	// Returns an error if the image cannot be processed or the external
+	// recognition tool is not available.
+	Recognize(data []byte) (*Result, error)
+
+	// Settings returns the engine's immutable configuration.
+	Settings() config.OCRConfig
+
+	// Parameters returns engine settings that affect recognition output, for
+	// cache key generation.
+	//
+	// Format: Returns a slice of "key=value" strings in deterministic order.
+	//
+	// Example (Tesseract): ["engine=tesseract", "languages=eng+deu", "psm=3"]
+	Parameters() []string
+}
+
+// imageKeyer is implemented by pages that expose the cache key of their
+// rendered image.
+type imageKeyer interface {
+	ImageCacheKey(renderer image.Renderer) (string, error)
+}
+
+// RecognizePage renders a page and recognizes its text.
+//
+// This function supports optional caching in the same way as Page.ToImage. The
+// page image is obtained through page.ToImage(renderer, c), so the rendered image
+// is cached as well. Recognition results are cached as JSON.
+//
+// Parameters:
+//   - page: The page to recognize
+//   - renderer: Image renderer used to render the page
+//   - engine: OCR engine used to recognize the rendered image
+//   - c: Optional cache for images and results. Pass nil to disable caching.
+//
+// The result cache key combines the page's image cache key with the engine
+// parameters:
+//
+//	<image key>/ocr?engine=tesseract&languages=eng&psm=3
+//
+// Pages that do not expose an image cache key (see PDFPage.ImageCacheKey) use a
+// SHA-256 hash of the rendered image instead.
+//
+// Returns the recognition result, or an error if rendering, recognition, or
+// caching fails.
+func RecognizePage(page document.Page, renderer image.Renderer, engine OCREngine, c cache.Cache) (*Result, error) {
+	var key string
+
+	if c != nil {
+		if keyer, ok := page.(imageKeyer); ok {
+			imageKey, err := keyer.ImageCacheKey(renderer)
+			if err != nil {
+				return nil, err
+			}
+			key = resultKey(imageKey, engine)
+
+			if result, err := cachedResult(c, key); result != nil || err != nil {
+				return result, err
+			}
+		}
+	}
+
+	data, err := page.ToImage(renderer, c)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render page %d: %w", page.Number(), err)
+	}
+
+	if c != nil && key == "" {
+		sum := sha256.Sum256(data)
+		key = resultKey("sha256:"+hex.EncodeToString(sum[:]), engine)
+
+		if result, err := cachedResult(c, key); result != nil || err != nil {
+			return result, err
+		}
+	}
+
+	result, err := engine.Recognize(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to recognize page %d: %w", page.Number(), err)
+	}
+
+	if c != nil {
+		encoded, err := json.Marshal(result)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode OCR result: %w", err)
+		}
+
+		entry := &cache.CacheEntry{
+			Key:      key,
+			Data:     encoded,
+			Filename: fmt.Sprintf("page-%d.ocr.json", page.Number()),
+		}
+
+		if err := c.Set(entry); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+func resultKey(imageKey string, engine OCREngine) string {
+	return cache.GenerateKey(fmt.Sprintf("%s/ocr?%s", imageKey, strings.Join(engine.Parameters(), "&")))
+}
+
+// cachedResult returns the cached result for key, or nil with no error on a
+// cache miss.
+func cachedResult(c cache.Cache, key string) (*Result, error) {
+	entry, err := c.Get(key)
+	if err != nil {
+		if errors.Is(err, cache.ErrCacheEntryNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var result Result
+	if err := json.Unmarshal(entry.Data, &result); err != nil {
+		return nil, fmt.Errorf("failed to decode cached OCR result: %w", err)
+	}
+	return &result, nil
+}
@@ -0,0 +1,212 @@
+package ocr
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/JaimeStill/document-context/pkg/config"
+)
+
+// languagePattern matches Tesseract language model names (e.g., "eng",
+// "chi_sim", "script/Latin").
+var languagePattern = regexp.MustCompile(`^[A-Za-z0-9_/-]+$`)
+
+type tesseractEngine struct {
+	settings config.OCRConfig
+}
+
+// NewTesseractEngine creates a new OCREngine using the Tesseract command-line tool.
+//
+// This transformation function validates the provided configuration and creates
+// an immutable engine instance. Configuration is finalized (defaults applied) and
+// then validated:
+//   - Languages must be valid model names (letters, digits, "_", "-", "/")
+//   - PageSegMode must be 1-13
+//
+// The binary is not executed until Recognize is called, so a missing Tesseract
+// installation surfaces as a recognition error.
+//
+// Returns an error if configuration validation fails.
+func NewTesseractEngine(cfg config.OCRConfig) (OCREngine, error) {
+	cfg.Finalize()
+
+	for _, lang := range cfg.Languages {
+		if !languagePattern.MatchString(lang) {
+			return nil, fmt.Errorf("invalid OCR language: %q", lang)
+		}
+	}
+
+	if cfg.PageSegMode < 1 || cfg.PageSegMode > 13 {
+		return nil, fmt.Errorf("page segmentation mode must be 1-13, got %d", cfg.PageSegMode)
+	}
+
+	return &tesseractEngine{settings: cfg}, nil
+}
+
+// Recognize writes the image to a temporary file and runs Tesseract over it,
+// requesting TSV output so word boxes and confidences are available.
+//
+// Command: <binary> <image> stdout -l <languages> --psm <mode> tsv
+func (e *tesseractEngine) Recognize(data []byte) (*Result, error) {
+	tmpFile, err := os.CreateTemp("", "ocr-*.img")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp file: %w", err)
+	}
+	tmpPath := tmpFile.Name()
+	defer os.Remove(tmpPath)
+
+	_, err = tmpFile.Write(data)
+	tmpFile.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to write image: %w", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(e.settings.Binary, e.buildArgs(tmpPath)...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("tesseract failed: %w\nOutput: %s", err, stderr.String())
+	}
+
+	return parseTSV(stdout.Bytes())
+}
+
+func (e *tesseractEngine) Settings() config.OCRConfig {
+	return e.settings
+}
+
+// Parameters returns Tesseract settings that affect recognition output.
+//
+// The binary path is excluded: the same engine version installed at different
+// paths produces the same output.
+//
+// Example output: ["engine=tesseract", "languages=eng+deu", "psm=3"]
+func (e *tesseractEngine) Parameters() []string {
+	return []string{
+		"engine=tesseract",
+		fmt.Sprintf("languages=%s", strings.Join(e.settings.Languages, "+")),
+		fmt.Sprintf("psm=%d", e.settings.PageSegMode),
+	}
+}
+
+func (e *tesseractEngine) buildArgs(imagePath string) []string {
+	return []string{
+		imagePath,
+		"stdout",
+		"-l", strings.Join(e.settings.Languages, "+"),
+		"--psm", strconv.Itoa(e.settings.PageSegMode),
+		"tsv",
+	}
+}
+
+// tsvWordLevel is the Tesseract TSV level for word rows.
+const tsvWordLevel = 5
+
+// parseTSV converts Tesseract TSV output into a Result.
+//
+// TSV columns: level, page_num, block_num, par_num, line_num, word_num, left,
+// top, width, height, conf, text. Only word rows with text are kept; lines and
+// paragraphs are reconstructed from the block, paragraph, and line numbers.
+func parseTSV(data []byte) (*Result, error) {
+	result := &Result{Words: []Word{}}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	header := true
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if header {
+			header = false
+			if strings.HasPrefix(line, "level") {
+				continue
+			}
+		}
+		if line == "" {
+			continue
+		}
+
+		fields := strings.Split(line, "\t")
+		if len(fields) < 11 {
+			return nil, fmt.Errorf("malformed tesseract output: %q", line)
+		}
+
+		values := make([]int, 10)
+		for i := range values {
+			v, err := strconv.Atoi(fields[i])
+			if err != nil {
+				return nil, fmt.Errorf("malformed tesseract output: %q", line)
+			}
+			values[i] = v
+		}
+
+		if values[0] != tsvWordLevel || len(fields) < 12 {
+			continue
+		}
+
+		text := strings.TrimSpace(fields[11])
+		if text == "" {
+			continue
+		}
+
+		confidence, err := strconv.ParseFloat(fields[10], 64)
+		if err != nil {
+			return nil, fmt.Errorf("malformed tesseract confidence: %q", fields[10])
+		}
+
+		result.Words = append(result.Words, Word{
+			Text:       text,
+			Box:        Box{Left: values[6], Top: values[7], Width: values[8], Height: values[9]},
+			Confidence: confidence,
+			Block:      values[2],
+			Paragraph:  values[3],
+			Line:       values[4],
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read tesseract output: %w", err)
+	}
+
+	result.Text = joinWords(result.Words)
+
+	if len(result.Words) > 0 {
+		var total float64
+		for _, w := range result.Words {
+			total += w.Confidence
+		}
+		result.Confidence = total / float64(len(result.Words))
+	}
+
+	return result, nil
+}
+
+// joinWords assembles words into text, separating words on a line with spaces,
+// lines with newlines, and paragraphs or blocks with blank lines.
+func joinWords(words []Word) string {
+	var builder strings.Builder
+
+	for i, w := range words {
+		if i > 0 {
+			prev := words[i-1]
+			switch {
+			case w.Block != prev.Block || w.Paragraph != prev.Paragraph:
+				builder.WriteString("\n\n")
+			case w.Line != prev.Line:
+				builder.WriteString("\n")
+			default:
+				builder.WriteString(" ")
+			}
+		}
+		builder.WriteString(w.Text)
+	}
+
+	return builder.String()
+}
@@ -0,0 +1,106 @@
+package config_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/JaimeStill/document-context/pkg/config"
+)
+
+func TestDefaultArchiveConfig(t *testing.T) {
+	cfg := config.DefaultArchiveConfig()
+
+	if cfg.MaxEntries != 1000 {
+		t.Errorf("expected MaxEntries 1000, got %d", cfg.MaxEntries)
+	}
+	if cfg.MaxTotalSize != 1<<30 {
+		t.Errorf("expected MaxTotalSize 1 GiB, got %d", cfg.MaxTotalSize)
+	}
+	if cfg.MaxDepth != 3 {
+		t.Errorf("expected MaxDepth 3, got %d", cfg.MaxDepth)
+	}
+}
+
+func TestArchiveConfig_Merge(t *testing.T) {
+	tests := []struct {
+		name         string
+		source       *config.ArchiveConfig
+		maxEntries   int
+		maxTotalSize int64
+		maxDepth     int
+	}{
+		{
+			name:         "override all fields",
+			source:       &config.ArchiveConfig{MaxEntries: 10, MaxTotalSize: 4096, MaxDepth: 1},
+			maxEntries:   10,
+			maxTotalSize: 4096,
+			maxDepth:     1,
+		},
+		{
+			name:         "empty source preserves base",
+			source:       &config.ArchiveConfig{},
+			maxEntries:   1000,
+			maxTotalSize: 1 << 30,
+			maxDepth:     3,
+		},
+		{
+			name:         "negative values ignored",
+			source:       &config.ArchiveConfig{MaxEntries: -1, MaxTotalSize: -1, MaxDepth: -1},
+			maxEntries:   1000,
+			maxTotalSize: 1 << 30,
+			maxDepth:     3,
+		},
+		{
+			name:         "nil source",
+			source:       nil,
+			maxEntries:   1000,
+			maxTotalSize: 1 << 30,
+			maxDepth:     3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.DefaultArchiveConfig()
+			cfg.Merge(tt.source)
+
+			if cfg.MaxEntries != tt.maxEntries {
+				t.Errorf("expected MaxEntries %d, got %d", tt.maxEntries, cfg.MaxEntries)
+			}
+			if cfg.MaxTotalSize != tt.maxTotalSize {
+				t.Errorf("expected MaxTotalSize %d, got %d", tt.maxTotalSize, cfg.MaxTotalSize)
+			}
+			if cfg.MaxDepth != tt.maxDepth {
+				t.Errorf("expected MaxDepth %d, got %d", tt.maxDepth, cfg.MaxDepth)
+			}
+		})
+	}
+}
+
+func TestArchiveConfig_Finalize(t *testing.T) {
+	cfg := config.ArchiveConfig{MaxDepth: 1}
+	cfg.Finalize()
+
+	if cfg.MaxDepth != 1 {
+		t.Errorf("expected MaxDepth 1, got %d", cfg.MaxDepth)
+	}
+	if cfg.MaxEntries != 1000 {
+		t.Errorf("expected MaxEntries 1000, got %d", cfg.MaxEntries)
+	}
+	if cfg.MaxTotalSize != 1<<30 {
+		t.Errorf("expected MaxTotalSize 1 GiB, got %d", cfg.MaxTotalSize)
+	}
+}
+
+func TestArchiveConfig_JSON(t *testing.T) {
+	data := []byte(`{"max_entries": 50, "max_total_size": 1048576, "max_depth": 2}`)
+
+	var cfg config.ArchiveConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+
+	if cfg.MaxEntries != 50 || cfg.MaxTotalSize != 1048576 || cfg.MaxDepth != 2 {
+		t.Errorf("unexpected config %+v", cfg)
+	}
+}
@@ -0,0 +1,120 @@
+package config_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/JaimeStill/document-context/pkg/config"
+)
+
+func TestDefaultDocumentConfig(t *testing.T) {
+	cfg := config.DefaultDocumentConfig()
+
+	if cfg.Fingerprint != config.FingerprintContent {
+		t.Errorf("expected default fingerprint %q, got %q", config.FingerprintContent, cfg.Fingerprint)
+	}
+
+	if cfg.Validation != config.ValidationRelaxed {
+		t.Errorf("expected default validation %q, got %q", config.ValidationRelaxed, cfg.Validation)
+	}
+
+	if cfg.Repair {
+		t.Error("expected repair to be disabled by default")
+	}
+}
+
+func TestDocumentConfig_Merge(t *testing.T) {
+	tests := []struct {
+		name     string
+		base     config.DocumentConfig
+		source   *config.DocumentConfig
+		expected config.DocumentConfig
+	}{
+		{
+			name:   "merge fingerprint",
+			base:   config.DefaultDocumentConfig(),
+			source: &config.DocumentConfig{Fingerprint: config.FingerprintFast},
+			expected: config.DocumentConfig{
+				Fingerprint: config.FingerprintFast,
+				Validation:  config.ValidationRelaxed,
+			},
+		},
+		{
+			name:     "ignore empty fingerprint",
+			base:     config.DocumentConfig{Fingerprint: config.FingerprintFast},
+			source:   &config.DocumentConfig{},
+			expected: config.DocumentConfig{Fingerprint: config.FingerprintFast},
+		},
+		{
+			name:   "merge validation and repair",
+			base:   config.DefaultDocumentConfig(),
+			source: &config.DocumentConfig{Validation: config.ValidationStrict, Repair: true},
+			expected: config.DocumentConfig{
+				Fingerprint: config.FingerprintContent,
+				Validation:  config.ValidationStrict,
+				Repair:      true,
+			},
+		},
+		{
+			name:   "false repair does not override",
+			base:   config.DocumentConfig{Repair: true},
+			source: &config.DocumentConfig{Repair: false},
+			expected: config.DocumentConfig{
+				Repair: true,
+			},
+		},
+		{
+			name:     "nil source",
+			base:     config.DocumentConfig{Fingerprint: config.FingerprintFast},
+			source:   nil,
+			expected: config.DocumentConfig{Fingerprint: config.FingerprintFast},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.base.Merge(tt.source)
+
+			if tt.base != tt.expected {
+				t.Errorf("expected %+v, got %+v", tt.expected, tt.base)
+			}
+		})
+	}
+}
+
+func TestDocumentConfig_Finalize(t *testing.T) {
+	cfg := config.DocumentConfig{}
+	cfg.Finalize()
+
+	if cfg.Fingerprint != config.FingerprintContent {
+		t.Errorf("expected fingerprint %q, got %q", config.FingerprintContent, cfg.Fingerprint)
+	}
+
+	cfg = config.DocumentConfig{Fingerprint: config.FingerprintFast}
+	cfg.Finalize()
+
+	if cfg.Fingerprint != config.FingerprintFast {
+		t.Errorf("expected fingerprint %q preserved, got %q", config.FingerprintFast, cfg.Fingerprint)
+	}
+}
+
+func TestDocumentConfig_JSON(t *testing.T) {
+	data := []byte(`{"fingerprint": "fast", "validation": "strict", "repair": true}`)
+
+	var cfg config.DocumentConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+
+	if cfg.Fingerprint != config.FingerprintFast {
+		t.Errorf("expected fingerprint %q, got %q", config.FingerprintFast, cfg.Fingerprint)
+	}
+
+	if cfg.Validation != config.ValidationStrict {
+		t.Errorf("expected validation %q, got %q", config.ValidationStrict, cfg.Validation)
+	}
+
+	if !cfg.Repair {
+		t.Error("expected repair to be enabled")
+	}
+}
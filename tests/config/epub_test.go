@@ -0,0 +1,60 @@
+package config_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/JaimeStill/document-context/pkg/config"
+)
+
+func TestDefaultEPUBConfig(t *testing.T) {
+	cfg := config.DefaultEPUBConfig()
+
+	if cfg.MaxChars != 3000 {
+		t.Errorf("expected MaxChars 3000, got %d", cfg.MaxChars)
+	}
+}
+
+func TestEPUBConfig_Merge(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   *config.EPUBConfig
+		maxChars int
+	}{
+		{"override", &config.EPUBConfig{MaxChars: 500}, 500},
+		{"empty source preserves base", &config.EPUBConfig{}, 3000},
+		{"negative ignored", &config.EPUBConfig{MaxChars: -1}, 3000},
+		{"nil source", nil, 3000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.DefaultEPUBConfig()
+			cfg.Merge(tt.source)
+
+			if cfg.MaxChars != tt.maxChars {
+				t.Errorf("expected MaxChars %d, got %d", tt.maxChars, cfg.MaxChars)
+			}
+		})
+	}
+}
+
+func TestEPUBConfig_Finalize(t *testing.T) {
+	var cfg config.EPUBConfig
+	cfg.Finalize()
+
+	if cfg.MaxChars != 3000 {
+		t.Errorf("expected MaxChars 3000, got %d", cfg.MaxChars)
+	}
+}
+
+func TestEPUBConfig_JSON(t *testing.T) {
+	var cfg config.EPUBConfig
+	if err := json.Unmarshal([]byte(`{"max_chars": 1200}`), &cfg); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+
+	if cfg.MaxChars != 1200 {
+		t.Errorf("expected MaxChars 1200, got %d", cfg.MaxChars)
+	}
+}
@@ -0,0 +1,129 @@
+package config_test
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/JaimeStill/document-context/pkg/config"
+)
+
+func TestDefaultHTMLConfig(t *testing.T) {
+	cfg := config.DefaultHTMLConfig()
+
+	if cfg.Converter != "wkhtmltopdf" {
+		t.Errorf("expected Converter 'wkhtmltopdf', got %q", cfg.Converter)
+	}
+	if !slices.Equal(cfg.Args, []string{"--quiet", "--disable-local-file-access", "--disable-javascript", "{input}", "{output}"}) {
+		t.Errorf("expected default Args, got %v", cfg.Args)
+	}
+	if cfg.TextOnly {
+		t.Error("expected TextOnly false")
+	}
+}
+
+func TestDefaultHTMLConfig_RestrictsUntrustedHTML(t *testing.T) {
+	cfg := config.HTMLConfig{}
+	cfg.Finalize()
+
+	for _, flag := range []string{"--disable-local-file-access", "--disable-javascript"} {
+		if !slices.Contains(cfg.Args, flag) {
+			t.Errorf("expected default Args to contain %s, got %v", flag, cfg.Args)
+		}
+	}
+}
+
+func TestHTMLConfig_Merge(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   *config.HTMLConfig
+		expected config.HTMLConfig
+	}{
+		{
+			name: "override all fields",
+			source: &config.HTMLConfig{
+				Converter: "chromium",
+				Args:      []string{"--headless", "--print-to-pdf={output}", "{input}"},
+				TextOnly:  true,
+			},
+			expected: config.HTMLConfig{
+				Converter: "chromium",
+				Args:      []string{"--headless", "--print-to-pdf={output}", "{input}"},
+				TextOnly:  true,
+			},
+		},
+		{
+			name:     "empty source preserves base",
+			source:   &config.HTMLConfig{},
+			expected: config.DefaultHTMLConfig(),
+		},
+		{
+			name:     "nil source",
+			source:   nil,
+			expected: config.DefaultHTMLConfig(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.DefaultHTMLConfig()
+			cfg.Merge(tt.source)
+
+			if cfg.Converter != tt.expected.Converter {
+				t.Errorf("expected Converter %q, got %q", tt.expected.Converter, cfg.Converter)
+			}
+			if !slices.Equal(cfg.Args, tt.expected.Args) {
+				t.Errorf("expected Args %v, got %v", tt.expected.Args, cfg.Args)
+			}
+			if cfg.TextOnly != tt.expected.TextOnly {
+				t.Errorf("expected TextOnly %v, got %v", tt.expected.TextOnly, cfg.TextOnly)
+			}
+		})
+	}
+}
+
+func TestHTMLConfig_Merge_CopiesArgs(t *testing.T) {
+	args := []string{"{input}", "{output}"}
+	cfg := config.DefaultHTMLConfig()
+	cfg.Merge(&config.HTMLConfig{Args: args})
+
+	args[0] = "changed"
+
+	if cfg.Args[0] != "{input}" {
+		t.Errorf("expected merged args to be independent of source, got %v", cfg.Args)
+	}
+}
+
+func TestHTMLConfig_Finalize(t *testing.T) {
+	cfg := config.HTMLConfig{TextOnly: true}
+	cfg.Finalize()
+
+	if cfg.Converter != "wkhtmltopdf" {
+		t.Errorf("expected Converter 'wkhtmltopdf', got %q", cfg.Converter)
+	}
+	if len(cfg.Args) != 5 {
+		t.Errorf("expected default Args, got %v", cfg.Args)
+	}
+	if !cfg.TextOnly {
+		t.Error("expected TextOnly to be preserved")
+	}
+}
+
+func TestHTMLConfig_JSON(t *testing.T) {
+	data := []byte(`{"converter": "/usr/bin/chromium", "args": ["--headless", "--print-to-pdf={output}", "{input}"], "text_only": true}`)
+
+	var cfg config.HTMLConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+
+	if cfg.Converter != "/usr/bin/chromium" {
+		t.Errorf("expected Converter '/usr/bin/chromium', got %q", cfg.Converter)
+	}
+	if len(cfg.Args) != 3 || cfg.Args[1] != "--print-to-pdf={output}" {
+		t.Errorf("unexpected Args %v", cfg.Args)
+	}
+	if !cfg.TextOnly {
+		t.Error("expected TextOnly true")
+	}
+}
@@ -97,12 +97,73 @@ func TestImageConfig_Merge_BaseFields(t *testing.T) {
 				Quality: 85,
 			},
 		},
+		{
+			name: "merge lossless",
+			base: config.ImageConfig{
+				Format: "webp",
+			},
+			source: config.ImageConfig{
+				Lossless: true,
+			},
+			expected: config.ImageConfig{
+				Format:   "webp",
+				Lossless: true,
+			},
+		},
+		{
+			name: "false lossless does not override",
+			base: config.ImageConfig{
+				Format:   "webp",
+				Lossless: true,
+			},
+			source: config.ImageConfig{
+				Format: "webp",
+			},
+			expected: config.ImageConfig{
+				Format:   "webp",
+				Lossless: true,
+			},
+		},
+		{
+			name: "merge renderer",
+			base: config.DefaultImageConfig(),
+			source: config.ImageConfig{
+				Renderer: "pdftoppm",
+			},
+			expected: config.ImageConfig{
+				Renderer: "pdftoppm",
+				Format:   "png",
+				DPI:      300,
+			},
+		},
+		{
+			name: "ignore empty renderer",
+			base: config.ImageConfig{
+				Renderer: "pdftoppm",
+				Format:   "png",
+			},
+			source: config.ImageConfig{
+				DPI: 150,
+			},
+			expected: config.ImageConfig{
+				Renderer: "pdftoppm",
+				Format:   "png",
+				DPI:      150,
+			},
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.base.Merge(&tt.source)
 
+			if tt.base.Lossless != tt.expected.Lossless {
+				t.Errorf("Lossless: expected %t, got %t", tt.expected.Lossless, tt.base.Lossless)
+			}
+			if tt.base.Renderer != tt.expected.Renderer {
+				t.Errorf("Renderer: expected %q, got %q", tt.expected.Renderer, tt.base.Renderer)
+			}
+
 			if tt.base.Format != tt.expected.Format {
 				t.Errorf("Format: expected %q, got %q", tt.expected.Format, tt.base.Format)
 			}
@@ -116,6 +177,47 @@ func TestImageConfig_Merge_BaseFields(t *testing.T) {
 	}
 }
 
+func TestImageConfig_Merge_FitLimits(t *testing.T) {
+	base := config.ImageConfig{MaxWidth: 1000, MaxLongEdge: 2000}
+	base.Merge(&config.ImageConfig{
+		MaxHeight:     800,
+		MaxLongEdge:   1568,
+		MaxMegapixels: 1.15,
+	})
+
+	if base.MaxWidth != 1000 {
+		t.Errorf("MaxWidth: expected 1000 preserved, got %d", base.MaxWidth)
+	}
+	if base.MaxHeight != 800 {
+		t.Errorf("MaxHeight: expected 800, got %d", base.MaxHeight)
+	}
+	if base.MaxLongEdge != 1568 {
+		t.Errorf("MaxLongEdge: expected 1568, got %d", base.MaxLongEdge)
+	}
+	if base.MaxMegapixels != 1.15 {
+		t.Errorf("MaxMegapixels: expected 1.15, got %g", base.MaxMegapixels)
+	}
+
+	base.Merge(&config.ImageConfig{MaxWidth: -1, MaxMegapixels: 0})
+	if base.MaxWidth != 1000 || base.MaxMegapixels != 1.15 {
+		t.Errorf("expected non-positive limits to be ignored, got %+v", base)
+	}
+}
+
+func TestImageConfig_Merge_MaxBytes(t *testing.T) {
+	base := config.ImageConfig{MaxBytes: 5 << 20}
+
+	base.Merge(&config.ImageConfig{MaxBytes: 0})
+	if base.MaxBytes != 5<<20 {
+		t.Errorf("MaxBytes: expected %d preserved, got %d", 5<<20, base.MaxBytes)
+	}
+
+	base.Merge(&config.ImageConfig{MaxBytes: 1 << 20})
+	if base.MaxBytes != 1<<20 {
+		t.Errorf("MaxBytes: expected %d, got %d", 1<<20, base.MaxBytes)
+	}
+}
+
 func TestImageConfig_Merge_Options(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -364,6 +466,36 @@ func TestImageConfig_JSON_Unmarshal(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "with fit limits",
+			json: `{"max_width":1200,"max_height":1600,"max_long_edge":1568,"max_megapixels":1.15}`,
+			checkFn: func(t *testing.T, cfg config.ImageConfig) {
+				if cfg.MaxWidth != 1200 || cfg.MaxHeight != 1600 || cfg.MaxLongEdge != 1568 || cfg.MaxMegapixels != 1.15 {
+					t.Errorf("unexpected fit limits: %+v", cfg)
+				}
+			},
+		},
+		{
+			name: "with byte budget",
+			json: `{"format":"jpg","max_bytes":5242880}`,
+			checkFn: func(t *testing.T, cfg config.ImageConfig) {
+				if cfg.MaxBytes != 5242880 {
+					t.Errorf("expected MaxBytes 5242880, got %d", cfg.MaxBytes)
+				}
+			},
+		},
+		{
+			name: "with renderer",
+			json: `{"renderer":"pdftoppm","format":"png"}`,
+			checkFn: func(t *testing.T, cfg config.ImageConfig) {
+				if cfg.Renderer != "pdftoppm" {
+					t.Errorf("expected Renderer 'pdftoppm', got %q", cfg.Renderer)
+				}
+				if cfg.Format != "png" {
+					t.Errorf("expected Format 'png', got %q", cfg.Format)
+				}
+			},
+		},
 		{
 			name: "empty JSON object",
 			json: `{}`,
@@ -0,0 +1,117 @@
+package config_test
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/JaimeStill/document-context/pkg/config"
+)
+
+func TestDefaultOCRConfig(t *testing.T) {
+	cfg := config.DefaultOCRConfig()
+
+	if cfg.Binary != "tesseract" {
+		t.Errorf("expected Binary 'tesseract', got %q", cfg.Binary)
+	}
+	if !slices.Equal(cfg.Languages, []string{"eng"}) {
+		t.Errorf("expected Languages [eng], got %v", cfg.Languages)
+	}
+	if cfg.PageSegMode != 3 {
+		t.Errorf("expected PageSegMode 3, got %d", cfg.PageSegMode)
+	}
+}
+
+func TestOCRConfig_Merge(t *testing.T) {
+	tests := []struct {
+		name     string
+		base     config.OCRConfig
+		source   *config.OCRConfig
+		expected config.OCRConfig
+	}{
+		{
+			name:   "override all fields",
+			base:   config.DefaultOCRConfig(),
+			source: &config.OCRConfig{Binary: "/opt/tesseract", Languages: []string{"deu", "eng"}, PageSegMode: 6},
+			expected: config.OCRConfig{
+				Binary:      "/opt/tesseract",
+				Languages:   []string{"deu", "eng"},
+				PageSegMode: 6,
+			},
+		},
+		{
+			name:     "empty source preserves base",
+			base:     config.DefaultOCRConfig(),
+			source:   &config.OCRConfig{},
+			expected: config.DefaultOCRConfig(),
+		},
+		{
+			name:     "nil source",
+			base:     config.DefaultOCRConfig(),
+			source:   nil,
+			expected: config.DefaultOCRConfig(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.base.Merge(tt.source)
+
+			if tt.base.Binary != tt.expected.Binary {
+				t.Errorf("expected Binary %q, got %q", tt.expected.Binary, tt.base.Binary)
+			}
+			if !slices.Equal(tt.base.Languages, tt.expected.Languages) {
+				t.Errorf("expected Languages %v, got %v", tt.expected.Languages, tt.base.Languages)
+			}
+			if tt.base.PageSegMode != tt.expected.PageSegMode {
+				t.Errorf("expected PageSegMode %d, got %d", tt.expected.PageSegMode, tt.base.PageSegMode)
+			}
+		})
+	}
+}
+
+func TestOCRConfig_Merge_CopiesLanguages(t *testing.T) {
+	languages := []string{"fra"}
+	cfg := config.DefaultOCRConfig()
+	cfg.Merge(&config.OCRConfig{Languages: languages})
+
+	languages[0] = "spa"
+
+	if cfg.Languages[0] != "fra" {
+		t.Errorf("expected merged languages to be independent of source, got %v", cfg.Languages)
+	}
+}
+
+func TestOCRConfig_Finalize(t *testing.T) {
+	cfg := config.OCRConfig{Languages: []string{"jpn"}}
+	cfg.Finalize()
+
+	if cfg.Binary != "tesseract" {
+		t.Errorf("expected Binary 'tesseract', got %q", cfg.Binary)
+	}
+	if !slices.Equal(cfg.Languages, []string{"jpn"}) {
+		t.Errorf("expected Languages [jpn], got %v", cfg.Languages)
+	}
+	if cfg.PageSegMode != 3 {
+		t.Errorf("expected PageSegMode 3, got %d", cfg.PageSegMode)
+	}
+}
+
+func TestOCRConfig_JSON(t *testing.T) {
+	data := []byte(`{"binary": "/usr/local/bin/tesseract", "languages": ["eng", "fra"], "psm": 6}`)
+
+	var cfg config.OCRConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+
+	if cfg.Binary != "/usr/local/bin/tesseract" {
+		t.Errorf("expected Binary '/usr/local/bin/tesseract', got %q", cfg.Binary)
+	}
+	if !slices.Equal(cfg.Languages, []string{"eng", "fra"}) {
+		t.Errorf("expected Languages [eng fra], got %v", cfg.Languages)
+	}
+	if cfg.PageSegMode != 6 {
+		t.Errorf("expected PageSegMode 6, got %d", cfg.PageSegMode)
+	}
+}
@@ -0,0 +1,121 @@
+package config_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/JaimeStill/document-context/pkg/config"
+)
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+func TestDefaultSpreadsheetConfig(t *testing.T) {
+	cfg := config.DefaultSpreadsheetConfig()
+
+	if cfg.MaxRows != 50 {
+		t.Errorf("expected MaxRows 50, got %d", cfg.MaxRows)
+	}
+	if cfg.MaxColumns != 20 {
+		t.Errorf("expected MaxColumns 20, got %d", cfg.MaxColumns)
+	}
+	if cfg.HeaderRow == nil || !*cfg.HeaderRow {
+		t.Errorf("expected HeaderRow true, got %v", cfg.HeaderRow)
+	}
+}
+
+func TestSpreadsheetConfig_Merge(t *testing.T) {
+	tests := []struct {
+		name       string
+		source     *config.SpreadsheetConfig
+		maxRows    int
+		maxColumns int
+		headerRow  bool
+	}{
+		{
+			name:       "override all fields",
+			source:     &config.SpreadsheetConfig{MaxRows: 10, MaxColumns: 5, HeaderRow: boolPtr(false)},
+			maxRows:    10,
+			maxColumns: 5,
+			headerRow:  false,
+		},
+		{
+			name:       "empty source preserves base",
+			source:     &config.SpreadsheetConfig{},
+			maxRows:    50,
+			maxColumns: 20,
+			headerRow:  true,
+		},
+		{
+			name:       "nil source",
+			source:     nil,
+			maxRows:    50,
+			maxColumns: 20,
+			headerRow:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.DefaultSpreadsheetConfig()
+			cfg.Merge(tt.source)
+
+			if cfg.MaxRows != tt.maxRows {
+				t.Errorf("expected MaxRows %d, got %d", tt.maxRows, cfg.MaxRows)
+			}
+			if cfg.MaxColumns != tt.maxColumns {
+				t.Errorf("expected MaxColumns %d, got %d", tt.maxColumns, cfg.MaxColumns)
+			}
+			if *cfg.HeaderRow != tt.headerRow {
+				t.Errorf("expected HeaderRow %v, got %v", tt.headerRow, *cfg.HeaderRow)
+			}
+		})
+	}
+}
+
+func TestSpreadsheetConfig_Merge_CopiesHeaderRow(t *testing.T) {
+	header := false
+	cfg := config.DefaultSpreadsheetConfig()
+	cfg.Merge(&config.SpreadsheetConfig{HeaderRow: &header})
+
+	header = true
+
+	if *cfg.HeaderRow {
+		t.Error("expected merged HeaderRow to be independent of source")
+	}
+}
+
+func TestSpreadsheetConfig_Finalize(t *testing.T) {
+	cfg := config.SpreadsheetConfig{MaxRows: 100}
+	cfg.Finalize()
+
+	if cfg.MaxRows != 100 {
+		t.Errorf("expected MaxRows 100, got %d", cfg.MaxRows)
+	}
+	if cfg.MaxColumns != 20 {
+		t.Errorf("expected MaxColumns 20, got %d", cfg.MaxColumns)
+	}
+	if cfg.HeaderRow == nil || !*cfg.HeaderRow {
+		t.Errorf("expected HeaderRow true, got %v", cfg.HeaderRow)
+	}
+}
+
+func TestSpreadsheetConfig_JSON(t *testing.T) {
+	data := []byte(`{"max_rows": 25, "max_columns": 8, "header_row": false}`)
+
+	var cfg config.SpreadsheetConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+
+	if cfg.MaxRows != 25 {
+		t.Errorf("expected MaxRows 25, got %d", cfg.MaxRows)
+	}
+	if cfg.MaxColumns != 8 {
+		t.Errorf("expected MaxColumns 8, got %d", cfg.MaxColumns)
+	}
+	if cfg.HeaderRow == nil || *cfg.HeaderRow {
+		t.Errorf("expected HeaderRow false, got %v", cfg.HeaderRow)
+	}
+}
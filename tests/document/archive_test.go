@@ -0,0 +1,418 @@
+package document_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/JaimeStill/document-context/pkg/config"
+	"github.com/JaimeStill/document-context/pkg/document"
+)
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", path, err)
+	}
+	return string(data)
+}
+
+// sampleArchive writes an archive holding a two-page PDF, an image, an
+// unsupported text file, macOS metadata, and a nested archive with a
+// one-page PDF.
+func sampleArchive(t *testing.T) string {
+	t.Helper()
+
+	inner := writeZip(t, "inner.zip", map[string]string{
+		"memo.pdf": readFile(t, writeContentPDF(t, showText(72, 700, 12, "Memo"))),
+	})
+
+	return writeZip(t, "bundle.zip", map[string]string{
+		"a/report.pdf": readFile(t, writeContentPDF(t,
+			showText(72, 700, 12, "Report one"),
+			showText(72, 700, 12, "Report two"),
+		)),
+		"b/scan.png":          readFile(t, writePNG(t, 4, 4)),
+		"c/notes.txt":         "plain notes",
+		"d/inner.zip":         readFile(t, inner),
+		"__MACOSX/._scan.png": "metadata",
+		"b/._scan.png":        "metadata",
+	})
+}
+
+func openArchive(t *testing.T, path string) *document.ArchiveDocument {
+	t.Helper()
+
+	doc, err := document.OpenZIP(path)
+	if err != nil {
+		t.Fatalf("OpenZIP failed: %v", err)
+	}
+	t.Cleanup(func() { doc.Close() })
+	return doc
+}
+
+func archivePage(t *testing.T, doc document.Document, n int) *document.ArchivePage {
+	t.Helper()
+
+	page, err := doc.ExtractPage(n)
+	if err != nil {
+		t.Fatalf("ExtractPage(%d) failed: %v", n, err)
+	}
+	archivePage, ok := page.(*document.ArchivePage)
+	if !ok {
+		t.Fatalf("expected *document.ArchivePage, got %T", page)
+	}
+	return archivePage
+}
+
+func TestOpenZIP_Entries(t *testing.T) {
+	doc := openArchive(t, sampleArchive(t))
+
+	entries, err := doc.Entries()
+	if err != nil {
+		t.Fatalf("Entries failed: %v", err)
+	}
+
+	want := []struct {
+		path        string
+		contentType string
+		opened      bool
+	}{
+		{"a/report.pdf", "application/pdf", true},
+		{"b/scan.png", "image/png", true},
+		{"c/notes.txt", "text/plain", false},
+		{"d/inner.zip", "application/zip", true},
+	}
+
+	if len(entries) != len(want) {
+		t.Fatalf("expected %d entries, got %d: %+v", len(want), len(entries), entries)
+	}
+
+	for i, w := range want {
+		entry := entries[i]
+		if entry.Path != w.path {
+			t.Errorf("entry %d: expected path %q, got %q", i, w.path, entry.Path)
+		}
+		if entry.ContentType != w.contentType {
+			t.Errorf("entry %s: expected content type %q, got %q", w.path, w.contentType, entry.ContentType)
+		}
+		if (entry.Document != nil) != w.opened {
+			t.Errorf("entry %s: expected opened %v, got error %v", w.path, w.opened, entry.Err)
+		}
+	}
+
+	if !errors.Is(entries[2].Err, document.ErrUnsupportedContentType) {
+		t.Errorf("expected ErrUnsupportedContentType for text entry, got %v", entries[2].Err)
+	}
+	if _, ok := entries[3].Document.(*document.ArchiveDocument); !ok {
+		t.Errorf("expected nested archive document, got %T", entries[3].Document)
+	}
+}
+
+func TestOpenZIP_FlattenedPages(t *testing.T) {
+	doc := openArchive(t, sampleArchive(t))
+
+	if doc.PageCount() != 4 {
+		t.Fatalf("expected 4 pages, got %d", doc.PageCount())
+	}
+
+	want := []struct {
+		entry string
+		page  int
+	}{
+		{"a/report.pdf", 1},
+		{"a/report.pdf", 2},
+		{"b/scan.png", 1},
+		{"d/inner.zip/memo.pdf", 1},
+	}
+
+	for i, w := range want {
+		page := archivePage(t, doc, i+1)
+		if page.Number() != i+1 {
+			t.Errorf("page %d: expected number %d, got %d", i+1, i+1, page.Number())
+		}
+		if page.Entry() != w.entry {
+			t.Errorf("page %d: expected entry %q, got %q", i+1, w.entry, page.Entry())
+		}
+		if page.EntryPage() != w.page {
+			t.Errorf("page %d: expected entry page %d, got %d", i+1, w.page, page.EntryPage())
+		}
+	}
+
+	source, ok := archivePage(t, doc, 2).Source().(document.TextPage)
+	if !ok {
+		t.Fatalf("expected PDF source page to implement TextPage")
+	}
+	text, err := source.Text()
+	if err != nil {
+		t.Fatalf("Text failed: %v", err)
+	}
+	if !strings.Contains(text, "Report two") {
+		t.Errorf("expected source text of second PDF page, got %q", text)
+	}
+
+	if _, ok := archivePage(t, doc, 3).Source().(*document.ImagePage); !ok {
+		t.Errorf("expected image source page, got %T", archivePage(t, doc, 3).Source())
+	}
+
+	pages, err := doc.ExtractAllPages()
+	if err != nil {
+		t.Fatalf("ExtractAllPages failed: %v", err)
+	}
+	if len(pages) != 4 {
+		t.Errorf("expected 4 pages, got %d", len(pages))
+	}
+
+	if _, err := doc.ExtractPage(5); err == nil {
+		t.Error("expected error for out of range page")
+	}
+}
+
+func TestArchivePage_ToImage(t *testing.T) {
+	doc := openArchive(t, sampleArchive(t))
+	renderer := newFakeRenderer()
+	c := newMockCache()
+
+	data, err := archivePage(t, doc, 2).ToImage(renderer, c)
+	if err != nil {
+		t.Fatalf("ToImage failed: %v", err)
+	}
+	if string(data) != "page-2" {
+		t.Errorf("expected second page of entry to be rendered, got %q", data)
+	}
+
+	if _, err := archivePage(t, doc, 2).ToImage(renderer, c); err != nil {
+		t.Fatalf("ToImage failed: %v", err)
+	}
+	if renderer.renderCount() != 1 {
+		t.Errorf("expected cached render, got %d renders", renderer.renderCount())
+	}
+}
+
+func TestOpenZIPWithConfig_Limits(t *testing.T) {
+	path := sampleArchive(t)
+
+	tests := []struct {
+		name string
+		cfg  config.ArchiveConfig
+	}{
+		{"max entries", config.ArchiveConfig{MaxEntries: 3}},
+		{"max total size", config.ArchiveConfig{MaxTotalSize: 64}},
+		{"max depth", config.ArchiveConfig{MaxDepth: 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := document.OpenZIPWithConfig(path, tt.cfg)
+			if err == nil {
+				doc.Close()
+				t.Fatal("expected limit error")
+			}
+			if !errors.Is(err, document.ErrArchiveLimit) {
+				t.Errorf("expected ErrArchiveLimit, got %v", err)
+			}
+		})
+	}
+
+	doc, err := document.OpenZIPWithConfig(path, config.ArchiveConfig{MaxEntries: 5, MaxDepth: 2})
+	if err != nil {
+		t.Fatalf("expected archive within limits to open, got %v", err)
+	}
+	doc.Close()
+}
+
+// zipEmail returns a message with a ZIP attachment holding a one-page PDF.
+func zipEmail(t *testing.T) string {
+	t.Helper()
+
+	attached := writeZip(t, "attached.zip", map[string]string{
+		"memo.pdf": readFile(t, writeContentPDF(t, showText(72, 700, 12, "Memo"))),
+	})
+
+	return `From: a@example.com
+Subject: Bundle
+MIME-Version: 1.0
+Content-Type: multipart/mixed; boundary="outer"
+
+--outer
+Content-Type: text/plain
+
+See attached.
+--outer
+Content-Type: application/zip
+Content-Disposition: attachment; filename="attached.zip"
+Content-Transfer-Encoding: base64
+
+` + base64File(t, attached) + `
+--outer--
+`
+}
+
+func TestOpenZIPWithConfig_LimitsApplyToAttachments(t *testing.T) {
+	path := writeZip(t, "mail.zip", map[string]string{"mail.eml": zipEmail(t)})
+
+	tests := []struct {
+		name string
+		cfg  config.ArchiveConfig
+	}{
+		{"max entries", config.ArchiveConfig{MaxEntries: 2}},
+		{"max total size", config.ArchiveConfig{MaxTotalSize: int64(len(zipEmail(t))) + 64}},
+		{"max depth", config.ArchiveConfig{MaxDepth: 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := document.OpenZIPWithConfig(path, tt.cfg)
+			if err != nil {
+				t.Fatalf("OpenZIPWithConfig failed: %v", err)
+			}
+			defer doc.Close()
+
+			entries, err := doc.Entries()
+			if err != nil {
+				t.Fatalf("Entries failed: %v", err)
+			}
+			email, ok := entries[0].Document.(*document.EmailDocument)
+			if !ok {
+				t.Fatalf("expected *document.EmailDocument, got %T (%v)", entries[0].Document, entries[0].Err)
+			}
+
+			if _, err := email.Attachments(); !errors.Is(err, document.ErrArchiveLimit) {
+				t.Errorf("expected ErrArchiveLimit, got %v", err)
+			}
+		})
+	}
+
+	doc, err := document.OpenZIPWithConfig(path, config.ArchiveConfig{MaxEntries: 3, MaxDepth: 2})
+	if err != nil {
+		t.Fatalf("OpenZIPWithConfig failed: %v", err)
+	}
+	defer doc.Close()
+
+	entries, err := doc.Entries()
+	if err != nil {
+		t.Fatalf("Entries failed: %v", err)
+	}
+	attachments, err := entries[0].Document.(*document.EmailDocument).Attachments()
+	if err != nil {
+		t.Fatalf("expected attachments within limits to open, got %v", err)
+	}
+	if _, ok := attachments[0].Document.(*document.ArchiveDocument); !ok {
+		t.Errorf("expected attached *document.ArchiveDocument, got %T (%v)", attachments[0].Document, attachments[0].Err)
+	}
+}
+
+func TestOpenZIP_Invalid(t *testing.T) {
+	if _, err := document.OpenZIP(testPDFPath(t)); err == nil {
+		t.Error("expected error opening a non-archive file")
+	}
+}
+
+func TestArchiveDocument_Close(t *testing.T) {
+	doc, err := document.OpenZIP(sampleArchive(t))
+	if err != nil {
+		t.Fatalf("OpenZIP failed: %v", err)
+	}
+
+	if err := doc.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if err := doc.Close(); err != nil {
+		t.Errorf("second Close failed: %v", err)
+	}
+
+	if _, err := doc.Entries(); err == nil {
+		t.Error("expected error listing entries of closed archive")
+	}
+	if _, err := doc.ExtractPage(1); err == nil {
+		t.Error("expected error extracting page of closed archive")
+	}
+}
+
+func TestOpen_ZIP(t *testing.T) {
+	doc, err := document.Open(sampleArchive(t), "application/zip")
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer doc.Close()
+
+	if _, ok := doc.(*document.ArchiveDocument); !ok {
+		t.Errorf("expected *document.ArchiveDocument, got %T", doc)
+	}
+}
+
+func TestArchivePage_ForwardsCapabilities(t *testing.T) {
+	path := writeZip(t, "scans.zip", map[string]string{
+		"cheatsheet.pdf": readFile(t, testPDFPath(t)),
+	})
+
+	doc, err := document.Open(path, "application/zip")
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer doc.Close()
+
+	page, err := doc.ExtractPage(1)
+	if err != nil {
+		t.Fatalf("ExtractPage failed: %v", err)
+	}
+	source := page.(*document.ArchivePage).Source().(*document.PDFPage)
+
+	renderPage, ok := page.(document.RenderPage)
+	if !ok {
+		t.Fatal("expected archive page to implement RenderPage")
+	}
+	renderer := &dataRenderer{fakeRenderer: newFakeRenderer(), data: encodePNG(t, 40, 30)}
+	result, err := renderPage.Render(renderer, newMockCache())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	key, err := source.ImageCacheKey(renderer)
+	if err != nil {
+		t.Fatalf("ImageCacheKey failed: %v", err)
+	}
+	if result.CacheKey != key || result.Width != 40 || result.Height != 30 {
+		t.Errorf("unexpected render result: key %q (want %q), %dx%d", result.CacheKey, key, result.Width, result.Height)
+	}
+
+	budgetPage, ok := page.(document.BudgetPage)
+	if !ok {
+		t.Fatal("expected archive page to implement BudgetPage")
+	}
+	img, err := budgetPage.ToBudgetImage(newFakeRenderer(), nil)
+	if err != nil {
+		t.Fatalf("ToBudgetImage failed: %v", err)
+	}
+	if string(img.Data) != "page-1" || img.Encoding.DPI != 150 {
+		t.Errorf("unexpected budget image %q at %v DPI", img.Data, img.Encoding.DPI)
+	}
+
+	streamPage, ok := page.(document.StreamPage)
+	if !ok {
+		t.Fatal("expected archive page to implement StreamPage")
+	}
+	streamer := &streamRenderer{fakeRenderer: newFakeRenderer()}
+	var buf bytes.Buffer
+	if err := streamPage.WriteImage(&buf, streamer, nil); err != nil {
+		t.Fatalf("WriteImage failed: %v", err)
+	}
+	if buf.String() != "page-1" || len(streamer.inputs) != 1 {
+		t.Errorf("expected the entry page to be streamed, got %q from %d streams", buf.String(), len(streamer.inputs))
+	}
+
+	textPage, ok := page.(document.TextPage)
+	if !ok {
+		t.Fatal("expected archive page to implement TextPage")
+	}
+	text, err := textPage.Text()
+	if err != nil {
+		t.Fatalf("Text failed: %v", err)
+	}
+	if want, _ := source.Text(); text != want || text == "" {
+		t.Errorf("expected entry page text, got %q", text)
+	}
+}
@@ -0,0 +1,125 @@
+package document_test
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/JaimeStill/document-context/pkg/document"
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+)
+
+// writeScannedPDF creates a single-page PDF containing only a full-page image.
+func writeScannedPDF(t *testing.T) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	imgs := []io.Reader{bytes.NewReader(stubImage(t, "scanned page"))}
+	if err := api.ImportImages(nil, &buf, imgs, nil, nil); err != nil {
+		t.Fatalf("Failed to create scanned PDF: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "scanned.pdf")
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatalf("Failed to write scanned PDF: %v", err)
+	}
+	return path
+}
+
+func TestPDFPage_Classify_Digital(t *testing.T) {
+	page := extractPDFPage(t, 1)
+
+	c, err := page.Classify()
+	if err != nil {
+		t.Fatalf("Classify failed: %v", err)
+	}
+
+	if c.Kind != document.PageDigital {
+		t.Errorf("Kind = %q, want %q", c.Kind, document.PageDigital)
+	}
+	if c.Page != 1 {
+		t.Errorf("Page = %d, want 1", c.Page)
+	}
+	if c.Images != 0 {
+		t.Errorf("Images = %d, want 0", c.Images)
+	}
+	if c.ImageCoverage != 0 {
+		t.Errorf("ImageCoverage = %f, want 0", c.ImageCoverage)
+	}
+	if c.TextOperators == 0 || c.Glyphs == 0 {
+		t.Errorf("expected text statistics, got %d operators and %d glyphs", c.TextOperators, c.Glyphs)
+	}
+	if c.InvisibleGlyphs != 0 {
+		t.Errorf("InvisibleGlyphs = %d, want 0", c.InvisibleGlyphs)
+	}
+	if len(c.Fonts) == 0 {
+		t.Error("expected fonts to be listed")
+	}
+}
+
+func TestPDFPage_Classify_Scanned(t *testing.T) {
+	doc, err := document.OpenPDF(writeScannedPDF(t))
+	if err != nil {
+		t.Fatalf("OpenPDF failed: %v", err)
+	}
+	defer doc.Close()
+
+	page, err := doc.ExtractPage(1)
+	if err != nil {
+		t.Fatalf("ExtractPage failed: %v", err)
+	}
+
+	c, err := page.(*document.PDFPage).Classify()
+	if err != nil {
+		t.Fatalf("Classify failed: %v", err)
+	}
+
+	if c.Kind != document.PageScanned {
+		t.Errorf("Kind = %q, want %q", c.Kind, document.PageScanned)
+	}
+	if c.Images != 1 {
+		t.Errorf("Images = %d, want 1", c.Images)
+	}
+	if c.ImageCoverage < 0.5 {
+		t.Errorf("ImageCoverage = %f, want >= 0.5", c.ImageCoverage)
+	}
+	if c.Glyphs != 0 {
+		t.Errorf("Glyphs = %d, want 0", c.Glyphs)
+	}
+}
+
+func TestClassifyPages(t *testing.T) {
+	doc, err := document.OpenPDF(testPDFPath(t))
+	if err != nil {
+		t.Fatalf("OpenPDF failed: %v", err)
+	}
+	defer doc.Close()
+
+	results, err := document.ClassifyPages(doc)
+	if err != nil {
+		t.Fatalf("ClassifyPages failed: %v", err)
+	}
+
+	if len(results) != doc.PageCount() {
+		t.Fatalf("got %d classifications, want %d", len(results), doc.PageCount())
+	}
+
+	for i, c := range results {
+		if c.Page != i+1 {
+			t.Errorf("results[%d].Page = %d, want %d", i, c.Page, i+1)
+		}
+		if c.Kind != document.PageDigital {
+			t.Errorf("page %d Kind = %q, want %q", c.Page, c.Kind, document.PageDigital)
+		}
+	}
+}
+
+func TestClassifyPages_Unsupported(t *testing.T) {
+	doc := newStubDocument(t, "alpha")
+
+	if _, err := document.ClassifyPages(doc); err == nil {
+		t.Error("expected error for pages without classification support")
+	}
+}
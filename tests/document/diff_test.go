@@ -0,0 +1,265 @@
+package document_test
+
+import (
+	"bytes"
+	"fmt"
+	goimage "image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/JaimeStill/document-context/pkg/cache"
+	"github.com/JaimeStill/document-context/pkg/document"
+	"github.com/JaimeStill/document-context/pkg/image"
+)
+
+// stubPage implements document.TextPage with fixed text and image data.
+type stubPage struct {
+	number int
+	text   string
+	image  []byte
+}
+
+func (p *stubPage) Number() int { return p.number }
+
+func (p *stubPage) Text() (string, error) { return p.text, nil }
+
+func (p *stubPage) ToImage(renderer image.Renderer, c cache.Cache) ([]byte, error) {
+	return p.image, nil
+}
+
+// stubDocument implements document.Document over a fixed set of stub pages.
+type stubDocument struct {
+	pages []*stubPage
+}
+
+func newStubDocument(t *testing.T, texts ...string) *stubDocument {
+	t.Helper()
+
+	doc := &stubDocument{}
+	for i, text := range texts {
+		doc.pages = append(doc.pages, &stubPage{
+			number: i + 1,
+			text:   text,
+			image:  stubImage(t, text),
+		})
+	}
+	return doc
+}
+
+func (d *stubDocument) PageCount() int { return len(d.pages) }
+
+func (d *stubDocument) Fingerprint() string { return "stub" }
+
+func (d *stubDocument) ExtractPage(pageNum int) (document.Page, error) {
+	if pageNum < 1 || pageNum > len(d.pages) {
+		return nil, fmt.Errorf("page %d out of range", pageNum)
+	}
+	return d.pages[pageNum-1], nil
+}
+
+func (d *stubDocument) ExtractAllPages() ([]document.Page, error) {
+	pages := make([]document.Page, len(d.pages))
+	for i, p := range d.pages {
+		pages[i] = p
+	}
+	return pages, nil
+}
+
+func (d *stubDocument) Close() error { return nil }
+
+// stubImage produces a 64x64 PNG whose dark bands are derived from text, so
+// different texts produce visibly different images.
+func stubImage(t *testing.T, text string) []byte {
+	t.Helper()
+
+	img := goimage.NewGray(goimage.Rect(0, 0, 64, 64))
+	for y := 0; y < 64; y++ {
+		for x := 0; x < 64; x++ {
+			img.SetGray(x, y, color.Gray{Y: 255})
+		}
+	}
+	for i, r := range text {
+		row := (int(r) * 7) % 64
+		col := (i * 5) % 64
+		for x := col; x < min(col+4, 64); x++ {
+			img.SetGray(x, row, color.Gray{Y: 0})
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("Failed to encode stub image: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func changes(c *document.Comparison) []document.PageChange {
+	out := make([]document.PageChange, len(c.Pages))
+	for i, p := range c.Pages {
+		out[i] = p.Change
+	}
+	return out
+}
+
+func TestCompare_Identical(t *testing.T) {
+	a := newStubDocument(t, "first page text", "second page text")
+	b := newStubDocument(t, "first page text", "second page text")
+
+	result, err := document.Compare(a, b, newFakeRenderer(), nil)
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+
+	if len(result.Pages) != 2 {
+		t.Fatalf("Expected 2 page diffs, got %d", len(result.Pages))
+	}
+
+	for _, p := range result.Pages {
+		if p.Change != document.PageUnchanged {
+			t.Errorf("Expected unchanged page, got %+v", p)
+		}
+		if p.HashDistance != 0 {
+			t.Errorf("Expected hash distance 0, got %d", p.HashDistance)
+		}
+	}
+
+	if len(result.Changed()) != 0 {
+		t.Errorf("Expected no changed pages, got %d", len(result.Changed()))
+	}
+}
+
+func TestCompare_AddedRemovedModified(t *testing.T) {
+	original := newStubDocument(t,
+		"terms and conditions of the agreement between parties",
+		"payment schedule due within thirty days of invoice",
+		"termination clause either party may terminate with notice",
+	)
+	revised := newStubDocument(t,
+		"terms and conditions of the agreement between parties",
+		"new confidentiality section covering proprietary information disclosure",
+		"termination clause either party may terminate with written notice",
+	)
+
+	result, err := document.Compare(original, revised, nil, nil)
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+
+	got := changes(result)
+	want := []document.PageChange{
+		document.PageUnchanged,
+		document.PageRemoved,
+		document.PageAdded,
+		document.PageModified,
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("Expected changes %v, got %v", want, got)
+	}
+
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Page diff %d: expected %s, got %s (%+v)", i, want[i], got[i], result.Pages[i])
+		}
+	}
+
+	modified := result.Pages[3]
+	if modified.OldPage != 3 || modified.NewPage != 3 {
+		t.Errorf("Expected modified page aligned 3→3, got %d→%d", modified.OldPage, modified.NewPage)
+	}
+
+	if modified.HashDistance != -1 {
+		t.Errorf("Expected hash distance -1 without renderer, got %d", modified.HashDistance)
+	}
+}
+
+func TestCompare_InsertedPageKeepsAlignment(t *testing.T) {
+	original := newStubDocument(t, "alpha section one", "beta section two", "gamma section three")
+	revised := newStubDocument(t, "alpha section one", "inserted cover letter page", "beta section two", "gamma section three")
+
+	result, err := document.Compare(original, revised, newFakeRenderer(), nil)
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+
+	changed := result.Changed()
+	if len(changed) != 1 {
+		t.Fatalf("Expected exactly 1 changed page, got %d: %+v", len(changed), changed)
+	}
+
+	if changed[0].Change != document.PageAdded || changed[0].NewPage != 2 {
+		t.Errorf("Expected page 2 added, got %+v", changed[0])
+	}
+}
+
+func TestCompare_PDF_SelfComparison(t *testing.T) {
+	a, err := document.OpenPDF(testPDFPath(t))
+	if err != nil {
+		t.Fatalf("OpenPDF failed: %v", err)
+	}
+	defer a.Close()
+
+	b, err := document.OpenPDF(testPDFPath(t))
+	if err != nil {
+		t.Fatalf("OpenPDF failed: %v", err)
+	}
+	defer b.Close()
+
+	result, err := document.Compare(a, b, nil, nil)
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+
+	if len(result.Changed()) != 0 {
+		t.Errorf("Expected no changes comparing a document with itself, got %+v", result.Changed())
+	}
+}
+
+func TestRenderDiff(t *testing.T) {
+	a := &stubPage{number: 1, image: stubImage(t, "original clause")}
+	b := &stubPage{number: 1, image: stubImage(t, "revised clause text")}
+
+	diff, err := document.RenderDiff(a, b, newFakeRenderer(), nil)
+	if err != nil {
+		t.Fatalf("RenderDiff failed: %v", err)
+	}
+
+	if diff.ChangedPixels == 0 {
+		t.Error("Expected changed pixels for different pages")
+	}
+
+	if diff.ChangedRatio <= 0 || diff.ChangedRatio >= 1 {
+		t.Errorf("Expected changed ratio in (0, 1), got %f", diff.ChangedRatio)
+	}
+
+	img, err := png.Decode(bytes.NewReader(diff.Data))
+	if err != nil {
+		t.Fatalf("Diff data is not a PNG: %v", err)
+	}
+
+	if img.Bounds().Dx() != 64 || img.Bounds().Dy() != 64 {
+		t.Errorf("Expected 64x64 diff image, got %v", img.Bounds())
+	}
+}
+
+func TestRenderDiff_Identical(t *testing.T) {
+	a := &stubPage{number: 1, image: stubImage(t, "same")}
+
+	diff, err := document.RenderDiff(a, a, newFakeRenderer(), nil)
+	if err != nil {
+		t.Fatalf("RenderDiff failed: %v", err)
+	}
+
+	if diff.ChangedPixels != 0 {
+		t.Errorf("Expected no changed pixels, got %d", diff.ChangedPixels)
+	}
+}
+
+func TestRenderDiff_RequiresRenderer(t *testing.T) {
+	a := &stubPage{number: 1, image: stubImage(t, "same")}
+
+	if _, err := document.RenderDiff(a, a, nil, nil); err == nil {
+		t.Error("Expected error when renderer is nil")
+	}
+}
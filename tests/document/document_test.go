@@ -1,6 +1,7 @@
 package document_test
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/JaimeStill/document-context/pkg/document"
@@ -25,7 +26,11 @@ func TestParseImageFormat(t *testing.T) {
 		{"whitespace only defaults to png", "   ", document.PNG, false},
 		{"invalid format gif", "gif", "", true},
 		{"invalid format bmp", "bmp", "", true},
-		{"invalid format webp", "webp", "", true},
+		{"webp lowercase", "webp", document.WebP, false},
+		{"WEBP uppercase", "WEBP", document.WebP, false},
+		{"avif lowercase", "avif", document.AVIF, false},
+		{"AVIF uppercase", "AVIF", document.AVIF, false},
+		{"invalid format tiff", "tiff", "", true},
 	}
 
 	for _, tt := range tests {
@@ -48,6 +53,32 @@ func TestParseImageFormat(t *testing.T) {
 	}
 }
 
+func TestImageFormat_MimeType(t *testing.T) {
+	tests := []struct {
+		format  document.ImageFormat
+		want    string
+		wantErr bool
+	}{
+		{document.PNG, "image/png", false},
+		{document.JPEG, "image/jpeg", false},
+		{document.WebP, "image/webp", false},
+		{document.AVIF, "image/avif", false},
+		{document.ImageFormat("bmp"), "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			got, err := tt.format.MimeType()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("MimeType() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("MimeType() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestSupportedFormats(t *testing.T) {
 	formats := document.SupportedFormats()
 
@@ -75,8 +106,16 @@ func TestIsSupported(t *testing.T) {
 		want        bool
 	}{
 		{"pdf supported", "application/pdf", true},
-		{"docx not supported", "application/vnd.openxmlformats-officedocument.wordprocessingml.document", false},
-		{"image/png not supported", "image/png", false},
+		{"docx supported", "application/vnd.openxmlformats-officedocument.wordprocessingml.document", true},
+		{"xlsx supported", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", true},
+		{"pptx supported", "application/vnd.openxmlformats-officedocument.presentationml.presentation", true},
+		{"csv supported", "text/csv", true},
+		{"png supported", "image/png", true},
+		{"eml supported", "message/rfc822", true},
+		{"zip supported", "application/zip", true},
+		{"html supported", "text/html", true},
+		{"epub supported", "application/epub+zip", true},
+		{"image/svg+xml not supported", "image/svg+xml", false},
 		{"empty string not supported", "", false},
 		{"text/plain not supported", "text/plain", false},
 	}
@@ -108,8 +147,8 @@ func TestOpen(t *testing.T) {
 
 	t.Run("unsupported content type", func(t *testing.T) {
 		_, err := document.Open(pdfPath, "application/msword")
-		if err == nil {
-			t.Error("Open() error = nil, want error for unsupported content type")
+		if !errors.Is(err, document.ErrUnsupportedContentType) {
+			t.Errorf("Open() error = %v, want ErrUnsupportedContentType", err)
 		}
 	})
 
@@ -0,0 +1,287 @@
+package document_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/JaimeStill/document-context/pkg/document"
+)
+
+const docxStyles = `<?xml version="1.0" encoding="UTF-8"?>
+<w:styles ` + wordNS + `>
+<w:style w:type="paragraph" w:styleId="Title"><w:name w:val="Title"/></w:style>
+<w:style w:type="paragraph" w:styleId="Heading1"><w:name w:val="heading 1"/><w:pPr><w:outlineLvl w:val="0"/></w:pPr></w:style>
+<w:style w:type="paragraph" w:styleId="Heading2"><w:name w:val="heading 2"/><w:basedOn w:val="Heading1"/><w:pPr><w:outlineLvl w:val="1"/></w:pPr></w:style>
+<w:style w:type="paragraph" w:styleId="ListBullet"><w:name w:val="List Bullet"/><w:pPr><w:numPr><w:numId w:val="1"/></w:numPr></w:pPr></w:style>
+</w:styles>`
+
+const docxNumbering = `<?xml version="1.0" encoding="UTF-8"?>
+<w:numbering ` + wordNS + `>
+<w:abstractNum w:abstractNumId="0">
+<w:lvl w:ilvl="0"><w:start w:val="1"/><w:numFmt w:val="bullet"/></w:lvl>
+<w:lvl w:ilvl="1"><w:start w:val="1"/><w:numFmt w:val="bullet"/></w:lvl>
+</w:abstractNum>
+<w:abstractNum w:abstractNumId="1">
+<w:lvl w:ilvl="0"><w:start w:val="1"/><w:numFmt w:val="decimal"/></w:lvl>
+</w:abstractNum>
+<w:num w:numId="1"><w:abstractNumId w:val="0"/></w:num>
+<w:num w:numId="2"><w:abstractNumId w:val="1"/></w:num>
+</w:numbering>`
+
+const docxFootnotes = `<?xml version="1.0" encoding="UTF-8"?>
+<w:footnotes ` + wordNS + `>
+<w:footnote w:type="separator" w:id="-1"><w:p><w:r><w:separator/></w:r></w:p></w:footnote>
+<w:footnote w:type="continuationSeparator" w:id="0"><w:p><w:r><w:continuationSeparator/></w:r></w:p></w:footnote>
+<w:footnote w:id="1"><w:p><w:r><w:footnoteRef/></w:r><w:r><w:t xml:space="preserve"> See the appendix.</w:t></w:r></w:p></w:footnote>
+<w:footnote w:id="2"><w:p><w:r><w:t>Never referenced.</w:t></w:r></w:p></w:footnote>
+</w:footnotes>`
+
+func listItem(numID, ilvl, text string) string {
+	return para(`<w:numPr><w:ilvl w:val="`+ilvl+`"/><w:numId w:val="`+numID+`"/></w:numPr>`, text)
+}
+
+func sampleDOCXBody() string {
+	return para(`<w:pStyle w:val="Title"/>`, "Quarterly Report") +
+		para(`<w:pStyle w:val="Heading1"/>`, "Summary") +
+		`<w:p><w:r><w:rPr><w:b/></w:rPr><w:t>Revenue grew</w:t></w:r>` +
+		`<w:r><w:footnoteReference w:id="1"/></w:r>` +
+		`<w:r><w:t xml:space="preserve"> this quarter.</w:t></w:r></w:p>` +
+		listItem("1", "0", "Faster delivery") +
+		listItem("1", "1", "Same-day shipping") +
+		para(`<w:pStyle w:val="ListBullet"/>`, "Lower cost") +
+		listItem("2", "0", "Plan") +
+		listItem("2", "0", "Build") +
+		`<w:p><w:r><w:br w:type="page"/></w:r></w:p>` +
+		para(`<w:pStyle w:val="Heading2"/>`, "Details") +
+		`<w:tbl><w:tblPr/><w:tblGrid><w:gridCol/><w:gridCol/></w:tblGrid>` +
+		`<w:tr><w:tc>` + para("", "Item") + `</w:tc><w:tc>` + para("", "Qty") + `</w:tc></w:tr>` +
+		`<w:tr><w:tc>` + para("", "Bolts") + `</w:tc><w:tc>` + para("", "40") + `</w:tc></w:tr>` +
+		`<w:tr><w:tc><w:tcPr><w:gridSpan w:val="2"/></w:tcPr>` + para("", "Total across all items") + `</w:tc></w:tr>` +
+		`</w:tbl>` +
+		para(`<w:pageBreakBefore/>`, "Appendix") +
+		`<w:sectPr/>`
+}
+
+func openSampleDOCX(t *testing.T) *document.DOCXDocument {
+	t.Helper()
+
+	doc, err := document.OpenDOCX(writeDOCX(t, sampleDOCXBody(), docxStyles, docxNumbering, docxFootnotes))
+	if err != nil {
+		t.Fatalf("OpenDOCX failed: %v", err)
+	}
+	t.Cleanup(func() { doc.Close() })
+	return doc
+}
+
+func docxPage(t *testing.T, doc document.Document, n int) *document.DOCXPage {
+	t.Helper()
+
+	page, err := doc.ExtractPage(n)
+	if err != nil {
+		t.Fatalf("ExtractPage(%d) failed: %v", n, err)
+	}
+	return page.(*document.DOCXPage)
+}
+
+func TestOpenDOCX_Pages(t *testing.T) {
+	doc := openSampleDOCX(t)
+
+	if doc.PageCount() != 3 {
+		t.Fatalf("expected 3 pages, got %d", doc.PageCount())
+	}
+	if !strings.HasPrefix(doc.Fingerprint(), "sha256:") {
+		t.Errorf("expected sha256 fingerprint, got %q", doc.Fingerprint())
+	}
+
+	pages, err := doc.ExtractAllPages()
+	if err != nil {
+		t.Fatalf("ExtractAllPages failed: %v", err)
+	}
+	if len(pages) != 3 || pages[2].Number() != 3 {
+		t.Errorf("unexpected pages: %v", pages)
+	}
+
+	if _, err := doc.ExtractPage(4); err == nil {
+		t.Error("expected error for out of range page")
+	}
+}
+
+func TestDOCXPage_Markdown(t *testing.T) {
+	doc := openSampleDOCX(t)
+
+	tests := []struct {
+		page int
+		want string
+	}{
+		{1, "# Quarterly Report\n\n" +
+			"# Summary\n\n" +
+			"Revenue grew[^1] this quarter.\n\n" +
+			"- Faster delivery\n" +
+			"  - Same-day shipping\n" +
+			"- Lower cost\n" +
+			"1. Plan\n" +
+			"2. Build\n\n" +
+			"[^1]: See the appendix."},
+		{2, "## Details\n\n" +
+			"| Item | Qty |\n" +
+			"| --- | --- |\n" +
+			"| Bolts | 40 |\n" +
+			"| Total across all items |  |"},
+		{3, "Appendix"},
+	}
+
+	for _, tt := range tests {
+		got, err := docxPage(t, doc, tt.page).Markdown()
+		if err != nil {
+			t.Fatalf("Markdown(page %d) failed: %v", tt.page, err)
+		}
+		if got != tt.want {
+			t.Errorf("Markdown(page %d) =\n%s\nwant:\n%s", tt.page, got, tt.want)
+		}
+	}
+}
+
+func TestDOCXPage_Text(t *testing.T) {
+	doc := openSampleDOCX(t)
+
+	tests := []struct {
+		page int
+		want string
+	}{
+		{1, "Quarterly Report\nSummary\nRevenue grew[1] this quarter.\n" +
+			"- Faster delivery\n  - Same-day shipping\n- Lower cost\n1. Plan\n2. Build\n\n" +
+			"[1] See the appendix."},
+		{2, "Details\nItem\tQty\nBolts\t40\nTotal across all items"},
+	}
+
+	for _, tt := range tests {
+		got, err := docxPage(t, doc, tt.page).Text()
+		if err != nil {
+			t.Fatalf("Text(page %d) failed: %v", tt.page, err)
+		}
+		if got != tt.want {
+			t.Errorf("Text(page %d) = %q, want %q", tt.page, got, tt.want)
+		}
+	}
+}
+
+func TestOpenDOCX_WithoutStyles(t *testing.T) {
+	body := para(`<w:pStyle w:val="Heading2"/>`, "Background") +
+		para("", "Plain paragraph.")
+
+	doc, err := document.OpenDOCX(writeDOCX(t, body, "", "", ""))
+	if err != nil {
+		t.Fatalf("OpenDOCX failed: %v", err)
+	}
+	defer doc.Close()
+
+	got, err := docxPage(t, doc, 1).Markdown()
+	if err != nil {
+		t.Fatalf("Markdown failed: %v", err)
+	}
+	if want := "## Background\n\nPlain paragraph."; got != want {
+		t.Errorf("Markdown() = %q, want %q", got, want)
+	}
+}
+
+func TestOpenDOCX_SectionBreaks(t *testing.T) {
+	tests := []struct {
+		name     string
+		sectType string
+		pages    int
+	}{
+		{"next page", "nextPage", 2},
+		{"continuous", "continuous", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := para(`<w:sectPr><w:type w:val="`+tt.sectType+`"/></w:sectPr>`, "Part one") +
+				para("", "Part two")
+
+			doc, err := document.OpenDOCX(writeDOCX(t, body, "", "", ""))
+			if err != nil {
+				t.Fatalf("OpenDOCX failed: %v", err)
+			}
+			defer doc.Close()
+
+			if doc.PageCount() != tt.pages {
+				t.Errorf("expected %d pages, got %d", tt.pages, doc.PageCount())
+			}
+		})
+	}
+}
+
+func TestOpenDOCX_Empty(t *testing.T) {
+	doc, err := document.OpenDOCX(writeDOCX(t, `<w:sectPr/>`, "", "", ""))
+	if err != nil {
+		t.Fatalf("OpenDOCX failed: %v", err)
+	}
+	defer doc.Close()
+
+	if doc.PageCount() != 1 {
+		t.Errorf("expected a single empty page, got %d pages", doc.PageCount())
+	}
+	if text, _ := docxPage(t, doc, 1).Text(); text != "" {
+		t.Errorf("expected empty text, got %q", text)
+	}
+}
+
+func TestOpenDOCX_Invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "broken.docx")
+	if err := os.WriteFile(path, []byte("not a zip"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := document.OpenDOCX(path); err == nil {
+		t.Error("expected error for non-zip file")
+	}
+
+	missing := writeZip(t, "empty.docx", map[string]string{"readme.txt": "hello"})
+	if _, err := document.OpenDOCX(missing); err == nil {
+		t.Error("expected error for package without a document part")
+	}
+}
+
+func TestDOCXPage_ToImage(t *testing.T) {
+	page := docxPage(t, openSampleDOCX(t), 1)
+
+	_, err := page.ToImage(nil, nil)
+	if !errors.Is(err, document.ErrRenderNotSupported) {
+		t.Errorf("expected ErrRenderNotSupported, got %v", err)
+	}
+}
+
+func TestDOCXPage_Closed(t *testing.T) {
+	doc := openSampleDOCX(t)
+	page := docxPage(t, doc, 1)
+	doc.Close()
+
+	if _, err := page.Text(); err == nil {
+		t.Error("expected error after Close")
+	}
+}
+
+func TestOpen_DOCX(t *testing.T) {
+	path := writeDOCX(t, para("", "Registered"), "", "", "")
+
+	doc, err := document.Open(path, "application/vnd.openxmlformats-officedocument.wordprocessingml.document")
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer doc.Close()
+
+	page, err := doc.ExtractPage(1)
+	if err != nil {
+		t.Fatalf("ExtractPage failed: %v", err)
+	}
+	if _, ok := page.(document.MarkdownPage); !ok {
+		t.Error("expected DOCX pages to implement MarkdownPage")
+	}
+	if text, _ := page.(document.TextPage).Text(); text != "Registered" {
+		t.Errorf("expected text 'Registered', got %q", text)
+	}
+}
@@ -0,0 +1,554 @@
+package document_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	stdimage "image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JaimeStill/document-context/pkg/config"
+	"github.com/JaimeStill/document-context/pkg/document"
+)
+
+// writePNG writes a blank PNG of the given size and returns its path.
+func writePNG(t *testing.T, width, height int) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, stdimage.NewGray(stdimage.Rect(0, 0, width, height))); err != nil {
+		t.Fatalf("Failed to encode PNG: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "image.png")
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatalf("Failed to write PNG: %v", err)
+	}
+	return path
+}
+
+// base64File returns the base64 encoding of a file, wrapped at 76 columns.
+func base64File(t *testing.T, path string) string {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", path, err)
+	}
+
+	encoded := base64.StdEncoding.EncodeToString(data)
+	var lines []string
+	for len(encoded) > 76 {
+		lines = append(lines, encoded[:76])
+		encoded = encoded[76:]
+	}
+	return strings.Join(append(lines, encoded), "\n")
+}
+
+func sampleEmail(t *testing.T) string {
+	t.Helper()
+
+	pdf := base64File(t, writeContentPDF(t, showText(72, 700, 12, "Invoice 42")))
+	logo := base64File(t, writePNG(t, 2, 3))
+
+	return `From: =?UTF-8?Q?Jos=C3=A9_P=C3=A9rez?= <jose@example.com>
+To: Support <support@example.com>, ops@example.com
+Subject: =?UTF-8?Q?Invoice_=E2=80=94_March?=
+Date: Mon, 02 Mar 2026 10:15:00 +0000
+Message-ID: <abc@example.com>
+MIME-Version: 1.0
+Content-Type: multipart/mixed; boundary="outer"
+
+--outer
+Content-Type: multipart/alternative; boundary="alt"
+
+--alt
+Content-Type: text/plain; charset=iso-8859-1
+Content-Transfer-Encoding: quoted-printable
+
+Please see the attached invoice.
+Gr=FC=DFe
+--alt
+Content-Type: text/html
+
+<p>HTML version</p>
+--alt--
+--outer
+Content-Type: application/pdf; name="invoice.pdf"
+Content-Disposition: attachment; filename="invoice.pdf"
+Content-Transfer-Encoding: base64
+
+` + pdf + `
+--outer
+Content-Type: image/png
+Content-Disposition: inline; filename="logo.png"
+Content-Transfer-Encoding: base64
+
+` + logo + `
+--outer
+Content-Type: application/octet-stream
+Content-Disposition: attachment; filename="data.bin"
+
+binary
+--outer
+Content-Type: message/rfc822
+
+From: alice@example.com
+Subject: Forwarded
+Content-Type: multipart/mixed; boundary="inner"
+
+--inner
+Content-Type: text/plain
+
+Inner body
+--inner
+Content-Type: application/octet-stream
+Content-Disposition: attachment; filename="rows.csv"
+
+a,b
+1,2
+--inner--
+--outer--
+`
+}
+
+func openEmail(t *testing.T, content string) *document.EmailDocument {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "message.eml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write message: %v", err)
+	}
+
+	doc, err := document.OpenEML(path)
+	if err != nil {
+		t.Fatalf("OpenEML failed: %v", err)
+	}
+	t.Cleanup(func() { doc.Close() })
+	return doc
+}
+
+func emailText(t *testing.T, doc document.Document) string {
+	t.Helper()
+
+	page, err := doc.ExtractPage(1)
+	if err != nil {
+		t.Fatalf("ExtractPage failed: %v", err)
+	}
+	text, err := page.(*document.EmailPage).Text()
+	if err != nil {
+		t.Fatalf("Text failed: %v", err)
+	}
+	return text
+}
+
+func TestOpenEML_Header(t *testing.T) {
+	doc := openEmail(t, sampleEmail(t))
+	h := doc.Header()
+
+	if h.From != "José Pérez <jose@example.com>" {
+		t.Errorf("From = %q", h.From)
+	}
+	if len(h.To) != 2 || h.To[0] != "Support <support@example.com>" || h.To[1] != "ops@example.com" {
+		t.Errorf("To = %q", h.To)
+	}
+	if h.Cc != nil {
+		t.Errorf("Cc = %q, want nil", h.Cc)
+	}
+	if h.Subject != "Invoice — March" {
+		t.Errorf("Subject = %q", h.Subject)
+	}
+	if !h.Date.Equal(time.Date(2026, 3, 2, 10, 15, 0, 0, time.UTC)) {
+		t.Errorf("Date = %v", h.Date)
+	}
+	if h.MessageID != "abc@example.com" {
+		t.Errorf("MessageID = %q", h.MessageID)
+	}
+}
+
+func TestEmailPage_Text(t *testing.T) {
+	doc := openEmail(t, sampleEmail(t))
+
+	want := "From: José Pérez <jose@example.com>\n" +
+		"To: Support <support@example.com>, ops@example.com\n" +
+		"Date: Mon, 02 Mar 2026 10:15:00 +0000\n" +
+		"Subject: Invoice — March\n" +
+		"Attachments: invoice.pdf, logo.png, data.bin, attachment-4\n" +
+		"\n" +
+		"Please see the attached invoice.\nGrüße"
+	if got := emailText(t, doc); got != want {
+		t.Errorf("Text mismatch\n got: %q\nwant: %q", got, want)
+	}
+}
+
+func TestEmailPage_HTMLBody(t *testing.T) {
+	doc := openEmail(t, "From: a@example.com\nContent-Type: text/html\n\n"+
+		"<html><head><style>p { color: red; }</style></head><body><p>Hello &amp; welcome</p><p>Second<br>line</p></body></html>\n")
+
+	text := emailText(t, doc)
+	if !strings.HasSuffix(text, "\n\nHello & welcome\nSecond\nline") {
+		t.Errorf("unexpected HTML body text %q", text)
+	}
+}
+
+func TestEmailDocument_Attachments(t *testing.T) {
+	doc := openEmail(t, sampleEmail(t))
+
+	attachments, err := doc.Attachments()
+	if err != nil {
+		t.Fatalf("Attachments failed: %v", err)
+	}
+	if len(attachments) != 4 {
+		t.Fatalf("expected 4 attachments, got %d", len(attachments))
+	}
+
+	pdf := attachments[0]
+	if pdf.Filename != "invoice.pdf" || pdf.ContentType != "application/pdf" {
+		t.Errorf("unexpected PDF attachment %q (%s)", pdf.Filename, pdf.ContentType)
+	}
+	if _, ok := pdf.Document.(*document.PDFDocument); !ok {
+		t.Fatalf("expected *PDFDocument, got %T (%v)", pdf.Document, pdf.Err)
+	}
+
+	logo, ok := attachments[1].Document.(*document.ImageDocument)
+	if !ok {
+		t.Fatalf("expected *ImageDocument, got %T (%v)", attachments[1].Document, attachments[1].Err)
+	}
+	if w, h := logo.Size(); w != 2 || h != 3 {
+		t.Errorf("image size = %dx%d, want 2x3", w, h)
+	}
+
+	bin := attachments[2]
+	if bin.Document != nil || !errors.Is(bin.Err, document.ErrUnsupportedContentType) {
+		t.Errorf("expected unsupported attachment, got %T, %v", bin.Document, bin.Err)
+	}
+	if bin.Size != len("binary") {
+		t.Errorf("attachment size = %d, want %d", bin.Size, len("binary"))
+	}
+
+	forwarded, ok := attachments[3].Document.(*document.EmailDocument)
+	if !ok {
+		t.Fatalf("expected *EmailDocument, got %T (%v)", attachments[3].Document, attachments[3].Err)
+	}
+	if attachments[3].Filename != "attachment-4.eml" {
+		t.Errorf("forwarded filename = %q", attachments[3].Filename)
+	}
+	if forwarded.Header().Subject != "Forwarded" {
+		t.Errorf("forwarded subject = %q", forwarded.Header().Subject)
+	}
+	if text := emailText(t, forwarded); !strings.HasSuffix(text, "Inner body") {
+		t.Errorf("forwarded text = %q", text)
+	}
+
+	nested, err := forwarded.Attachments()
+	if err != nil {
+		t.Fatalf("nested Attachments failed: %v", err)
+	}
+	if len(nested) != 1 || nested[0].ContentType != "text/csv" {
+		t.Fatalf("unexpected nested attachments %+v", nested)
+	}
+	if _, ok := nested[0].Document.(*document.SpreadsheetDocument); !ok {
+		t.Errorf("expected *SpreadsheetDocument, got %T (%v)", nested[0].Document, nested[0].Err)
+	}
+
+	again, err := doc.Attachments()
+	if err != nil {
+		t.Fatalf("Attachments (second call) failed: %v", err)
+	}
+	if again[0].Document != pdf.Document {
+		t.Error("expected the same attachment documents on repeated calls")
+	}
+}
+
+func TestOpenEMLWithConfig_Limits(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "message.eml")
+	if err := os.WriteFile(path, []byte(zipEmail(t)), 0644); err != nil {
+		t.Fatalf("Failed to write message: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		cfg  config.ArchiveConfig
+	}{
+		{"max entries", config.ArchiveConfig{MaxEntries: 1}},
+		{"max total size", config.ArchiveConfig{MaxTotalSize: 64}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := document.OpenEMLWithConfig(path, tt.cfg)
+			if err != nil {
+				t.Fatalf("OpenEMLWithConfig failed: %v", err)
+			}
+			defer doc.Close()
+
+			if _, err := doc.Attachments(); !errors.Is(err, document.ErrArchiveLimit) {
+				t.Errorf("expected ErrArchiveLimit, got %v", err)
+			}
+		})
+	}
+
+	// The message is not an archive, so an attached archive is the
+	// outermost one.
+	doc, err := document.OpenEMLWithConfig(path, config.ArchiveConfig{MaxEntries: 2, MaxDepth: 1})
+	if err != nil {
+		t.Fatalf("OpenEMLWithConfig failed: %v", err)
+	}
+	defer doc.Close()
+
+	attachments, err := doc.Attachments()
+	if err != nil {
+		t.Fatalf("expected attachments within limits to open, got %v", err)
+	}
+	if _, ok := attachments[0].Document.(*document.ArchiveDocument); !ok {
+		t.Errorf("expected attached *document.ArchiveDocument, got %T (%v)", attachments[0].Document, attachments[0].Err)
+	}
+}
+
+func TestEmailDocument_Close(t *testing.T) {
+	doc := openEmail(t, sampleEmail(t))
+
+	attachments, err := doc.Attachments()
+	if err != nil {
+		t.Fatalf("Attachments failed: %v", err)
+	}
+	forwarded := attachments[3].Document.(*document.EmailDocument)
+
+	if err := doc.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	if _, err := doc.Attachments(); err == nil {
+		t.Error("expected error after Close")
+	}
+	if _, err := forwarded.Attachments(); err == nil {
+		t.Error("expected attached message to be closed")
+	}
+}
+
+func TestEmailPage_ToImage(t *testing.T) {
+	doc := openEmail(t, sampleEmail(t))
+	page, err := doc.ExtractPage(1)
+	if err != nil {
+		t.Fatalf("ExtractPage failed: %v", err)
+	}
+
+	renderer := &capturingRenderer{fakeRenderer: newFakeRenderer()}
+	c := newMockCache()
+	for range 2 {
+		data, err := page.ToImage(renderer, c)
+		if err != nil {
+			t.Fatalf("ToImage failed: %v", err)
+		}
+		if string(data) != "page-1" {
+			t.Errorf("unexpected image data %q", data)
+		}
+	}
+	if renderer.renderCount() != 1 {
+		t.Errorf("expected cached image, rendered %d times", renderer.renderCount())
+	}
+
+	pdfPath := filepath.Join(t.TempDir(), "message.pdf")
+	if err := os.WriteFile(pdfPath, renderer.input, 0644); err != nil {
+		t.Fatalf("Failed to write message PDF: %v", err)
+	}
+	pdf, err := document.OpenPDF(pdfPath)
+	if err != nil {
+		t.Fatalf("rendered message is not a valid PDF: %v", err)
+	}
+	defer pdf.Close()
+
+	pdfPage, err := pdf.ExtractPage(1)
+	if err != nil {
+		t.Fatalf("ExtractPage failed: %v", err)
+	}
+	text, err := pdfPage.(*document.PDFPage).Text()
+	if err != nil {
+		t.Fatalf("Text failed: %v", err)
+	}
+	for _, want := range []string{"From: Jos", "Attachments: invoice.pdf", "Please see the attached invoice."} {
+		if !strings.Contains(text, want) {
+			t.Errorf("message PDF text missing %q:\n%s", want, text)
+		}
+	}
+}
+
+func TestEmailPage_WriteImage(t *testing.T) {
+	doc := openEmail(t, sampleEmail(t))
+	page, err := doc.ExtractPage(1)
+	if err != nil {
+		t.Fatalf("ExtractPage failed: %v", err)
+	}
+
+	renderer := &streamRenderer{fakeRenderer: newFakeRenderer()}
+
+	var buf bytes.Buffer
+	if err := page.(document.StreamPage).WriteImage(&buf, renderer, nil); err != nil {
+		t.Fatalf("WriteImage failed: %v", err)
+	}
+	if buf.String() != "page-1" || len(renderer.inputs) != 1 {
+		t.Errorf("expected one streamed page, got %q from %d streams", buf.String(), len(renderer.inputs))
+	}
+}
+
+func TestEmailDocument_ExtractPage_Closed(t *testing.T) {
+	doc := openEmail(t, sampleEmail(t))
+	if err := doc.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	if _, err := doc.ExtractPage(1); err == nil || !strings.Contains(err.Error(), "document is closed") {
+		t.Errorf("expected closed error, got %v", err)
+	}
+	if _, err := doc.ExtractAllPages(); err == nil {
+		t.Error("expected error after Close")
+	}
+}
+
+func TestImagePage_ToImage(t *testing.T) {
+	doc, err := document.OpenImage(writePNG(t, 4, 4))
+	if err != nil {
+		t.Fatalf("OpenImage failed: %v", err)
+	}
+	defer doc.Close()
+
+	if doc.Format() != "png" || doc.PageCount() != 1 {
+		t.Errorf("unexpected image document %s with %d pages", doc.Format(), doc.PageCount())
+	}
+
+	page, err := doc.ExtractPage(1)
+	if err != nil {
+		t.Fatalf("ExtractPage failed: %v", err)
+	}
+
+	renderer := newFakeRenderer()
+	c := newMockCache()
+	for range 2 {
+		data, err := page.ToImage(renderer, c)
+		if err != nil {
+			t.Fatalf("ToImage failed: %v", err)
+		}
+		if string(data) != "page-1" {
+			t.Errorf("unexpected image data %q", data)
+		}
+	}
+	if renderer.renderCount() != 1 {
+		t.Errorf("expected cached image, rendered %d times", renderer.renderCount())
+	}
+}
+
+func TestImagePage_ToImage_FitLimits(t *testing.T) {
+	path := writePNG(t, 3000, 2000)
+	doc, err := document.OpenImage(path)
+	if err != nil {
+		t.Fatalf("OpenImage failed: %v", err)
+	}
+	defer doc.Close()
+
+	page, err := doc.ExtractPage(1)
+	if err != nil {
+		t.Fatalf("ExtractPage failed: %v", err)
+	}
+
+	renderer := &fitRenderer{fakeRenderer: newFakeRenderer()}
+	renderer.settings.MaxLongEdge = 1568
+
+	if _, err := page.ToImage(renderer, nil); err != nil {
+		t.Fatalf("ToImage failed: %v", err)
+	}
+
+	if len(renderer.inputs) != 1 {
+		t.Fatalf("expected one render, got %d", len(renderer.inputs))
+	}
+	cfg, err := png.DecodeConfig(bytes.NewReader(renderer.inputs[0]))
+	if err != nil {
+		t.Fatalf("rendered input is not a PNG: %v", err)
+	}
+	if cfg.Width != 1568 || cfg.Height != 1045 {
+		t.Errorf("expected image resampled to 1568x1045, got %dx%d", cfg.Width, cfg.Height)
+	}
+	if data, err := os.ReadFile(path); err != nil || len(data) == 0 {
+		t.Errorf("expected source image to remain, got %v", err)
+	}
+
+	small, err := document.OpenImage(writePNG(t, 800, 600))
+	if err != nil {
+		t.Fatalf("OpenImage failed: %v", err)
+	}
+	defer small.Close()
+	smallPage, err := small.ExtractPage(1)
+	if err != nil {
+		t.Fatalf("ExtractPage failed: %v", err)
+	}
+
+	renderer.inputs = nil
+	renderer.dpis = nil
+	if _, err := smallPage.ToImage(renderer, nil); err != nil {
+		t.Fatalf("ToImage failed: %v", err)
+	}
+	if cfg, err := png.DecodeConfig(bytes.NewReader(renderer.inputs[0])); err != nil || cfg.Width != 1568 {
+		t.Errorf("expected small image scaled up to 1568 px wide, got %d (%v)", cfg.Width, err)
+	}
+}
+
+func TestOpenImage_Invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "broken.png")
+	if err := os.WriteFile(path, []byte("not an image"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	if _, err := document.OpenImage(path); err == nil {
+		t.Error("expected error for invalid image")
+	}
+}
+
+func TestDetectContentType(t *testing.T) {
+	pdf, err := os.ReadFile(testPDFPath(t))
+	if err != nil {
+		t.Fatalf("Failed to read test PDF: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		filename string
+		declared string
+		data     []byte
+		want     string
+	}{
+		{"declared supported", "scan", "application/pdf; name=scan", nil, "application/pdf"},
+		{"extension over generic", "report.xlsx", "application/octet-stream", nil, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"},
+		{"extension over unsupported", "notes.csv", "text/plain", nil, "text/csv"},
+		{"sniffed", "", "application/octet-stream", pdf, "application/pdf"},
+		{"declared unsupported", "", "application/x-custom", []byte("data"), "application/x-custom"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := document.DetectContentType(tt.filename, tt.declared, tt.data); got != tt.want {
+				t.Errorf("DetectContentType = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOpen_EML(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "message.eml")
+	if err := os.WriteFile(path, []byte("From: a@example.com\nSubject: Hi\n\nBody\n"), 0644); err != nil {
+		t.Fatalf("Failed to write message: %v", err)
+	}
+
+	doc, err := document.Open(path, "message/rfc822")
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer doc.Close()
+
+	if text := emailText(t, doc); text != "From: a@example.com\nSubject: Hi\n\nBody" {
+		t.Errorf("Text = %q", text)
+	}
+}
@@ -0,0 +1,353 @@
+package document_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/JaimeStill/document-context/pkg/config"
+	"github.com/JaimeStill/document-context/pkg/document"
+)
+
+const epubContainer = `<?xml version="1.0"?>
+<container version="1.0" xmlns="urn:oasis:names:tc:opendocument:xmlns:container">
+  <rootfiles>
+    <rootfile full-path="OEBPS/content.opf" media-type="application/oebps-package+xml"/>
+  </rootfiles>
+</container>`
+
+func xhtml(title, body string) string {
+	return `<?xml version="1.0" encoding="utf-8"?>
+<html xmlns="http://www.w3.org/1999/xhtml"><head><title>` + title + `</title></head><body>` + body + `</body></html>`
+}
+
+// writeEPUB3 writes an EPUB 3 book with two chapters, one with a space in its
+// file name, a cover image outside the spine, and a navigation document.
+func writeEPUB3(t *testing.T) string {
+	t.Helper()
+
+	return writeZip(t, "manual.epub", map[string]string{
+		"mimetype":               "application/epub+zip",
+		"META-INF/container.xml": epubContainer,
+		"OEBPS/content.opf": `<?xml version="1.0"?>
+<package xmlns="http://www.idpf.org/2007/opf" version="3.0">
+  <metadata xmlns:dc="http://purl.org/dc/elements/1.1/"><dc:title>Policy Manual</dc:title></metadata>
+  <manifest>
+    <item id="nav" href="nav.xhtml" media-type="application/xhtml+xml" properties="nav"/>
+    <item id="c1" href="text/ch1.xhtml" media-type="application/xhtml+xml"/>
+    <item id="c2" href="text/chapter%20two.xhtml" media-type="application/xhtml+xml"/>
+    <item id="cover" href="cover.jpg" media-type="image/jpeg"/>
+  </manifest>
+  <spine>
+    <itemref idref="c1"/>
+    <itemref idref="cover"/>
+    <itemref idref="c2"/>
+  </spine>
+</package>`,
+		"OEBPS/nav.xhtml": `<?xml version="1.0"?>
+<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops"><body>
+<nav epub:type="landmarks"><ol><li><a href="text/ch1.xhtml">Start</a></li></ol></nav>
+<nav epub:type="toc"><h1>Contents</h1><ol>
+  <li><a href="text/ch1.xhtml">Chapter&nbsp;One</a></li>
+  <li><a href="text/chapter%20two.xhtml"><em>Chapter</em> Two</a>
+    <ol><li><a href="text/chapter%20two.xhtml#leave">Leave Policy</a></li></ol>
+  </li>
+  <li><span>Appendices</span></li>
+</ol></nav>
+</body></html>`,
+		"OEBPS/text/ch1.xhtml": xhtml("One", `<h1>Chapter One</h1><p>Welcome to the manual.</p>`),
+		"OEBPS/text/chapter two.xhtml": xhtml("Two",
+			`<h1>Chapter Two</h1><p>General rules.</p><h2 id="leave">Leave Policy</h2><ul><li>Annual</li><li>Sick</li></ul>`),
+		"OEBPS/cover.jpg": "not an image",
+	})
+}
+
+func openEPUB(t *testing.T, path string, cfg config.EPUBConfig) *document.EPUBDocument {
+	t.Helper()
+
+	doc, err := document.OpenEPUBWithConfig(path, cfg)
+	if err != nil {
+		t.Fatalf("OpenEPUBWithConfig failed: %v", err)
+	}
+	t.Cleanup(func() { doc.Close() })
+	return doc
+}
+
+func epubPage(t *testing.T, doc document.Document, n int) *document.EPUBPage {
+	t.Helper()
+
+	page, err := doc.ExtractPage(n)
+	if err != nil {
+		t.Fatalf("ExtractPage(%d) failed: %v", n, err)
+	}
+	return page.(*document.EPUBPage)
+}
+
+func TestOpenEPUB_Chapters(t *testing.T) {
+	doc := openEPUB(t, writeEPUB3(t), config.EPUBConfig{})
+
+	if doc.Title() != "Policy Manual" {
+		t.Errorf("unexpected title %q", doc.Title())
+	}
+	if doc.PageCount() != 2 {
+		t.Fatalf("expected 2 pages (non-XHTML spine items skipped), got %d", doc.PageCount())
+	}
+
+	tests := []struct {
+		chapter int
+		href    string
+		text    string
+	}{
+		{1, "OEBPS/text/ch1.xhtml", "# Chapter One\n\nWelcome to the manual."},
+		{2, "OEBPS/text/chapter two.xhtml", "# Chapter Two\n\nGeneral rules.\n\n## Leave Policy\n\n- Annual\n- Sick"},
+	}
+
+	for i, tt := range tests {
+		page := epubPage(t, doc, i+1)
+		if page.Chapter() != tt.chapter {
+			t.Errorf("page %d: expected chapter %d, got %d", i+1, tt.chapter, page.Chapter())
+		}
+		if page.Href() != tt.href {
+			t.Errorf("page %d: expected href %q, got %q", i+1, tt.href, page.Href())
+		}
+		text, err := page.Text()
+		if err != nil {
+			t.Fatalf("Text failed: %v", err)
+		}
+		if text != tt.text {
+			t.Errorf("page %d: unexpected text:\n%s\nwant:\n%s", i+1, text, tt.text)
+		}
+	}
+}
+
+func TestEPUBDocument_Outline(t *testing.T) {
+	doc := openEPUB(t, writeEPUB3(t), config.EPUBConfig{})
+
+	want := []document.OutlineEntry{
+		{Title: "Chapter One", Level: 1, Target: "OEBPS/text/ch1.xhtml", Page: 1},
+		{Title: "Chapter Two", Level: 1, Target: "OEBPS/text/chapter two.xhtml", Page: 2},
+		{Title: "Leave Policy", Level: 2, Target: "OEBPS/text/chapter two.xhtml#leave", Page: 2},
+		{Title: "Appendices", Level: 1},
+	}
+
+	outline := doc.Outline()
+	if len(outline) != len(want) {
+		t.Fatalf("expected %d outline entries, got %d: %+v", len(want), len(outline), outline)
+	}
+	for i, w := range want {
+		if outline[i] != w {
+			t.Errorf("entry %d: expected %+v, got %+v", i, w, outline[i])
+		}
+	}
+}
+
+func TestEPUBDocument_OutlineNCX(t *testing.T) {
+	path := writeZip(t, "legacy.epub", map[string]string{
+		"META-INF/container.xml": epubContainer,
+		"OEBPS/content.opf": `<?xml version="1.0"?>
+<package xmlns="http://www.idpf.org/2007/opf" version="2.0">
+  <metadata xmlns:dc="http://purl.org/dc/elements/1.1/"><dc:title>Legacy</dc:title></metadata>
+  <manifest>
+    <item id="ncx" href="toc.ncx" media-type="application/x-dtbncx+xml"/>
+    <item id="c1" href="ch1.html" media-type="application/xhtml+xml"/>
+  </manifest>
+  <spine toc="ncx"><itemref idref="c1"/></spine>
+</package>`,
+		"OEBPS/toc.ncx": `<?xml version="1.0"?>
+<ncx xmlns="http://www.daisy.org/z3986/2005/ncx/" version="2005-1"><navMap>
+  <navPoint id="p1" playOrder="1"><navLabel><text>Introduction</text></navLabel><content src="ch1.html"/>
+    <navPoint id="p2" playOrder="2"><navLabel><text>Scope</text></navLabel><content src="ch1.html#scope"/></navPoint>
+  </navPoint>
+</navMap></ncx>`,
+		"OEBPS/ch1.html": xhtml("Intro", `<h1>Introduction</h1><h2 id="scope">Scope</h2><p>All staff.</p>`),
+	})
+
+	doc := openEPUB(t, path, config.EPUBConfig{})
+
+	want := []document.OutlineEntry{
+		{Title: "Introduction", Level: 1, Target: "OEBPS/ch1.html", Page: 1},
+		{Title: "Scope", Level: 2, Target: "OEBPS/ch1.html#scope", Page: 1},
+	}
+	outline := doc.Outline()
+	if len(outline) != len(want) {
+		t.Fatalf("expected %d outline entries, got %+v", len(want), outline)
+	}
+	for i, w := range want {
+		if outline[i] != w {
+			t.Errorf("entry %d: expected %+v, got %+v", i, w, outline[i])
+		}
+	}
+}
+
+func TestOpenEPUB_ChunkedChapters(t *testing.T) {
+	var body strings.Builder
+	body.WriteString("<h1>Long Chapter</h1>")
+	for i := range 6 {
+		body.WriteString("<p>" + strings.Repeat("word ", 15) + "</p>")
+		if i == 2 {
+			body.WriteString("<h2>Second Half</h2>")
+		}
+	}
+
+	path := writeZip(t, "long.epub", map[string]string{
+		"META-INF/container.xml": epubContainer,
+		"OEBPS/content.opf": `<package xmlns="http://www.idpf.org/2007/opf" version="3.0">
+  <manifest><item id="c1" href="long.xhtml" media-type="application/xhtml+xml"/></manifest>
+  <spine><itemref idref="c1"/></spine>
+</package>`,
+		"OEBPS/long.xhtml": xhtml("Long", body.String()),
+	})
+
+	doc := openEPUB(t, path, config.EPUBConfig{MaxChars: 200})
+
+	if doc.PageCount() < 3 {
+		t.Fatalf("expected chapter to be split into several pages, got %d", doc.PageCount())
+	}
+
+	var texts []string
+	for i := 1; i <= doc.PageCount(); i++ {
+		page := epubPage(t, doc, i)
+		if page.Chapter() != 1 {
+			t.Errorf("page %d: expected chapter 1, got %d", i, page.Chapter())
+		}
+		text, _ := page.Text()
+		if len(text) > 200 {
+			t.Errorf("page %d: expected at most 200 characters, got %d", i, len(text))
+		}
+		if strings.HasSuffix(text, "Second Half") {
+			t.Errorf("page %d ends with a heading", i)
+		}
+		texts = append(texts, text)
+	}
+
+	if !strings.Contains(strings.Join(texts, "\n\n"), "## Second Half\n\nword") {
+		t.Error("expected heading to stay with the following paragraph")
+	}
+}
+
+func TestEPUBPage_WriteImage(t *testing.T) {
+	doc := openEPUB(t, writeEPUB3(t), config.EPUBConfig{})
+	var page document.StreamPage = epubPage(t, doc, 2)
+
+	renderer := &streamRenderer{fakeRenderer: newFakeRenderer()}
+
+	var buf bytes.Buffer
+	if err := page.WriteImage(&buf, renderer, nil); err != nil {
+		t.Fatalf("WriteImage failed: %v", err)
+	}
+
+	if buf.String() != "page-1" {
+		t.Errorf("unexpected image data %q", buf.String())
+	}
+	if len(renderer.inputs) != 1 {
+		t.Fatalf("expected one stream, got %d", len(renderer.inputs))
+	}
+	if _, err := os.Stat(renderer.inputs[0]); !os.IsNotExist(err) {
+		t.Errorf("expected temporary page PDF to be removed, got %v", err)
+	}
+}
+
+func TestOpenEPUB_Invalid(t *testing.T) {
+	if _, err := document.OpenEPUB(writeZip(t, "empty.epub", map[string]string{"mimetype": "application/epub+zip"})); err == nil {
+		t.Error("expected error for EPUB without container")
+	}
+}
+
+func TestEPUBPage_ToImage(t *testing.T) {
+	doc := openEPUB(t, writeEPUB3(t), config.EPUBConfig{})
+	page := epubPage(t, doc, 2)
+
+	renderer := &capturingRenderer{fakeRenderer: newFakeRenderer()}
+	c := newMockCache()
+
+	data, err := page.ToImage(renderer, c)
+	if err != nil {
+		t.Fatalf("ToImage failed: %v", err)
+	}
+	if string(data) != "page-1" {
+		t.Errorf("unexpected image data %q", data)
+	}
+	if _, err := page.ToImage(renderer, c); err != nil {
+		t.Fatalf("ToImage failed: %v", err)
+	}
+	if renderer.renderCount() != 1 {
+		t.Errorf("expected cached render, got %d renders", renderer.renderCount())
+	}
+
+	pdfPath := filepath.Join(t.TempDir(), "page.pdf")
+	if err := os.WriteFile(pdfPath, renderer.input, 0644); err != nil {
+		t.Fatalf("Failed to write page PDF: %v", err)
+	}
+	pdf, err := document.OpenPDF(pdfPath)
+	if err != nil {
+		t.Fatalf("rendered page is not a valid PDF: %v", err)
+	}
+	defer pdf.Close()
+
+	pdfPage, err := pdf.ExtractPage(1)
+	if err != nil {
+		t.Fatalf("ExtractPage failed: %v", err)
+	}
+	text, err := pdfPage.(*document.PDFPage).Text()
+	if err != nil {
+		t.Fatalf("Text failed: %v", err)
+	}
+	for _, want := range []string{"Chapter Two", "Leave Policy", "- Sick"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("page PDF text missing %q:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "#") {
+		t.Errorf("expected heading markers to be dropped from rendering:\n%s", text)
+	}
+}
+
+func TestEPUBPage_ToImage_FitLimits(t *testing.T) {
+	var body strings.Builder
+	body.WriteString("<h1>Long Chapter</h1>")
+	for range 200 {
+		body.WriteString("<p>" + strings.Repeat("word ", 15) + "</p>")
+	}
+
+	path := writeZip(t, "long.epub", map[string]string{
+		"META-INF/container.xml": epubContainer,
+		"OEBPS/content.opf": `<package xmlns="http://www.idpf.org/2007/opf" version="3.0">
+  <manifest><item id="c1" href="long.xhtml" media-type="application/xhtml+xml"/></manifest>
+  <spine><itemref idref="c1"/></spine>
+</package>`,
+		"OEBPS/long.xhtml": xhtml("Long", body.String()),
+	})
+	page := epubPage(t, openEPUB(t, path, config.EPUBConfig{}), 1)
+
+	renderer := &fitRenderer{fakeRenderer: newFakeRenderer()}
+	renderer.settings.MaxLongEdge = 1568
+
+	if _, err := page.ToImage(renderer, nil); err != nil {
+		t.Fatalf("ToImage failed: %v", err)
+	}
+
+	// The page grows with the chapter, far past 1568 px at 150 DPI.
+	if edge := renderedLongEdge(t, renderer); edge > 1568 || edge < 1567 {
+		t.Errorf("expected long edge fitted to 1568 px, got %.2f at %v DPI", edge, renderer.dpis[0])
+	}
+}
+
+func TestEPUBPage_Render_ConfigCacheKey(t *testing.T) {
+	path := writeEPUB3(t)
+
+	var keys []string
+	for _, maxChars := range []int{3000, 20} {
+		page := epubPage(t, openEPUB(t, path, config.EPUBConfig{MaxChars: maxChars}), 1)
+		result, err := page.Render(newFakeRenderer(), newMockCache())
+		if err != nil {
+			t.Fatalf("Render (MaxChars %d) failed: %v", maxChars, err)
+		}
+		keys = append(keys, result.CacheKey)
+	}
+
+	if keys[0] == keys[1] {
+		t.Errorf("expected different MaxChars to produce different cache keys, got %s", keys[0])
+	}
+}
@@ -0,0 +1,236 @@
+package document_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/JaimeStill/document-context/pkg/document"
+)
+
+// openExtracted writes PDF bytes to a temp file and opens it.
+func openExtracted(t *testing.T, data []byte) *document.PDFDocument {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "extracted.pdf")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("Failed to write extracted PDF: %v", err)
+	}
+
+	doc, err := document.OpenPDF(path)
+	if err != nil {
+		t.Fatalf("Failed to open extracted PDF: %v", err)
+	}
+	t.Cleanup(func() { doc.Close() })
+
+	return doc
+}
+
+func pageText(t *testing.T, doc document.Document, n int) string {
+	t.Helper()
+
+	page, err := doc.ExtractPage(n)
+	if err != nil {
+		t.Fatalf("ExtractPage(%d) failed: %v", n, err)
+	}
+
+	text, err := page.(document.TextPage).Text()
+	if err != nil {
+		t.Fatalf("Text() failed: %v", err)
+	}
+	return text
+}
+
+func TestPDFDocument_WritePDF(t *testing.T) {
+	doc, err := document.OpenPDF(testPDFPath(t))
+	if err != nil {
+		t.Fatalf("OpenPDF failed: %v", err)
+	}
+	defer doc.Close()
+
+	var buf bytes.Buffer
+	if err := doc.WritePDF(&buf, 2, 1); err != nil {
+		t.Fatalf("WritePDF failed: %v", err)
+	}
+
+	if !bytes.HasPrefix(buf.Bytes(), []byte("%PDF-")) {
+		t.Fatal("output is not a PDF")
+	}
+
+	out := openExtracted(t, buf.Bytes())
+	if out.PageCount() != 2 {
+		t.Fatalf("PageCount() = %d, want 2", out.PageCount())
+	}
+
+	if got, want := pageText(t, out, 1), pageText(t, doc, 2); got != want {
+		t.Error("first extracted page does not match original page 2")
+	}
+	if got, want := pageText(t, out, 2), pageText(t, doc, 1); got != want {
+		t.Error("second extracted page does not match original page 1")
+	}
+}
+
+func TestPDFDocument_WritePDF_InvalidPages(t *testing.T) {
+	doc, err := document.OpenPDF(testPDFPath(t))
+	if err != nil {
+		t.Fatalf("OpenPDF failed: %v", err)
+	}
+	defer doc.Close()
+
+	tests := []struct {
+		name  string
+		pages []int
+	}{
+		{name: "none", pages: nil},
+		{name: "zero", pages: []int{0}},
+		{name: "beyond count", pages: []int{1, 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := doc.WritePDF(&buf, tt.pages...); err == nil {
+				t.Error("expected error for invalid page selection")
+			}
+		})
+	}
+}
+
+func TestPDFDocument_WritePDF_Closed(t *testing.T) {
+	doc, err := document.OpenPDF(testPDFPath(t))
+	if err != nil {
+		t.Fatalf("OpenPDF failed: %v", err)
+	}
+	doc.Close()
+
+	var buf bytes.Buffer
+	if err := doc.WritePDF(&buf, 1); err == nil {
+		t.Error("expected error for closed document")
+	}
+}
+
+func TestPDFPage_ToPDF(t *testing.T) {
+	page := extractPDFPage(t, 2)
+
+	data, err := page.ToPDF(nil)
+	if err != nil {
+		t.Fatalf("ToPDF failed: %v", err)
+	}
+
+	out := openExtracted(t, data)
+	if out.PageCount() != 1 {
+		t.Errorf("PageCount() = %d, want 1", out.PageCount())
+	}
+
+	original, err := page.Text()
+	if err != nil {
+		t.Fatalf("Text() failed: %v", err)
+	}
+	if got := pageText(t, out, 1); got != original {
+		t.Error("extracted page text does not match original")
+	}
+}
+
+func TestPDFDocument_ExtractPDF_Cache(t *testing.T) {
+	dir := t.TempDir()
+	doc, err := document.OpenPDF(copyTestPDF(t, dir, "excerpt.pdf"))
+	if err != nil {
+		t.Fatalf("OpenPDF failed: %v", err)
+	}
+	defer doc.Close()
+
+	c := newMockCache()
+
+	first, err := doc.ExtractPDF([]int{1, 2}, c)
+	if err != nil {
+		t.Fatalf("ExtractPDF failed: %v", err)
+	}
+	if c.entryCount() != 1 {
+		t.Fatalf("cache entries = %d, want 1", c.entryCount())
+	}
+
+	for _, entry := range c.entries {
+		if entry.Filename != "excerpt.1-2.pdf" {
+			t.Errorf("Filename = %q, want %q", entry.Filename, "excerpt.1-2.pdf")
+		}
+	}
+
+	second, err := doc.ExtractPDF([]int{1, 2}, c)
+	if err != nil {
+		t.Fatalf("ExtractPDF failed: %v", err)
+	}
+	if !bytes.Equal(first, second) {
+		t.Error("cached PDF differs from extracted PDF")
+	}
+
+	if _, err := doc.ExtractPDF([]int{2, 1}, c); err != nil {
+		t.Fatalf("ExtractPDF failed: %v", err)
+	}
+	if c.entryCount() != 2 {
+		t.Errorf("cache entries = %d, want 2 (page order is part of the key)", c.entryCount())
+	}
+
+	page, err := doc.ExtractPage(1)
+	if err != nil {
+		t.Fatalf("ExtractPage failed: %v", err)
+	}
+	if _, err := page.(*document.PDFPage).ToPDF(c); err != nil {
+		t.Fatalf("ToPDF failed: %v", err)
+	}
+	if c.entryCount() != 3 {
+		t.Errorf("cache entries = %d, want 3", c.entryCount())
+	}
+}
+
+func TestPDFDocument_ExtractPDF_CacheHit(t *testing.T) {
+	doc, err := document.OpenPDF(testPDFPath(t))
+	if err != nil {
+		t.Fatalf("OpenPDF failed: %v", err)
+	}
+	defer doc.Close()
+
+	c := newMockCache()
+	if _, err := doc.ExtractPDF([]int{1}, c); err != nil {
+		t.Fatalf("ExtractPDF failed: %v", err)
+	}
+
+	for _, entry := range c.entries {
+		entry.Data = []byte("cached")
+	}
+
+	data, err := doc.ExtractPDF([]int{1}, c)
+	if err != nil {
+		t.Fatalf("ExtractPDF failed: %v", err)
+	}
+	if string(data) != "cached" {
+		t.Error("expected cached data to be returned without re-extracting")
+	}
+}
+
+func TestPDFDocument_ExtractPDF_CacheErrors(t *testing.T) {
+	doc, err := document.OpenPDF(testPDFPath(t))
+	if err != nil {
+		t.Fatalf("OpenPDF failed: %v", err)
+	}
+	defer doc.Close()
+
+	getErr := errors.New("storage unavailable")
+	c := newMockCache()
+	c.setGetError(getErr)
+
+	if _, err := doc.ExtractPDF([]int{1}, c); !errors.Is(err, getErr) {
+		t.Errorf("expected get error to propagate, got %v", err)
+	}
+
+	setErr := errors.New("disk full")
+	c = newMockCache()
+	c.setSetError(setErr)
+
+	_, err = doc.ExtractPDF([]int{1}, c)
+	if err == nil || !strings.Contains(err.Error(), "disk full") {
+		t.Errorf("expected set error to propagate, got %v", err)
+	}
+}
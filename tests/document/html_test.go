@@ -0,0 +1,253 @@
+package document_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/JaimeStill/document-context/pkg/config"
+	"github.com/JaimeStill/document-context/pkg/document"
+)
+
+const sampleHTML = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Quarterly  &amp; Annual Report</title>
+<style>h1 { color: red; }</style>
+<script>var hidden = "not text";</script>
+</head>
+<body>
+<h1>Overview</h1>
+<p>Revenue grew <b>12%</b> this quarter.</p>
+<h2 class="section">Regional <em>Results</em></h2>
+<ul><li>North</li><li>South &amp; East</li></ul>
+</body>
+</html>
+`
+
+func writeHTML(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "report.html")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write HTML: %v", err)
+	}
+	return path
+}
+
+// writeFakeConverter creates an executable standing in for an HTML-to-PDF
+// converter. It records its arguments and copies a two-page PDF to the output
+// path: the value of a leading --print-to-pdf= argument, or else the last
+// argument.
+func writeFakeConverter(t *testing.T, exitCode string) (binary, argsFile string) {
+	t.Helper()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("fake converter requires a POSIX shell")
+	}
+
+	pdf := writeContentPDF(t,
+		showText(72, 700, 12, "Converted one"),
+		showText(72, 700, 12, "Converted two"),
+	)
+
+	dir := t.TempDir()
+	argsFile = filepath.Join(dir, "args")
+
+	script := "#!/bin/sh\n" +
+		"echo \"$@\" >> '" + argsFile + "'\n" +
+		"for a in \"$@\"; do out=\"$a\"; done\n" +
+		"case \"$1\" in --print-to-pdf=*) out=\"${1#--print-to-pdf=}\";; esac\n" +
+		"cp '" + pdf + "' \"$out\"\n" +
+		"echo 'fake diagnostics' >&2\n" +
+		"exit " + exitCode + "\n"
+
+	binary = filepath.Join(dir, "converter")
+	if err := os.WriteFile(binary, []byte(script), 0755); err != nil {
+		t.Fatalf("Failed to write fake converter: %v", err)
+	}
+	return binary, argsFile
+}
+
+func openHTML(t *testing.T, path string, cfg config.HTMLConfig) *document.HTMLDocument {
+	t.Helper()
+
+	doc, err := document.OpenHTMLWithConfig(path, cfg)
+	if err != nil {
+		t.Fatalf("OpenHTMLWithConfig failed: %v", err)
+	}
+	t.Cleanup(func() { doc.Close() })
+	return doc
+}
+
+func TestOpenHTML_TextOnly(t *testing.T) {
+	doc := openHTML(t, writeHTML(t, sampleHTML), config.HTMLConfig{TextOnly: true})
+
+	if !doc.TextOnly() {
+		t.Error("expected text-only document")
+	}
+	if doc.Title() != "Quarterly & Annual Report" {
+		t.Errorf("unexpected title %q", doc.Title())
+	}
+	if doc.PageCount() != 1 {
+		t.Fatalf("expected 1 page, got %d", doc.PageCount())
+	}
+
+	page, err := doc.ExtractPage(1)
+	if err != nil {
+		t.Fatalf("ExtractPage failed: %v", err)
+	}
+	text, err := page.(document.TextPage).Text()
+	if err != nil {
+		t.Fatalf("Text failed: %v", err)
+	}
+
+	want := "# Overview\n\nRevenue grew 12% this quarter.\n\n## Regional Results\n\n- North\n- South & East"
+	if text != want {
+		t.Errorf("unexpected text:\n%s\nwant:\n%s", text, want)
+	}
+	if text != doc.Text() {
+		t.Error("expected page text to match document text")
+	}
+
+	_, err = page.ToImage(newFakeRenderer(), nil)
+	if !errors.Is(err, document.ErrRenderNotSupported) {
+		t.Errorf("expected ErrRenderNotSupported, got %v", err)
+	}
+
+	if _, err := doc.ExtractPage(2); err == nil {
+		t.Error("expected error for out of range page")
+	}
+}
+
+func TestOpenHTML_Charset(t *testing.T) {
+	content := "<html><head><meta http-equiv=\"Content-Type\" content=\"text/html; charset=iso-8859-1\"></head>" +
+		"<body><p>Gr\xfc\xdfe</p></body></html>"
+
+	doc := openHTML(t, writeHTML(t, content), config.HTMLConfig{TextOnly: true})
+	if doc.Text() != "Grüße" {
+		t.Errorf("expected Latin-1 text to be decoded, got %q", doc.Text())
+	}
+}
+
+func TestOpenHTML_Converter(t *testing.T) {
+	binary, argsFile := writeFakeConverter(t, "0")
+	path := writeHTML(t, sampleHTML)
+
+	doc := openHTML(t, path, config.HTMLConfig{Converter: binary})
+
+	if doc.TextOnly() {
+		t.Error("expected rendered document")
+	}
+	if doc.PageCount() != 2 {
+		t.Fatalf("expected 2 pages, got %d", doc.PageCount())
+	}
+
+	args := readFile(t, argsFile)
+	if !strings.HasPrefix(args, "--quiet ") || !strings.Contains(args, "report.html") || !strings.Contains(args, ".pdf") {
+		t.Errorf("unexpected converter arguments %q", args)
+	}
+
+	pages, err := doc.ExtractAllPages()
+	if err != nil {
+		t.Fatalf("ExtractAllPages failed: %v", err)
+	}
+
+	text, err := pages[1].(document.TextPage).Text()
+	if err != nil {
+		t.Fatalf("Text failed: %v", err)
+	}
+	if text != "Converted two" {
+		t.Errorf("expected text of converted page, got %q", text)
+	}
+	if !strings.Contains(doc.Text(), "## Regional Results") {
+		t.Errorf("expected readable document text, got %q", doc.Text())
+	}
+}
+
+func TestOpenHTML_ConverterArgsPlaceholders(t *testing.T) {
+	binary, argsFile := writeFakeConverter(t, "0")
+
+	doc := openHTML(t, writeHTML(t, sampleHTML), config.HTMLConfig{
+		Converter: binary,
+		Args:      []string{"--print-to-pdf={output}", "--headless", "{input}"},
+	})
+	if doc.PageCount() != 2 {
+		t.Errorf("expected 2 pages, got %d", doc.PageCount())
+	}
+
+	args := readFile(t, argsFile)
+	if !strings.HasPrefix(args, "--print-to-pdf=/") || strings.Contains(args, "{") {
+		t.Errorf("expected placeholders to be replaced, got %q", args)
+	}
+
+	_, err := document.OpenHTMLWithConfig(writeHTML(t, sampleHTML), config.HTMLConfig{
+		Converter: binary,
+		Args:      []string{"{input}"},
+	})
+	if err == nil {
+		t.Error("expected error for args without {output}")
+	}
+}
+
+func TestOpenHTML_ConverterFailure(t *testing.T) {
+	binary, _ := writeFakeConverter(t, "1")
+
+	_, err := document.OpenHTMLWithConfig(writeHTML(t, sampleHTML), config.HTMLConfig{Converter: binary})
+	if err == nil {
+		t.Fatal("expected error when converter fails")
+	}
+	if !strings.Contains(err.Error(), "fake diagnostics") {
+		t.Errorf("expected converter output in error, got %v", err)
+	}
+}
+
+func TestHTMLPage_ToImage(t *testing.T) {
+	binary, _ := writeFakeConverter(t, "0")
+	path := writeHTML(t, sampleHTML)
+	renderer := newFakeRenderer()
+	c := newMockCache()
+
+	for range 2 {
+		doc := openHTML(t, path, config.HTMLConfig{Converter: binary})
+
+		page, err := doc.ExtractPage(2)
+		if err != nil {
+			t.Fatalf("ExtractPage failed: %v", err)
+		}
+		data, err := page.ToImage(renderer, c)
+		if err != nil {
+			t.Fatalf("ToImage failed: %v", err)
+		}
+		if string(data) != "page-2" {
+			t.Errorf("expected second converted page, got %q", data)
+		}
+	}
+
+	if renderer.renderCount() != 1 {
+		t.Errorf("expected conversions of the same HTML to share cache entries, got %d renders", renderer.renderCount())
+	}
+}
+
+func TestHTMLDocument_Close(t *testing.T) {
+	binary, _ := writeFakeConverter(t, "0")
+
+	doc, err := document.OpenHTMLWithConfig(writeHTML(t, sampleHTML), config.HTMLConfig{Converter: binary})
+	if err != nil {
+		t.Fatalf("OpenHTMLWithConfig failed: %v", err)
+	}
+
+	if err := doc.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if err := doc.Close(); err != nil {
+		t.Errorf("second Close failed: %v", err)
+	}
+	if _, err := doc.ExtractPage(1); err == nil {
+		t.Error("expected error extracting page of closed document")
+	}
+}
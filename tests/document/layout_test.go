@@ -0,0 +1,179 @@
+package document_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/JaimeStill/document-context/pkg/document"
+)
+
+func openLayoutPage(t *testing.T, content string) document.LayoutPage {
+	t.Helper()
+
+	doc, err := document.OpenPDF(writeContentPDF(t, content))
+	if err != nil {
+		t.Fatalf("OpenPDF failed: %v", err)
+	}
+	t.Cleanup(func() { doc.Close() })
+
+	page, err := doc.ExtractPage(1)
+	if err != nil {
+		t.Fatalf("ExtractPage failed: %v", err)
+	}
+
+	lp, ok := page.(document.LayoutPage)
+	if !ok {
+		t.Fatal("expected PDFPage to implement LayoutPage")
+	}
+	return lp
+}
+
+// twoColumnPaper draws a running header, a title centered across the gutter,
+// two columns of lines with aligned baselines, and a page number footer. Each
+// row is drawn left then right, so content stream order interleaves the
+// columns.
+func twoColumnPaper(rows int) string {
+	var b strings.Builder
+	b.WriteString(showText(72, 760, 8, "Journal of Examples"))
+	b.WriteString(showText(230, 700, 14, "A Study of Columns"))
+	for i := range rows {
+		y := 660 - float64(i)*12
+		b.WriteString(showText(72, y, 10, fmt.Sprintf("left column line %d of the paper", i+1)))
+		b.WriteString(showText(320, y, 10, fmt.Sprintf("right column line %d of the paper", i+1)))
+	}
+	b.WriteString(showText(300, 30, 8, "7"))
+	return b.String()
+}
+
+func TestPDFPage_Layout_TwoColumns(t *testing.T) {
+	page := openLayoutPage(t, twoColumnPaper(3))
+
+	regions, err := page.Layout()
+	if err != nil {
+		t.Fatalf("Layout failed: %v", err)
+	}
+
+	want := []struct {
+		kind document.RegionKind
+		text string
+	}{
+		{document.RegionHeader, "Journal of Examples"},
+		{document.RegionBody, "A Study of Columns"},
+		{document.RegionBody, "left column line 1 of the paper\nleft column line 2 of the paper\nleft column line 3 of the paper"},
+		{document.RegionBody, "right column line 1 of the paper\nright column line 2 of the paper\nright column line 3 of the paper"},
+		{document.RegionFooter, "7"},
+	}
+
+	if len(regions) != len(want) {
+		t.Fatalf("expected %d regions, got %d: %+v", len(want), len(regions), regions)
+	}
+	for i, w := range want {
+		if regions[i].Kind != w.kind || regions[i].Text != w.text {
+			t.Errorf("region %d = %s %q, want %s %q", i, regions[i].Kind, regions[i].Text, w.kind, w.text)
+		}
+	}
+
+	left := regions[2].Bounds
+	if left.X0 != 72 || left.X1 >= 320 {
+		t.Errorf("unexpected left column bounds: %+v", left)
+	}
+}
+
+func TestPDFPage_ExtractText_Modes(t *testing.T) {
+	page := openLayoutPage(t, twoColumnPaper(2))
+
+	stream, err := page.ExtractText(document.TextStream)
+	if err != nil {
+		t.Fatalf("ExtractText(stream) failed: %v", err)
+	}
+	text, err := page.Text()
+	if err != nil {
+		t.Fatalf("Text failed: %v", err)
+	}
+	if stream != text {
+		t.Errorf("expected stream mode to match Text()\nstream: %q\ntext:   %q", stream, text)
+	}
+	if !strings.Contains(stream, "left column line 1 of the paper right column line 1 of the paper") {
+		t.Errorf("expected stream order to interleave columns, got %q", stream)
+	}
+
+	reading, err := page.ExtractText(document.TextReading)
+	if err != nil {
+		t.Fatalf("ExtractText(reading) failed: %v", err)
+	}
+
+	want := "Journal of Examples\n\n" +
+		"A Study of Columns\n\n" +
+		"left column line 1 of the paper\nleft column line 2 of the paper\n\n" +
+		"right column line 1 of the paper\nright column line 2 of the paper\n\n" +
+		"7"
+	if reading != want {
+		t.Errorf("reading order = %q, want %q", reading, want)
+	}
+}
+
+func TestPDFPage_ExtractText_InvalidMode(t *testing.T) {
+	page := openLayoutPage(t, showText(72, 700, 10, "text"))
+
+	if _, err := page.ExtractText("columns"); err == nil {
+		t.Error("expected error for unknown text mode")
+	}
+}
+
+func TestPDFPage_Layout_Sidebar(t *testing.T) {
+	var b strings.Builder
+	for i := range 4 {
+		y := 700 - float64(i)*12
+		b.WriteString(showText(400, y, 10, fmt.Sprintf("Margin note %d here", i+1)))
+		b.WriteString(showText(72, y, 10, fmt.Sprintf("Main text line %d runs across most of the page", i+1)))
+	}
+	b.WriteString(showText(72, 640, 10, "Main text continues below the notes"))
+
+	page := openLayoutPage(t, b.String())
+
+	regions, err := page.Layout()
+	if err != nil {
+		t.Fatalf("Layout failed: %v", err)
+	}
+	if len(regions) != 2 {
+		t.Fatalf("expected 2 regions, got %d: %+v", len(regions), regions)
+	}
+
+	if regions[0].Kind != document.RegionBody || !strings.HasPrefix(regions[0].Text, "Main text line 1") {
+		t.Errorf("expected main text first, got %s %q", regions[0].Kind, regions[0].Text)
+	}
+	if regions[1].Kind != document.RegionSidebar || regions[1].Text != "Margin note 1 here\nMargin note 2 here\nMargin note 3 here\nMargin note 4 here" {
+		t.Errorf("expected sidebar notes last, got %s %q", regions[1].Kind, regions[1].Text)
+	}
+}
+
+func TestPDFPage_Layout_TableRowsStayTogether(t *testing.T) {
+	var b strings.Builder
+	for i, row := range [][]string{{"Name", "Qty"}, {"bolts", "40"}, {"nuts", "25"}} {
+		y := 700 - float64(i)*12
+		b.WriteString(showText(72, y, 10, row[0]))
+		b.WriteString(showText(150, y, 10, row[1]))
+	}
+
+	text, err := openLayoutPage(t, b.String()).ExtractText(document.TextReading)
+	if err != nil {
+		t.Fatalf("ExtractText failed: %v", err)
+	}
+
+	if want := "Name Qty\nbolts 40\nnuts 25"; text != want {
+		t.Errorf("ExtractText = %q, want %q", text, want)
+	}
+}
+
+func TestPDFPage_Layout_CheatSheet(t *testing.T) {
+	page := extractPDFPage(t, 1)
+
+	regions, err := page.Layout()
+	if err != nil {
+		t.Fatalf("Layout failed: %v", err)
+	}
+	if len(regions) < 2 {
+		t.Errorf("expected multiple regions on the three-column cheat sheet, got %d", len(regions))
+	}
+}
@@ -1,10 +1,22 @@
 package document_test
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
 	"errors"
+	"fmt"
+	stdimage "image"
+	"image/jpeg"
+	"image/png"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
 	"sync"
 	"testing"
 
@@ -316,6 +328,56 @@ func (m *mockCache) entryCount() int {
 	return len(m.entries)
 }
 
+// fakeRenderer implements image.Renderer without external binaries, writing a
+// fixed payload so cache behavior can be tested when ImageMagick is unavailable.
+type fakeRenderer struct {
+	settings config.ImageConfig
+	renders  int
+	mu       sync.Mutex
+}
+
+func newFakeRenderer() *fakeRenderer {
+	cfg := config.ImageConfig{Format: "png", DPI: 150}
+	cfg.Finalize()
+	return &fakeRenderer{settings: cfg}
+}
+
+func (r *fakeRenderer) Render(inputPath string, pageNum int, outputPath string) error {
+	r.mu.Lock()
+	r.renders++
+	r.mu.Unlock()
+	return os.WriteFile(outputPath, []byte(fmt.Sprintf("page-%d", pageNum)), 0644)
+}
+
+func (r *fakeRenderer) FileExtension() string { return r.settings.Format }
+
+func (r *fakeRenderer) Settings() config.ImageConfig { return r.settings }
+
+func (r *fakeRenderer) Parameters() []string { return []string{"renderer=fake"} }
+
+func (r *fakeRenderer) renderCount() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.renders
+}
+
+// copyTestPDF copies the test PDF into a temporary directory under name.
+func copyTestPDF(t *testing.T, dir, name string) string {
+	t.Helper()
+
+	data, err := os.ReadFile(testPDFPath(t))
+	if err != nil {
+		t.Fatalf("Failed to read test PDF: %v", err)
+	}
+
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("Failed to write PDF copy: %v", err)
+	}
+
+	return path
+}
+
 func TestPDFPage_ToImage_CacheMiss(t *testing.T) {
 	requireImageMagick(t)
 
@@ -950,3 +1012,755 @@ func TestPDFPage_ToImage_CacheEntry_Filename(t *testing.T) {
 		t.Logf("Cache entry: key=%s, filename=%s, size=%d bytes", entry.Key, entry.Filename, len(entry.Data))
 	}
 }
+
+func TestPDFDocument_Fingerprint_Modes(t *testing.T) {
+	path := testPDFPath(t)
+
+	tests := []struct {
+		name   string
+		mode   config.FingerprintMode
+		prefix string
+	}{
+		{"default content mode", "", "sha256:"},
+		{"content mode", config.FingerprintContent, "sha256:"},
+		{"fast mode", config.FingerprintFast, "fast:"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := document.OpenPDFWithConfig(path, config.DocumentConfig{Fingerprint: tt.mode})
+			if err != nil {
+				t.Fatalf("OpenPDFWithConfig failed: %v", err)
+			}
+			defer doc.Close()
+
+			fp := doc.Fingerprint()
+			if !strings.HasPrefix(fp, tt.prefix) {
+				t.Errorf("Expected fingerprint prefix %q, got %q", tt.prefix, fp)
+			}
+		})
+	}
+}
+
+func TestPDFDocument_Fingerprint_ContentHash(t *testing.T) {
+	path := testPDFPath(t)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read test PDF: %v", err)
+	}
+	sum := sha256.Sum256(data)
+
+	doc, err := document.OpenPDF(path)
+	if err != nil {
+		t.Fatalf("OpenPDF failed: %v", err)
+	}
+	defer doc.Close()
+
+	if want := "sha256:" + hex.EncodeToString(sum[:]); doc.Fingerprint() != want {
+		t.Errorf("expected fingerprint %s, got %s", want, doc.Fingerprint())
+	}
+}
+
+func TestOpenPDFWithConfig_InvalidFingerprint(t *testing.T) {
+	_, err := document.OpenPDFWithConfig(testPDFPath(t), config.DocumentConfig{Fingerprint: "md5"})
+	if err == nil {
+		t.Error("Expected error for unsupported fingerprint mode")
+	}
+}
+
+func TestPDFDocument_Fingerprint_PathIndependent(t *testing.T) {
+	dir := t.TempDir()
+	pathA := copyTestPDF(t, dir, "a.pdf")
+	pathB := copyTestPDF(t, dir, "b.pdf")
+
+	docA, err := document.OpenPDF(pathA)
+	if err != nil {
+		t.Fatalf("OpenPDF failed: %v", err)
+	}
+	defer docA.Close()
+
+	docB, err := document.OpenPDF(pathB)
+	if err != nil {
+		t.Fatalf("OpenPDF failed: %v", err)
+	}
+	defer docB.Close()
+
+	if docA.Fingerprint() != docB.Fingerprint() {
+		t.Errorf("Expected identical fingerprints for identical content\nA: %s\nB: %s", docA.Fingerprint(), docB.Fingerprint())
+	}
+}
+
+func TestPDFDocument_Fingerprint_ContentChange(t *testing.T) {
+	dir := t.TempDir()
+	path := copyTestPDF(t, dir, "doc.pdf")
+
+	original, err := document.OpenPDF(path)
+	if err != nil {
+		t.Fatalf("OpenPDF failed: %v", err)
+	}
+	original.Close()
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatalf("Failed to open PDF for append: %v", err)
+	}
+	if _, err := f.WriteString("\n% revised\n"); err != nil {
+		t.Fatalf("Failed to modify PDF: %v", err)
+	}
+	f.Close()
+
+	revised, err := document.OpenPDF(path)
+	if err != nil {
+		t.Fatalf("OpenPDF of revised document failed: %v", err)
+	}
+	defer revised.Close()
+
+	if original.Fingerprint() == revised.Fingerprint() {
+		t.Error("Expected fingerprint to change when document content changes")
+	}
+}
+
+func TestPDFPage_ToImage_CacheKey_ContentAddressed(t *testing.T) {
+	dir := t.TempDir()
+	pathA := copyTestPDF(t, dir, "a.pdf")
+	pathB := copyTestPDF(t, dir, "b.pdf")
+
+	renderer := newFakeRenderer()
+	mockCache := newMockCache()
+
+	for _, path := range []string{pathA, pathB} {
+		doc, err := document.OpenPDF(path)
+		if err != nil {
+			t.Fatalf("OpenPDF failed: %v", err)
+		}
+
+		page, err := doc.ExtractPage(1)
+		if err != nil {
+			t.Fatalf("ExtractPage failed: %v", err)
+		}
+
+		if _, err := page.ToImage(renderer, mockCache); err != nil {
+			t.Fatalf("ToImage failed: %v", err)
+		}
+		doc.Close()
+	}
+
+	if mockCache.entryCount() != 1 {
+		t.Errorf("Expected 1 cache entry for identical content at two paths, got %d", mockCache.entryCount())
+	}
+
+	if renderer.renderCount() != 1 {
+		t.Errorf("Expected 1 render for identical content, got %d", renderer.renderCount())
+	}
+}
+
+func TestPDFPage_ImageCacheKey(t *testing.T) {
+	page := extractPDFPage(t, 1)
+	renderer := newFakeRenderer()
+	mockCache := newMockCache()
+
+	key, err := page.ImageCacheKey(renderer)
+	if err != nil {
+		t.Fatalf("ImageCacheKey failed: %v", err)
+	}
+
+	if _, err := page.ToImage(renderer, mockCache); err != nil {
+		t.Fatalf("ToImage failed: %v", err)
+	}
+
+	if !mockCache.hasKey(key) {
+		t.Error("Expected ToImage to store the image under ImageCacheKey")
+	}
+}
+
+func TestPDFPage_ImageCacheKey_ModernFormats(t *testing.T) {
+	page := extractPDFPage(t, 1)
+
+	configs := []config.ImageConfig{
+		{Format: "png", DPI: 150},
+		{Format: "webp", Quality: 80, DPI: 150},
+		{Format: "webp", Quality: 80, Lossless: true, DPI: 150},
+		{Format: "avif", Quality: 80, DPI: 150},
+	}
+
+	keys := make(map[string]string)
+	for _, cfg := range configs {
+		renderer := newFakeRenderer()
+		renderer.settings = cfg
+
+		key, err := page.ImageCacheKey(renderer)
+		if err != nil {
+			t.Fatalf("ImageCacheKey failed: %v", err)
+		}
+
+		name := fmt.Sprintf("%s lossless=%t", cfg.Format, cfg.Lossless)
+		if other, ok := keys[key]; ok {
+			t.Errorf("%s shares a cache key with %s", name, other)
+		}
+		keys[key] = name
+	}
+}
+
+// fitRenderer is a fakeRenderer implementing image.FitRenderer that records
+// the DPI and input document of each render.
+type fitRenderer struct {
+	*fakeRenderer
+	dpis   []float64
+	inputs [][]byte
+}
+
+func (r *fitRenderer) FitDPI(width, height float64) float64 {
+	return image.FitDPI(r.settings, width, height)
+}
+
+func (r *fitRenderer) RenderAt(inputPath string, pageNum int, outputPath string, dpi float64) error {
+	input, err := os.ReadFile(inputPath)
+	if err != nil {
+		return err
+	}
+	r.dpis = append(r.dpis, dpi)
+	r.inputs = append(r.inputs, input)
+	return r.Render(inputPath, pageNum, outputPath)
+}
+
+var mediaBoxPattern = regexp.MustCompile(`/MediaBox \[0 0 ([0-9.]+) ([0-9.]+)\]`)
+
+// renderedLongEdge returns the long edge, in pixels, of the single-page PDF
+// rendered by r, from its media box and the DPI it was rendered at.
+func renderedLongEdge(t *testing.T, r *fitRenderer) float64 {
+	t.Helper()
+
+	if len(r.inputs) != 1 {
+		t.Fatalf("expected one render, got %d", len(r.inputs))
+	}
+	m := mediaBoxPattern.FindSubmatch(r.inputs[0])
+	if m == nil {
+		t.Fatal("rendered document has no media box")
+	}
+	width, _ := strconv.ParseFloat(string(m[1]), 64)
+	height, _ := strconv.ParseFloat(string(m[2]), 64)
+	return max(width, height) * r.dpis[0] / 72
+}
+
+func TestPDFPage_ToImage_FitLimits(t *testing.T) {
+	page := extractPDFPage(t, 1)
+
+	renderer := &fitRenderer{fakeRenderer: newFakeRenderer()}
+	renderer.settings.MaxLongEdge = 1568
+	mockCache := newMockCache()
+
+	if _, err := page.ToImage(renderer, mockCache); err != nil {
+		t.Fatalf("ToImage failed: %v", err)
+	}
+
+	// The test PDF is A4 landscape (841.89 x 595.276 pt).
+	if len(renderer.dpis) != 1 || renderer.dpis[0] != 134.09 {
+		t.Fatalf("expected one render at 134.09 DPI, got %v", renderer.dpis)
+	}
+
+	fitted, err := page.ImageCacheKey(renderer)
+	if err != nil {
+		t.Fatalf("ImageCacheKey failed: %v", err)
+	}
+	if !mockCache.hasKey(fitted) {
+		t.Error("expected ToImage to store the image under the fitted cache key")
+	}
+
+	fixed, err := page.ImageCacheKey(newFakeRenderer())
+	if err != nil {
+		t.Fatalf("ImageCacheKey failed: %v", err)
+	}
+	if fitted == fixed {
+		t.Error("expected fitted DPI to produce a different cache key")
+	}
+
+	renderer.settings.MaxLongEdge = 1000
+	smaller, err := page.ImageCacheKey(renderer)
+	if err != nil {
+		t.Fatalf("ImageCacheKey failed: %v", err)
+	}
+	if smaller == fitted {
+		t.Error("expected different fit limits to produce different cache keys")
+	}
+}
+
+func TestPDFPage_ToImage_FitLimitsUnsupported(t *testing.T) {
+	page := extractPDFPage(t, 1)
+
+	renderer := newFakeRenderer()
+	renderer.settings.MaxWidth = 1000
+
+	if _, err := page.ToImage(renderer, nil); err == nil || !strings.Contains(err.Error(), "image.FitRenderer") {
+		t.Errorf("expected error for renderer without fit support, got %v", err)
+	}
+	if renderer.renderCount() != 0 {
+		t.Errorf("expected no render at the configured DPI, got %d", renderer.renderCount())
+	}
+}
+
+// budgetRenderer is a fakeRenderer implementing image.FitRenderer and
+// image.EncodingRenderer whose output size grows with pixel count and, for
+// lossy formats, quality. It records the encoding of each render.
+type budgetRenderer struct {
+	*fakeRenderer
+	encodings []image.Encoding
+}
+
+func newBudgetRenderer(format string, quality, maxBytes int) *budgetRenderer {
+	renderer := &budgetRenderer{fakeRenderer: newFakeRenderer()}
+	renderer.settings.Format = format
+	renderer.settings.Quality = quality
+	renderer.settings.MaxBytes = maxBytes
+	return renderer
+}
+
+func (r *budgetRenderer) Render(inputPath string, pageNum int, outputPath string) error {
+	return r.RenderAt(inputPath, pageNum, outputPath, float64(r.settings.DPI))
+}
+
+func (r *budgetRenderer) FitDPI(width, height float64) float64 {
+	return image.FitDPI(r.settings, width, height)
+}
+
+func (r *budgetRenderer) RenderAt(inputPath string, pageNum int, outputPath string, dpi float64) error {
+	return r.RenderWith(inputPath, pageNum, outputPath, image.Encoding{DPI: dpi, Quality: r.settings.Quality})
+}
+
+func (r *budgetRenderer) RenderWith(inputPath string, pageNum int, outputPath string, enc image.Encoding) error {
+	r.encodings = append(r.encodings, enc)
+
+	size := enc.DPI * enc.DPI
+	if image.IsLossy(r.settings) {
+		size = size * float64(enc.Quality) / 100
+	}
+	return os.WriteFile(outputPath, make([]byte, int(size)), 0644)
+}
+
+func TestPDFPage_ToBudgetImage(t *testing.T) {
+	tests := []struct {
+		name     string
+		renderer *budgetRenderer
+		want     []image.Encoding
+	}{
+		{
+			name:     "within budget",
+			renderer: newBudgetRenderer("jpg", 90, 1<<20),
+			want:     []image.Encoding{{DPI: 150, Quality: 90}},
+		},
+		{
+			name:     "lowers quality",
+			renderer: newBudgetRenderer("jpg", 90, 15000),
+			want: []image.Encoding{
+				{DPI: 150, Quality: 90},
+				{DPI: 150, Quality: 80},
+				{DPI: 150, Quality: 70},
+				{DPI: 150, Quality: 60},
+			},
+		},
+		{
+			name:     "lowers resolution for lossless formats",
+			renderer: newBudgetRenderer("png", 0, 10000),
+			want: []image.Encoding{
+				{DPI: 150, Quality: 0},
+				{DPI: 95, Quality: 0},
+			},
+		},
+		{
+			name:     "lowers quality then resolution",
+			renderer: newBudgetRenderer("webp", 50, 5000),
+			want: []image.Encoding{
+				{DPI: 150, Quality: 50},
+				{DPI: 150, Quality: 40},
+				{DPI: 106.21, Quality: 40},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page := extractPDFPage(t, 1)
+
+			img, err := page.ToBudgetImage(tt.renderer, nil)
+			if err != nil {
+				t.Fatalf("ToBudgetImage failed: %v", err)
+			}
+
+			if fmt.Sprint(tt.renderer.encodings) != fmt.Sprint(tt.want) {
+				t.Errorf("expected renders %v, got %v", tt.want, tt.renderer.encodings)
+			}
+			if img.Encoding != tt.want[len(tt.want)-1] {
+				t.Errorf("expected encoding %v, got %v", tt.want[len(tt.want)-1], img.Encoding)
+			}
+			if len(img.Data) > tt.renderer.settings.MaxBytes {
+				t.Errorf("image of %d bytes exceeds budget of %d", len(img.Data), tt.renderer.settings.MaxBytes)
+			}
+		})
+	}
+}
+
+func TestPDFPage_ToBudgetImage_ExceedsBudget(t *testing.T) {
+	page := extractPDFPage(t, 1)
+
+	renderer := newBudgetRenderer("jpg", 90, 100)
+
+	_, err := page.ToBudgetImage(renderer, nil)
+	if !errors.Is(err, document.ErrByteBudget) {
+		t.Fatalf("expected ErrByteBudget, got %v", err)
+	}
+
+	last := renderer.encodings[len(renderer.encodings)-1]
+	if last.Quality != 40 || last.DPI < 36 {
+		t.Errorf("expected search to stop at quality 40 and at least 36 DPI, got %v", last)
+	}
+}
+
+func TestPDFPage_ToBudgetImage_Cache(t *testing.T) {
+	page := extractPDFPage(t, 1)
+
+	renderer := newBudgetRenderer("jpg", 90, 15000)
+	mockCache := newMockCache()
+
+	first, err := page.ToBudgetImage(renderer, mockCache)
+	if err != nil {
+		t.Fatalf("ToBudgetImage failed: %v", err)
+	}
+
+	key, err := page.ImageCacheKey(renderer)
+	if err != nil {
+		t.Fatalf("ImageCacheKey failed: %v", err)
+	}
+	entry, err := mockCache.Get(key)
+	if err != nil {
+		t.Fatalf("expected image cached under the budget key: %v", err)
+	}
+	if entry.Filename != "vim-cheatsheet.1.q60-dpi150.jpg" {
+		t.Errorf("expected filename to record the encoding, got %q", entry.Filename)
+	}
+
+	renders := len(renderer.encodings)
+	second, err := page.ToBudgetImage(renderer, mockCache)
+	if err != nil {
+		t.Fatalf("ToBudgetImage failed: %v", err)
+	}
+	if len(renderer.encodings) != renders {
+		t.Error("expected cache hit without rendering")
+	}
+	if second.Encoding != first.Encoding || len(second.Data) != len(first.Data) {
+		t.Errorf("expected cached %v, got %v", first.Encoding, second.Encoding)
+	}
+
+	unbudgeted := newBudgetRenderer("jpg", 90, 0)
+	other, err := page.ImageCacheKey(unbudgeted)
+	if err != nil {
+		t.Fatalf("ImageCacheKey failed: %v", err)
+	}
+	if other == key {
+		t.Error("expected the byte budget to produce a different cache key")
+	}
+}
+
+func TestPDFPage_ToBudgetImage_NoBudget(t *testing.T) {
+	page := extractPDFPage(t, 1)
+
+	renderer := newFakeRenderer()
+	mockCache := newMockCache()
+
+	for range 2 {
+		img, err := page.ToBudgetImage(renderer, mockCache)
+		if err != nil {
+			t.Fatalf("ToBudgetImage failed: %v", err)
+		}
+		if img.Encoding != (image.Encoding{DPI: 150, Quality: renderer.settings.Quality}) {
+			t.Errorf("expected configured encoding, got %v", img.Encoding)
+		}
+	}
+
+	if renderer.renderCount() != 1 {
+		t.Errorf("expected 1 render, got %d", renderer.renderCount())
+	}
+}
+
+func TestPDFPage_ToImage_BudgetUnsupported(t *testing.T) {
+	page := extractPDFPage(t, 1)
+
+	renderer := newFakeRenderer()
+	renderer.settings.Format = "jpg"
+	renderer.settings.Quality = 90
+	renderer.settings.MaxBytes = 1
+
+	if _, err := page.ToImage(renderer, nil); err == nil || !strings.Contains(err.Error(), "image.EncodingRenderer") {
+		t.Errorf("expected error for renderer without encoding support, got %v", err)
+	}
+}
+
+// streamRenderer is a fakeRenderer implementing image.StreamRenderer that
+// records the input and encoding of each stream.
+type streamRenderer struct {
+	*fakeRenderer
+	inputs  []string
+	streams []image.Encoding
+}
+
+func (r *streamRenderer) RenderTo(inputPath string, pageNum int, w io.Writer, enc image.Encoding) error {
+	r.inputs = append(r.inputs, inputPath)
+	r.streams = append(r.streams, enc)
+	_, err := fmt.Fprintf(w, "page-%d", pageNum)
+	return err
+}
+
+// failingWriter is an io.Writer that always fails.
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestPDFPage_WriteImage(t *testing.T) {
+	page := extractPDFPage(t, 1)
+
+	renderer := &streamRenderer{fakeRenderer: newFakeRenderer()}
+
+	var buf bytes.Buffer
+	if err := page.WriteImage(&buf, renderer, nil); err != nil {
+		t.Fatalf("WriteImage failed: %v", err)
+	}
+
+	if buf.String() != "page-1" {
+		t.Errorf("unexpected image data %q", buf.String())
+	}
+	if len(renderer.streams) != 1 || renderer.streams[0] != (image.Encoding{DPI: 150, Quality: renderer.settings.Quality}) {
+		t.Errorf("expected one stream at the configured encoding, got %v", renderer.streams)
+	}
+	if renderer.renderCount() != 0 {
+		t.Errorf("expected no file render, got %d", renderer.renderCount())
+	}
+}
+
+func TestPDFPage_WriteImage_Cache(t *testing.T) {
+	page := extractPDFPage(t, 1)
+
+	renderer := &streamRenderer{fakeRenderer: newFakeRenderer()}
+	mockCache := newMockCache()
+
+	for range 2 {
+		var buf bytes.Buffer
+		if err := page.WriteImage(&buf, renderer, mockCache); err != nil {
+			t.Fatalf("WriteImage failed: %v", err)
+		}
+		if buf.String() != "page-1" {
+			t.Errorf("unexpected image data %q", buf.String())
+		}
+	}
+
+	if len(renderer.streams) != 1 {
+		t.Errorf("expected one render followed by a cache hit, got %d renders", len(renderer.streams))
+	}
+
+	key, err := page.ImageCacheKey(renderer)
+	if err != nil {
+		t.Fatalf("ImageCacheKey failed: %v", err)
+	}
+	if !mockCache.hasKey(key) {
+		t.Error("expected WriteImage to cache the image")
+	}
+}
+
+func TestPDFPage_WriteImage_Fallback(t *testing.T) {
+	page := extractPDFPage(t, 1)
+
+	renderer := newFakeRenderer()
+
+	var buf bytes.Buffer
+	if err := page.WriteImage(&buf, renderer, nil); err != nil {
+		t.Fatalf("WriteImage failed: %v", err)
+	}
+
+	if buf.String() != "page-1" {
+		t.Errorf("unexpected image data %q", buf.String())
+	}
+	if renderer.renderCount() != 1 {
+		t.Errorf("expected one file render, got %d", renderer.renderCount())
+	}
+}
+
+func TestPDFPage_WriteImage_WriterError(t *testing.T) {
+	page := extractPDFPage(t, 1)
+
+	renderers := map[string]image.Renderer{
+		"stream":   &streamRenderer{fakeRenderer: newFakeRenderer()},
+		"fallback": newFakeRenderer(),
+	}
+
+	for name, renderer := range renderers {
+		t.Run(name, func(t *testing.T) {
+			err := page.WriteImage(failingWriter{}, renderer, nil)
+			if err == nil || !strings.Contains(err.Error(), "disk full") {
+				t.Errorf("expected writer error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestPDFPage_ToImage_StreamRenderer(t *testing.T) {
+	page := extractPDFPage(t, 1)
+
+	renderer := &streamRenderer{fakeRenderer: newFakeRenderer()}
+
+	data, err := page.ToImage(renderer, nil)
+	if err != nil {
+		t.Fatalf("ToImage failed: %v", err)
+	}
+
+	if string(data) != "page-1" {
+		t.Errorf("unexpected image data %q", data)
+	}
+	if len(renderer.streams) != 1 {
+		t.Errorf("expected one stream, got %d", len(renderer.streams))
+	}
+	if renderer.renderCount() != 0 {
+		t.Errorf("expected no temporary file render, got %d", renderer.renderCount())
+	}
+}
+
+// dataRenderer is a fakeRenderer that writes fixed image data.
+type dataRenderer struct {
+	*fakeRenderer
+	data []byte
+}
+
+func (r *dataRenderer) Render(inputPath string, pageNum int, outputPath string) error {
+	r.fakeRenderer.Render(inputPath, pageNum, outputPath)
+	return os.WriteFile(outputPath, r.data, 0644)
+}
+
+func encodePNG(t *testing.T, width, height int) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, stdimage.NewGray(stdimage.Rect(0, 0, width, height))); err != nil {
+		t.Fatalf("Failed to encode PNG: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestPDFPage_Render(t *testing.T) {
+	page := extractPDFPage(t, 1)
+
+	renderer := &dataRenderer{fakeRenderer: newFakeRenderer(), data: encodePNG(t, 40, 30)}
+	mockCache := newMockCache()
+
+	key, err := page.ImageCacheKey(renderer)
+	if err != nil {
+		t.Fatalf("ImageCacheKey failed: %v", err)
+	}
+
+	for i, wantHit := range []bool{false, true} {
+		result, err := page.Render(renderer, mockCache)
+		if err != nil {
+			t.Fatalf("Render %d failed: %v", i+1, err)
+		}
+
+		if result.CacheHit != wantHit {
+			t.Errorf("Render %d: expected CacheHit %t, got %t", i+1, wantHit, result.CacheHit)
+		}
+		if !bytes.Equal(result.Data, renderer.data) || result.Size != len(renderer.data) {
+			t.Errorf("Render %d: unexpected data (%d bytes, Size %d)", i+1, len(result.Data), result.Size)
+		}
+		if result.Format != document.PNG || result.MimeType != "image/png" {
+			t.Errorf("Render %d: expected png image/png, got %s %s", i+1, result.Format, result.MimeType)
+		}
+		if result.Width != 40 || result.Height != 30 {
+			t.Errorf("Render %d: expected 40x30, got %dx%d", i+1, result.Width, result.Height)
+		}
+		if result.Encoding != (image.Encoding{DPI: 150, Quality: renderer.settings.Quality}) {
+			t.Errorf("Render %d: expected configured encoding, got %v", i+1, result.Encoding)
+		}
+		if result.CacheKey != key {
+			t.Errorf("Render %d: expected cache key %s, got %s", i+1, key, result.CacheKey)
+		}
+		if fmt.Sprint(result.Parameters) != "[renderer=fake]" {
+			t.Errorf("Render %d: unexpected parameters %v", i+1, result.Parameters)
+		}
+		if result.Duration < 0 {
+			t.Errorf("Render %d: negative duration %v", i+1, result.Duration)
+		}
+	}
+
+	if renderer.renderCount() != 1 {
+		t.Errorf("expected 1 render, got %d", renderer.renderCount())
+	}
+}
+
+func TestPDFPage_Render_Formats(t *testing.T) {
+	var jpegData bytes.Buffer
+	if err := jpeg.Encode(&jpegData, stdimage.NewGray(stdimage.Rect(0, 0, 40, 30)), nil); err != nil {
+		t.Fatalf("Failed to encode JPEG: %v", err)
+	}
+
+	// Lossless WebP: RIFF header, VP8L chunk with signature 0x2f and
+	// 14-bit width-1 and height-1.
+	webp := []byte("RIFF\x12\x00\x00\x00WEBPVP8L\x05\x00\x00\x00\x2f")
+	webp = binary.LittleEndian.AppendUint32(webp, (40-1)|(30-1)<<14)
+	webp = append(webp, 0)
+
+	// AVIF: 'ftyp' box followed by an 'ispe' property with 32-bit width
+	// and height.
+	avif := []byte("\x00\x00\x00\x10ftypavif\x00\x00\x00\x00\x00\x00\x00\x14ispe\x00\x00\x00\x00")
+	avif = binary.BigEndian.AppendUint32(avif, 40)
+	avif = binary.BigEndian.AppendUint32(avif, 30)
+
+	tests := []struct {
+		format        string
+		data          []byte
+		wantFormat    document.ImageFormat
+		wantMime      string
+		width, height int
+	}{
+		{"png", encodePNG(t, 40, 30), document.PNG, "image/png", 40, 30},
+		{"jpeg", jpegData.Bytes(), document.JPEG, "image/jpeg", 40, 30},
+		{"webp", webp, document.WebP, "image/webp", 40, 30},
+		{"avif", avif, document.AVIF, "image/avif", 40, 30},
+		{"tiff", []byte("II*\x00"), document.ImageFormat("tiff"), "", 0, 0},
+	}
+
+	page := extractPDFPage(t, 1)
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			renderer := &dataRenderer{fakeRenderer: newFakeRenderer(), data: tt.data}
+			renderer.settings.Format = tt.format
+
+			result, err := page.Render(renderer, nil)
+			if err != nil {
+				t.Fatalf("Render failed: %v", err)
+			}
+
+			if result.Format != tt.wantFormat || result.MimeType != tt.wantMime {
+				t.Errorf("expected %s %q, got %s %q", tt.wantFormat, tt.wantMime, result.Format, result.MimeType)
+			}
+			if result.Width != tt.width || result.Height != tt.height {
+				t.Errorf("expected %dx%d, got %dx%d", tt.width, tt.height, result.Width, result.Height)
+			}
+		})
+	}
+}
+
+func TestPDFPage_Render_Budget(t *testing.T) {
+	page := extractPDFPage(t, 1)
+
+	renderer := newBudgetRenderer("jpg", 90, 15000)
+	mockCache := newMockCache()
+
+	if _, err := page.Render(renderer, mockCache); err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	result, err := page.Render(renderer, mockCache)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if !result.CacheHit || result.Encoding != (image.Encoding{DPI: 150, Quality: 60}) {
+		t.Errorf("expected cached quality 60 at 150 DPI, got hit=%t %v", result.CacheHit, result.Encoding)
+	}
+}
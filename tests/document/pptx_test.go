@@ -0,0 +1,263 @@
+package document_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/JaimeStill/document-context/pkg/document"
+)
+
+func sampleSlides() []pptxSlideXML {
+	table := `<p:graphicFrame><p:nvGraphicFramePr><p:cNvPr id="4" name="Table"/><p:cNvGraphicFramePr/><p:nvPr/></p:nvGraphicFramePr>` +
+		`<p:xfrm><a:off x="457200" y="3800000"/><a:ext cx="4000000" cy="740000"/></p:xfrm>` +
+		`<a:graphic><a:graphicData uri="http://schemas.openxmlformats.org/drawingml/2006/table"><a:tbl><a:tblGrid><a:gridCol w="2000000"/><a:gridCol w="2000000"/></a:tblGrid>` +
+		`<a:tr h="370000"><a:tc><a:txBody><a:bodyPr/>` + pptxPara("", "Metric") + `</a:txBody></a:tc><a:tc><a:txBody><a:bodyPr/>` + pptxPara("", "Value") + `</a:txBody></a:tc></a:tr>` +
+		`<a:tr h="370000"><a:tc><a:txBody><a:bodyPr/>` + pptxPara("", "Revenue") + `</a:txBody></a:tc><a:tc><a:txBody><a:bodyPr/>` + pptxPara("", "$4.2M") + `</a:txBody></a:tc></a:tr>` +
+		`</a:tbl></a:graphicData></a:graphic></p:graphicFrame>`
+
+	// The group maps its child coordinates to the bottom of the slide, so
+	// its text is read last.
+	group := `<p:grpSp><p:nvGrpSpPr><p:cNvPr id="6" name="Group"/><p:cNvGrpSpPr/><p:nvPr/></p:nvGrpSpPr>` +
+		`<p:grpSpPr><a:xfrm><a:off x="457200" y="5800000"/><a:ext cx="2000000" cy="400000"/><a:chOff x="0" y="0"/><a:chExt cx="1000000" cy="200000"/></a:xfrm></p:grpSpPr>` +
+		pptxShape("", pptxXfrm(0, 0, 1000000, 200000), pptxPara("", "Source: finance")) +
+		`</p:grpSp>`
+
+	picture := `<p:pic><p:nvPicPr><p:cNvPr id="7" name="Chart" descr="Revenue chart"/><p:cNvPicPr/><p:nvPr/></p:nvPicPr>` +
+		`<p:blipFill/><p:spPr>` + pptxXfrm(5000000, 3800000, 3000000, 2000000) + `</p:spPr></p:pic>`
+
+	return []pptxSlideXML{
+		{
+			shapes: pptxShape(`<p:ph type="ctrTitle"/>`, "", pptxPara("", "Quarterly Review")) +
+				pptxShape(`<p:ph type="subTitle" idx="1"/>`, pptxXfrm(1371600, 3886200, 6400800, 1752600), pptxPara("", "Q3 2026")),
+			notes: pptxPara("", "Welcome everyone."),
+		},
+		{
+			shapes: group + picture + table +
+				pptxShape("", pptxXfrm(457200, 4700000, 4000000, 800000),
+					pptxPara(`<a:pPr><a:buAutoNum type="arabicPeriod"/></a:pPr>`, "First")+
+						pptxPara(`<a:pPr><a:buAutoNum type="arabicPeriod"/></a:pPr>`, "Second")) +
+				pptxShape(`<p:ph idx="1"/>`, "",
+					pptxPara("", "Revenue up 12%")+
+						pptxPara(`<a:pPr lvl="1"/>`, "North region")+
+						pptxPara("", "Costs flat")) +
+				pptxShape(`<p:ph type="title"/>`, "", pptxPara("", "Results")),
+			notes: pptxPara("", "Mention the North region.") + pptxPara("", "Skip if short on time."),
+		},
+		{
+			shapes: pptxShape("", pptxXfrm(457200, 457200, 4000000, 800000), pptxPara("", "Appendix")),
+			attrs:  `show="0"`,
+		},
+	}
+}
+
+func openSamplePPTX(t *testing.T) *document.PPTXDocument {
+	t.Helper()
+
+	doc, err := document.OpenPPTX(writePPTX(t, sampleSlides()))
+	if err != nil {
+		t.Fatalf("OpenPPTX failed: %v", err)
+	}
+	t.Cleanup(func() { doc.Close() })
+	return doc
+}
+
+func slidePage(t *testing.T, doc document.Document, n int) *document.SlidePage {
+	t.Helper()
+
+	page, err := doc.ExtractPage(n)
+	if err != nil {
+		t.Fatalf("ExtractPage(%d) failed: %v", n, err)
+	}
+	return page.(*document.SlidePage)
+}
+
+func TestOpenPPTX_Slides(t *testing.T) {
+	doc := openSamplePPTX(t)
+
+	if doc.PageCount() != 3 {
+		t.Fatalf("expected 3 pages, got %d", doc.PageCount())
+	}
+
+	titles := []string{"Quarterly Review", "Results", ""}
+	for i, want := range titles {
+		got, err := slidePage(t, doc, i+1).Title()
+		if err != nil {
+			t.Fatalf("Title failed: %v", err)
+		}
+		if got != want {
+			t.Errorf("slide %d title = %q, want %q", i+1, got, want)
+		}
+	}
+
+	hidden, err := slidePage(t, doc, 3).Hidden()
+	if err != nil || !hidden {
+		t.Errorf("expected slide 3 hidden, got %v, %v", hidden, err)
+	}
+	if hidden, _ := slidePage(t, doc, 1).Hidden(); hidden {
+		t.Error("expected slide 1 visible")
+	}
+}
+
+func TestSlidePage_Text(t *testing.T) {
+	doc := openSamplePPTX(t)
+
+	text, err := slidePage(t, doc, 1).Text()
+	if err != nil {
+		t.Fatalf("Text failed: %v", err)
+	}
+	if text != "Quarterly Review\nQ3 2026" {
+		t.Errorf("slide 1 text = %q", text)
+	}
+
+	text, err = slidePage(t, doc, 2).Text()
+	if err != nil {
+		t.Fatalf("Text failed: %v", err)
+	}
+
+	want := "Results\n" +
+		"- Revenue up 12%\n" +
+		"  - North region\n" +
+		"- Costs flat\n" +
+		"Metric\tValue\n" +
+		"Revenue\t$4.2M\n" +
+		"1. First\n" +
+		"2. Second\n" +
+		"Source: finance"
+	if text != want {
+		t.Errorf("slide 2 text mismatch\n got: %q\nwant: %q", text, want)
+	}
+}
+
+func TestSlidePage_Notes(t *testing.T) {
+	doc := openSamplePPTX(t)
+
+	notes, err := slidePage(t, doc, 2).Notes()
+	if err != nil {
+		t.Fatalf("Notes failed: %v", err)
+	}
+	if notes != "Mention the North region.\nSkip if short on time." {
+		t.Errorf("notes = %q", notes)
+	}
+
+	notes, err = slidePage(t, doc, 3).Notes()
+	if err != nil || notes != "" {
+		t.Errorf("expected no notes on slide 3, got %q, %v", notes, err)
+	}
+}
+
+func TestSlidePage_Markdown(t *testing.T) {
+	doc := openSamplePPTX(t)
+
+	md, err := slidePage(t, doc, 2).Markdown()
+	if err != nil {
+		t.Fatalf("Markdown failed: %v", err)
+	}
+
+	want := "# Results\n\n" +
+		"- Revenue up 12%\n" +
+		"  - North region\n" +
+		"- Costs flat\n\n" +
+		"| Metric | Value |\n| --- | --- |\n| Revenue | $4.2M |\n\n" +
+		"1. First\n" +
+		"2. Second\n\n" +
+		"Source: finance\n\n" +
+		"**Notes:**\n\n" +
+		"Mention the North region.\n\n" +
+		"Skip if short on time."
+	if md != want {
+		t.Errorf("Markdown mismatch\n got: %q\nwant: %q", md, want)
+	}
+}
+
+func TestSlidePage_ToImage(t *testing.T) {
+	doc := openSamplePPTX(t)
+	page := slidePage(t, doc, 2)
+
+	renderer := &capturingRenderer{fakeRenderer: newFakeRenderer()}
+	c := newMockCache()
+
+	if _, err := page.ToImage(renderer, c); err != nil {
+		t.Fatalf("ToImage failed: %v", err)
+	}
+	if c.entryCount() != 1 {
+		t.Errorf("expected 1 cache entry, got %d", c.entryCount())
+	}
+
+	pdfPath := filepath.Join(t.TempDir(), "slide.pdf")
+	if err := os.WriteFile(pdfPath, renderer.input, 0644); err != nil {
+		t.Fatalf("Failed to write slide PDF: %v", err)
+	}
+	pdf, err := document.OpenPDF(pdfPath)
+	if err != nil {
+		t.Fatalf("rendered slide is not a valid PDF: %v", err)
+	}
+	defer pdf.Close()
+
+	pdfPage, err := pdf.ExtractPage(1)
+	if err != nil {
+		t.Fatalf("ExtractPage failed: %v", err)
+	}
+	text, err := pdfPage.(*document.PDFPage).Text()
+	if err != nil {
+		t.Fatalf("Text failed: %v", err)
+	}
+	for _, want := range []string{"Results", "Revenue up 12%", "North region", "$4.2M", "Source: finance"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("slide PDF text missing %q:\n%s", want, text)
+		}
+	}
+
+	if _, err := page.ToImage(renderer, c); err != nil {
+		t.Fatalf("ToImage (cached) failed: %v", err)
+	}
+	if renderer.renderCount() != 1 {
+		t.Errorf("expected cached image, rendered %d times", renderer.renderCount())
+	}
+}
+
+func TestSlidePage_Closed(t *testing.T) {
+	doc := openSamplePPTX(t)
+	page := slidePage(t, doc, 1)
+	doc.Close()
+
+	if _, err := page.Notes(); err == nil {
+		t.Error("expected error after Close")
+	}
+}
+
+func TestOpenPPTX_NoSlides(t *testing.T) {
+	if _, err := document.OpenPPTX(writePPTX(t, nil)); err == nil {
+		t.Error("expected error for presentation without slides")
+	}
+}
+
+func TestOpen_PPTX(t *testing.T) {
+	path := writePPTX(t, sampleSlides())
+
+	doc, err := document.Open(path, "application/vnd.openxmlformats-officedocument.presentationml.presentation")
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer doc.Close()
+
+	if _, ok := doc.(*document.PPTXDocument); !ok {
+		t.Errorf("expected *PPTXDocument, got %T", doc)
+	}
+}
+
+func TestSlidePage_ToImage_FitLimits(t *testing.T) {
+	page := slidePage(t, openSamplePPTX(t), 1)
+
+	renderer := &fitRenderer{fakeRenderer: newFakeRenderer()}
+	renderer.settings.MaxLongEdge = 1000
+
+	if _, err := page.ToImage(renderer, nil); err != nil {
+		t.Fatalf("ToImage failed: %v", err)
+	}
+
+	if edge := renderedLongEdge(t, renderer); edge > 1000 || edge < 999 {
+		t.Errorf("expected long edge fitted to 1000 px, got %.2f at %v DPI", edge, renderer.dpis[0])
+	}
+}
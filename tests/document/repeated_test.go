@@ -0,0 +1,206 @@
+package document_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/JaimeStill/document-context/pkg/document"
+)
+
+// reportPages returns content streams for a report with a letterhead, a body
+// line unique to each page, and a "Page N of M" footer.
+func reportPages(n int) []string {
+	topics := []string{"revenue", "staffing", "logistics"}
+
+	pages := make([]string, n)
+	for i := range pages {
+		pages[i] = showText(72, 750, 12, "ACME Corporation") +
+			showText(72, 600, 10, fmt.Sprintf("Findings on %s are summarized here.", topics[i])) +
+			showText(220, 40, 8, fmt.Sprintf("Confidential - Page %d of %d", i+1, n))
+	}
+	return pages
+}
+
+func openDocument(t *testing.T, pages ...string) document.Document {
+	t.Helper()
+
+	doc, err := document.OpenPDF(writeContentPDF(t, pages...))
+	if err != nil {
+		t.Fatalf("OpenPDF failed: %v", err)
+	}
+	t.Cleanup(func() { doc.Close() })
+	return doc
+}
+
+func TestFindRepeatedContent(t *testing.T) {
+	doc := openDocument(t, reportPages(3)...)
+
+	repeated, err := document.FindRepeatedContent(doc)
+	if err != nil {
+		t.Fatalf("FindRepeatedContent failed: %v", err)
+	}
+
+	if len(repeated.Lines) != 2 {
+		t.Fatalf("expected 2 repeated lines, got %d: %+v", len(repeated.Lines), repeated.Lines)
+	}
+
+	header, footer := repeated.Lines[0], repeated.Lines[1]
+
+	if header.Pattern != "ACME Corporation" || header.Kind != document.RegionHeader {
+		t.Errorf("unexpected header: %q %s", header.Pattern, header.Kind)
+	}
+	if footer.Pattern != "Confidential - Page # of #" || footer.Kind != document.RegionFooter {
+		t.Errorf("unexpected footer: %q %s", footer.Pattern, footer.Kind)
+	}
+
+	if pages := footer.Pages(); !reflect.DeepEqual(pages, []int{1, 2, 3}) {
+		t.Errorf("expected footer on pages [1 2 3], got %v", pages)
+	}
+	if got := footer.Occurrences[1].Text; got != "Confidential - Page 2 of 3" {
+		t.Errorf("expected original occurrence text, got %q", got)
+	}
+
+	bounds := header.Occurrences[0].Bounds
+	if bounds.X0 != 72 || bounds.Y0 >= 750 || bounds.Y1 <= 750 {
+		t.Errorf("unexpected header bounds: %+v", bounds)
+	}
+}
+
+func TestRepeatedContent_Text(t *testing.T) {
+	doc := openDocument(t, reportPages(2)...)
+
+	repeated, err := document.FindRepeatedContent(doc)
+	if err != nil {
+		t.Fatalf("FindRepeatedContent failed: %v", err)
+	}
+
+	page, err := doc.ExtractPage(2)
+	if err != nil {
+		t.Fatalf("ExtractPage failed: %v", err)
+	}
+
+	for _, mode := range []document.TextMode{document.TextStream, document.TextReading} {
+		text, err := repeated.Text(page.(document.LayoutPage), mode)
+		if err != nil {
+			t.Fatalf("Text(%s) failed: %v", mode, err)
+		}
+		if want := "Findings on staffing are summarized here."; text != want {
+			t.Errorf("Text(%s) = %q, want %q", mode, text, want)
+		}
+	}
+}
+
+func TestRepeatedContent_ContentBox(t *testing.T) {
+	doc := openDocument(t, reportPages(2)...)
+
+	repeated, err := document.FindRepeatedContent(doc)
+	if err != nil {
+		t.Fatalf("FindRepeatedContent failed: %v", err)
+	}
+
+	box := repeated.ContentBox(1)
+	if box.X0 != 0 || box.X1 != 612 {
+		t.Errorf("expected full page width, got %+v", box)
+	}
+	if box.Y1 >= 750 || box.Y1 < 740 {
+		t.Errorf("expected top edge just below the letterhead, got %+v", box)
+	}
+	if box.Y0 <= 40 || box.Y0 > 50 {
+		t.Errorf("expected bottom edge just above the footer, got %+v", box)
+	}
+
+	if got := repeated.ContentBox(9); got != (document.Rect{}) {
+		t.Errorf("expected empty box for unknown page, got %+v", got)
+	}
+}
+
+func TestFindRepeatedContent_AlternatingHeaders(t *testing.T) {
+	var pages []string
+	for i := range 4 {
+		header := "Chapter One"
+		if i%2 == 1 {
+			header = "A Field Guide"
+		}
+		pages = append(pages, showText(72, 750, 10, header)+
+			showText(72, 600, 10, fmt.Sprintf("Body text %c", 'a'+i)))
+	}
+
+	repeated, err := document.FindRepeatedContent(openDocument(t, pages...))
+	if err != nil {
+		t.Fatalf("FindRepeatedContent failed: %v", err)
+	}
+
+	if len(repeated.Lines) != 2 {
+		t.Fatalf("expected 2 repeated lines, got %d: %+v", len(repeated.Lines), repeated.Lines)
+	}
+	for _, line := range repeated.Lines {
+		if len(line.Pages()) != 2 {
+			t.Errorf("expected %q on 2 pages, got %v", line.Pattern, line.Pages())
+		}
+	}
+}
+
+func TestFindRepeatedContent_DifferentPositions(t *testing.T) {
+	doc := openDocument(t,
+		showText(72, 700, 10, "Summary"),
+		showText(72, 500, 10, "Summary"),
+	)
+
+	repeated, err := document.FindRepeatedContent(doc)
+	if err != nil {
+		t.Fatalf("FindRepeatedContent failed: %v", err)
+	}
+	if len(repeated.Lines) != 0 {
+		t.Errorf("expected no repeated lines, got %+v", repeated.Lines)
+	}
+}
+
+func TestFindRepeatedContent_BodyLines(t *testing.T) {
+	pages := reportPages(3)
+	for i := range pages {
+		pages[i] += showText(72, 700, 10, fmt.Sprintf("%d. Overview", i+1)) +
+			showText(72, 150, 10, "Notes")
+	}
+	doc := openDocument(t, pages...)
+
+	repeated, err := document.FindRepeatedContent(doc)
+	if err != nil {
+		t.Fatalf("FindRepeatedContent failed: %v", err)
+	}
+
+	if len(repeated.Lines) != 2 {
+		t.Fatalf("expected only the letterhead and footer, got %+v", repeated.Lines)
+	}
+	for _, line := range repeated.Lines {
+		if line.Pattern == "#. Overview" || line.Pattern == "Notes" {
+			t.Errorf("expected body line %q not to be repeated content", line.Pattern)
+		}
+	}
+
+	box := repeated.ContentBox(1)
+	if box.Y1 >= 750 || box.Y1 < 740 {
+		t.Errorf("expected top edge just below the letterhead, got %+v", box)
+	}
+	if box.Y0 <= 40 || box.Y0 > 50 {
+		t.Errorf("expected bottom edge just above the footer, got %+v", box)
+	}
+}
+
+func TestFindRepeatedContent_SinglePage(t *testing.T) {
+	repeated, err := document.FindRepeatedContent(openDocument(t, reportPages(1)...))
+	if err != nil {
+		t.Fatalf("FindRepeatedContent failed: %v", err)
+	}
+	if len(repeated.Lines) != 0 {
+		t.Errorf("expected no repeated lines for a single page, got %+v", repeated.Lines)
+	}
+}
+
+func TestFindRepeatedContent_Unsupported(t *testing.T) {
+	doc := newStubDocument(t, "alpha", "beta")
+
+	if _, err := document.FindRepeatedContent(doc); err == nil {
+		t.Error("expected error for pages without text line support")
+	}
+}
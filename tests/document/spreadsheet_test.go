@@ -0,0 +1,453 @@
+package document_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/JaimeStill/document-context/pkg/config"
+	"github.com/JaimeStill/document-context/pkg/document"
+)
+
+const xlsxSharedStrings = `<si><t>Region</t></si><si><t>Amount</t></si><si><t>Date</t></si>` +
+	`<si><r><t>So</t></r><r><t>uth</t></r><rPh><t>ignored</t></rPh></si>`
+
+const xlsxStyles = `<numFmts><numFmt numFmtId="164" formatCode="h:mm"/><numFmt numFmtId="165" formatCode="0.0&quot; days&quot;"/></numFmts>` +
+	`<cellXfs><xf numFmtId="0"/><xf numFmtId="14"/><xf numFmtId="164"/><xf numFmtId="165"/></cellXfs>`
+
+func sampleXLSXSheets() []xlsxSheet {
+	return []xlsxSheet{
+		{"Sales", `<row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c><c r="C1" t="s"><v>2</v></c></row>` +
+			`<row r="2"><c r="A2" t="inlineStr"><is><t>North</t></is></c><c r="B2"><v>1250.5</v></c><c r="C2" s="1"><v>45292</v></c></row>` +
+			`<row r="3"><c r="A3" t="s"><v>3</v></c><c r="B3" t="b"><v>1</v></c><c r="C3" s="2"><v>0.5</v></c></row>` +
+			`<row r="5"><c r="B5" s="3"><v>2.5</v></c><c r="D5" t="e"><v>#DIV/0!</v></c></row>`},
+		{"Notes", `<row><c t="str"><f>A1</f><v>computed</v></c><c/><c><v>7</v></c></row>`},
+	}
+}
+
+func openSampleXLSX(t *testing.T) *document.SpreadsheetDocument {
+	t.Helper()
+
+	doc, err := document.OpenXLSX(writeXLSX(t, sampleXLSXSheets(), xlsxSharedStrings, xlsxStyles))
+	if err != nil {
+		t.Fatalf("OpenXLSX failed: %v", err)
+	}
+	t.Cleanup(func() { doc.Close() })
+	return doc
+}
+
+func sheetPage(t *testing.T, doc document.Document, n int) *document.SheetPage {
+	t.Helper()
+
+	page, err := doc.ExtractPage(n)
+	if err != nil {
+		t.Fatalf("ExtractPage(%d) failed: %v", n, err)
+	}
+	return page.(*document.SheetPage)
+}
+
+// writeCSV writes content to a file with the given name and returns its path.
+func writeCSV(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write %s: %v", name, err)
+	}
+	return path
+}
+
+func TestOpenXLSX_Sheets(t *testing.T) {
+	doc := openSampleXLSX(t)
+
+	names := doc.SheetNames()
+	if len(names) != 2 || names[0] != "Sales" || names[1] != "Notes" {
+		t.Fatalf("unexpected sheet names %v", names)
+	}
+	if doc.PageCount() != 2 {
+		t.Fatalf("expected 2 pages, got %d", doc.PageCount())
+	}
+
+	if got := sheetPage(t, doc, 1).Sheet(); got != "Sales" {
+		t.Errorf("page 1 sheet = %q, want Sales", got)
+	}
+	if got := sheetPage(t, doc, 2).Sheet(); got != "Notes" {
+		t.Errorf("page 2 sheet = %q, want Notes", got)
+	}
+}
+
+func TestSheetPage_Text_CellValues(t *testing.T) {
+	doc := openSampleXLSX(t)
+
+	text, err := sheetPage(t, doc, 1).Text()
+	if err != nil {
+		t.Fatalf("Text failed: %v", err)
+	}
+
+	want := "Region\tAmount\tDate\n" +
+		"North\t1250.5\t2024-01-01\n" +
+		"South\tTRUE\t12:00:00\n" +
+		"\n" +
+		"\t2.5\t\t#DIV/0!"
+	if text != want {
+		t.Errorf("Text mismatch\n got: %q\nwant: %q", text, want)
+	}
+
+	text, err = sheetPage(t, doc, 2).Text()
+	if err != nil {
+		t.Fatalf("Text failed: %v", err)
+	}
+	if text != "computed\t\t7" {
+		t.Errorf("Notes text = %q", text)
+	}
+}
+
+func TestSheetPage_Range(t *testing.T) {
+	doc := openSampleXLSX(t)
+
+	if got := sheetPage(t, doc, 1).Range(); got != "A2:D5" {
+		t.Errorf("Range = %q, want A2:D5", got)
+	}
+}
+
+func TestSpreadsheet_Chunking(t *testing.T) {
+	path := writeCSV(t, "data.csv", "k,a,b\n1,x,y\n2,x,y\n3,x,y\n4,x,y\n5,x,y\n")
+
+	cfg := config.SpreadsheetConfig{MaxRows: 2, MaxColumns: 2}
+	doc, err := document.OpenCSVWithConfig(path, cfg)
+	if err != nil {
+		t.Fatalf("OpenCSVWithConfig failed: %v", err)
+	}
+	defer doc.Close()
+
+	if doc.PageCount() != 6 {
+		t.Fatalf("expected 6 pages, got %d", doc.PageCount())
+	}
+
+	ranges := []string{"A2:B3", "C2:C3", "A4:B5", "C4:C5", "A6:B6", "C6:C6"}
+	for i, want := range ranges {
+		page := sheetPage(t, doc, i+1)
+		if got := page.Range(); got != want {
+			t.Errorf("page %d range = %q, want %q", i+1, got, want)
+		}
+		if got := page.Sheet(); got != "data" {
+			t.Errorf("page %d sheet = %q, want data", i+1, got)
+		}
+	}
+
+	text, err := sheetPage(t, doc, 4).Text()
+	if err != nil {
+		t.Fatalf("Text failed: %v", err)
+	}
+	if text != "b\ny\ny" {
+		t.Errorf("page 4 text = %q, want header repeated", text)
+	}
+}
+
+func TestSpreadsheet_NoHeaderRow(t *testing.T) {
+	path := writeCSV(t, "data.csv", "1,2\n3,4\n5,6\n")
+
+	header := false
+	doc, err := document.OpenCSVWithConfig(path, config.SpreadsheetConfig{MaxRows: 2, HeaderRow: &header})
+	if err != nil {
+		t.Fatalf("OpenCSVWithConfig failed: %v", err)
+	}
+	defer doc.Close()
+
+	if doc.PageCount() != 2 {
+		t.Fatalf("expected 2 pages, got %d", doc.PageCount())
+	}
+
+	page := sheetPage(t, doc, 2)
+	if got := page.Range(); got != "A3:B3" {
+		t.Errorf("Range = %q, want A3:B3", got)
+	}
+	text, err := page.Text()
+	if err != nil {
+		t.Fatalf("Text failed: %v", err)
+	}
+	if text != "5\t6" {
+		t.Errorf("Text = %q, want 5\\t6", text)
+	}
+}
+
+func TestSheetPage_Markdown(t *testing.T) {
+	path := writeCSV(t, "data.csv", "\xef\xbb\xbfName,Note\nA|B,\"two\nlines\"\n")
+
+	doc, err := document.OpenCSV(path)
+	if err != nil {
+		t.Fatalf("OpenCSV failed: %v", err)
+	}
+	defer doc.Close()
+
+	md, err := sheetPage(t, doc, 1).Markdown()
+	if err != nil {
+		t.Fatalf("Markdown failed: %v", err)
+	}
+
+	want := "| Name | Note |\n| --- | --- |\n| A\\|B | two<br>lines |"
+	if md != want {
+		t.Errorf("Markdown mismatch\n got: %q\nwant: %q", md, want)
+	}
+}
+
+func TestSheetPage_CSV(t *testing.T) {
+	doc := openSampleXLSX(t)
+
+	out, err := sheetPage(t, doc, 2).CSV()
+	if err != nil {
+		t.Fatalf("CSV failed: %v", err)
+	}
+	if out != "computed,,7\n" {
+		t.Errorf("CSV = %q", out)
+	}
+}
+
+func TestOpenTSV(t *testing.T) {
+	path := writeCSV(t, "data.tsv", "a\tb, c\n1\t2\n")
+
+	doc, err := document.OpenTSV(path)
+	if err != nil {
+		t.Fatalf("OpenTSV failed: %v", err)
+	}
+	defer doc.Close()
+
+	text, err := sheetPage(t, doc, 1).Text()
+	if err != nil {
+		t.Fatalf("Text failed: %v", err)
+	}
+	if text != "a\tb, c\n1\t2" {
+		t.Errorf("Text = %q", text)
+	}
+}
+
+func TestSpreadsheet_EmptySheet(t *testing.T) {
+	path := writeCSV(t, "empty.csv", "")
+
+	doc, err := document.OpenCSV(path)
+	if err != nil {
+		t.Fatalf("OpenCSV failed: %v", err)
+	}
+	defer doc.Close()
+
+	if doc.PageCount() != 1 {
+		t.Fatalf("expected 1 page, got %d", doc.PageCount())
+	}
+
+	page := sheetPage(t, doc, 1)
+	if page.Range() != "" {
+		t.Errorf("expected empty range, got %q", page.Range())
+	}
+	if text, err := page.Text(); err != nil || text != "" {
+		t.Errorf("Text = %q, %v", text, err)
+	}
+}
+
+// capturingRenderer records the document passed to Render.
+type capturingRenderer struct {
+	*fakeRenderer
+	input []byte
+}
+
+func (r *capturingRenderer) Render(inputPath string, pageNum int, outputPath string) error {
+	data, err := os.ReadFile(inputPath)
+	if err != nil {
+		return err
+	}
+	r.input = data
+	return r.fakeRenderer.Render(inputPath, pageNum, outputPath)
+}
+
+func TestSheetPage_ToImage(t *testing.T) {
+	doc := openSampleXLSX(t)
+	page := sheetPage(t, doc, 1)
+
+	renderer := &capturingRenderer{fakeRenderer: newFakeRenderer()}
+	c := newMockCache()
+
+	data, err := page.ToImage(renderer, c)
+	if err != nil {
+		t.Fatalf("ToImage failed: %v", err)
+	}
+	if string(data) != "page-1" {
+		t.Errorf("unexpected image data %q", data)
+	}
+	if c.entryCount() != 1 {
+		t.Errorf("expected 1 cache entry, got %d", c.entryCount())
+	}
+
+	pdfPath := filepath.Join(t.TempDir(), "table.pdf")
+	if err := os.WriteFile(pdfPath, renderer.input, 0644); err != nil {
+		t.Fatalf("Failed to write table PDF: %v", err)
+	}
+	pdf, err := document.OpenPDF(pdfPath)
+	if err != nil {
+		t.Fatalf("rendered table is not a valid PDF: %v", err)
+	}
+	defer pdf.Close()
+
+	pdfPage, err := pdf.ExtractPage(1)
+	if err != nil {
+		t.Fatalf("ExtractPage failed: %v", err)
+	}
+	text, err := pdfPage.(*document.PDFPage).Text()
+	if err != nil {
+		t.Fatalf("Text failed: %v", err)
+	}
+	for _, want := range []string{"Sales (A2:D5)", "Region", "North", "2024-01-01", "#DIV/0!"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("table PDF text missing %q:\n%s", want, text)
+		}
+	}
+
+	if _, err := page.ToImage(renderer, c); err != nil {
+		t.Fatalf("ToImage (cached) failed: %v", err)
+	}
+	if renderer.renderCount() != 1 {
+		t.Errorf("expected cached image, rendered %d times", renderer.renderCount())
+	}
+}
+
+func TestSheetPage_Closed(t *testing.T) {
+	doc := openSampleXLSX(t)
+	page := sheetPage(t, doc, 1)
+	doc.Close()
+
+	if _, err := page.Text(); err == nil {
+		t.Error("expected error after Close")
+	}
+}
+
+func TestOpenXLSX_Invalid(t *testing.T) {
+	path := writeCSV(t, "broken.xlsx", "not a zip")
+
+	if _, err := document.OpenXLSX(path); err == nil {
+		t.Error("expected error for invalid workbook")
+	}
+}
+
+func TestOpen_Spreadsheets(t *testing.T) {
+	tests := []struct {
+		contentType string
+		path        string
+	}{
+		{"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", writeXLSX(t, sampleXLSXSheets(), xlsxSharedStrings, xlsxStyles)},
+		{"text/csv", writeCSV(t, "data.csv", "a,b\n1,2\n")},
+		{"text/tab-separated-values", writeCSV(t, "data.tsv", "a\tb\n1\t2\n")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.contentType, func(t *testing.T) {
+			doc, err := document.Open(tt.path, tt.contentType)
+			if err != nil {
+				t.Fatalf("Open failed: %v", err)
+			}
+			defer doc.Close()
+
+			if _, ok := doc.(*document.SpreadsheetDocument); !ok {
+				t.Errorf("expected *SpreadsheetDocument, got %T", doc)
+			}
+		})
+	}
+}
+
+func TestSheetPage_ToImage_FitLimits(t *testing.T) {
+	var csv strings.Builder
+	csv.WriteString("id,name,notes\n")
+	for i := range 500 {
+		fmt.Fprintf(&csv, "%d,item %d,%s\n", i, i, strings.Repeat("x", 40))
+	}
+
+	doc, err := document.Open(writeCSV(t, "long.csv", csv.String()), "text/csv")
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer doc.Close()
+	page := sheetPage(t, doc, 1)
+
+	renderer := &fitRenderer{fakeRenderer: newFakeRenderer()}
+	renderer.settings.MaxLongEdge = 1568
+
+	if _, err := page.ToImage(renderer, nil); err != nil {
+		t.Fatalf("ToImage failed: %v", err)
+	}
+
+	// The table page grows with the rows, far past 1568 px at 150 DPI.
+	if edge := renderedLongEdge(t, renderer); edge > 1568 || edge < 1567 {
+		t.Errorf("expected long edge fitted to 1568 px, got %.2f at %v DPI", edge, renderer.dpis[0])
+	}
+}
+
+func TestSheetPage_Render_ConfigCacheKey(t *testing.T) {
+	var csv strings.Builder
+	csv.WriteString("id,name\n")
+	for i := range 200 {
+		fmt.Fprintf(&csv, "%d,item %d\n", i, i)
+	}
+	path := writeCSV(t, "items.csv", csv.String())
+
+	noHeader := false
+	configs := map[string]config.SpreadsheetConfig{
+		"50 rows":   {MaxRows: 50},
+		"100 rows":  {MaxRows: 100},
+		"1 column":  {MaxRows: 50, MaxColumns: 1},
+		"no header": {MaxRows: 50, HeaderRow: &noHeader},
+	}
+
+	keys := make(map[string]string)
+	for name, cfg := range configs {
+		doc, err := document.OpenCSVWithConfig(path, cfg)
+		if err != nil {
+			t.Fatalf("OpenCSVWithConfig(%s) failed: %v", name, err)
+		}
+		result, err := sheetPage(t, doc, 2).Render(newFakeRenderer(), newMockCache())
+		doc.Close()
+		if err != nil {
+			t.Fatalf("Render(%s) failed: %v", name, err)
+		}
+
+		if other, ok := keys[result.CacheKey]; ok {
+			t.Errorf("%s shares a cache key with %s", name, other)
+		}
+		keys[result.CacheKey] = name
+	}
+}
+
+func TestSheetPage_ToImage_WinAnsiCharacters(t *testing.T) {
+	doc, err := document.Open(writeCSV(t, "prices.csv", "item,price\n“Deluxe” plan – annual…,€120\n‘Basic’ plan™,€10\n"), "text/csv")
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer doc.Close()
+
+	renderer := &capturingRenderer{fakeRenderer: newFakeRenderer()}
+	if _, err := sheetPage(t, doc, 1).ToImage(renderer, nil); err != nil {
+		t.Fatalf("ToImage failed: %v", err)
+	}
+
+	pdfPath := filepath.Join(t.TempDir(), "table.pdf")
+	if err := os.WriteFile(pdfPath, renderer.input, 0644); err != nil {
+		t.Fatalf("Failed to write table PDF: %v", err)
+	}
+	pdf, err := document.OpenPDF(pdfPath)
+	if err != nil {
+		t.Fatalf("rendered table is not a valid PDF: %v", err)
+	}
+	defer pdf.Close()
+
+	pdfPage, err := pdf.ExtractPage(1)
+	if err != nil {
+		t.Fatalf("ExtractPage failed: %v", err)
+	}
+	text, err := pdfPage.(*document.PDFPage).Text()
+	if err != nil {
+		t.Fatalf("Text failed: %v", err)
+	}
+	for _, want := range []string{"“Deluxe” plan – annual…", "€120", "‘Basic’ plan™"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("table PDF text missing %q:\n%s", want, text)
+		}
+	}
+}
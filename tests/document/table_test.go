@@ -0,0 +1,233 @@
+package document_test
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/JaimeStill/document-context/pkg/document"
+)
+
+func extractTables(t *testing.T, content string) []document.Table {
+	t.Helper()
+
+	doc, err := document.OpenPDF(writeContentPDF(t, content))
+	if err != nil {
+		t.Fatalf("OpenPDF failed: %v", err)
+	}
+	t.Cleanup(func() { doc.Close() })
+
+	page, err := doc.ExtractPage(1)
+	if err != nil {
+		t.Fatalf("ExtractPage failed: %v", err)
+	}
+
+	tables, err := page.(document.TablePage).Tables()
+	if err != nil {
+		t.Fatalf("Tables failed: %v", err)
+	}
+	return tables
+}
+
+// ruledGrid draws a stroked grid with the given column and row boundaries and
+// places each cell's text near its lower-left corner.
+func ruledGrid(xs, ys []float64, cells [][]string) string {
+	var b strings.Builder
+
+	for _, y := range ys {
+		fmt.Fprintf(&b, "%g %g m %g %g l S\n", xs[0], y, xs[len(xs)-1], y)
+	}
+	for _, x := range xs {
+		fmt.Fprintf(&b, "%g %g m %g %g l S\n", x, ys[0], x, ys[len(ys)-1])
+	}
+
+	for i, row := range cells {
+		for j, text := range row {
+			if text != "" {
+				b.WriteString(showText(xs[j]+4, ys[i+1]+6, 10, text))
+			}
+		}
+	}
+
+	return b.String()
+}
+
+func TestPDFPage_Tables_Ruled(t *testing.T) {
+	want := [][]string{
+		{"Quarter", "Revenue", "Margin"},
+		{"Q1", "1,200", "12%"},
+		{"Q2", "1,450", "14%"},
+	}
+
+	content := showText(72, 740, 12, "Quarterly results") +
+		ruledGrid([]float64{72, 200, 328, 456}, []float64{700, 680, 660, 640}, want)
+
+	tables := extractTables(t, content)
+	if len(tables) != 1 {
+		t.Fatalf("expected 1 table, got %d", len(tables))
+	}
+
+	table := tables[0]
+	if !table.Ruled {
+		t.Error("expected ruled table")
+	}
+	if got := table.Rows(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Rows() = %v, want %v", got, want)
+	}
+
+	bounds := document.Rect{X0: 72, Y0: 640, X1: 456, Y1: 700}
+	if table.Bounds != bounds {
+		t.Errorf("Bounds = %+v, want %+v", table.Bounds, bounds)
+	}
+	if cell := table.Cells[1][2].Bounds; cell != (document.Rect{X0: 328, Y0: 660, X1: 456, Y1: 680}) {
+		t.Errorf("unexpected cell bounds: %+v", cell)
+	}
+}
+
+func TestPDFPage_Tables_RuledRectangles(t *testing.T) {
+	var b strings.Builder
+	for i, y := range []float64{700, 680} {
+		for j, x := range []float64{72, 172} {
+			fmt.Fprintf(&b, "%g %g 100 20 re S\n", x, y-20)
+			b.WriteString(showText(x+4, y-14, 10, fmt.Sprintf("r%dc%d", i, j)))
+		}
+	}
+
+	tables := extractTables(t, b.String())
+	if len(tables) != 1 {
+		t.Fatalf("expected 1 table, got %d", len(tables))
+	}
+
+	want := [][]string{{"r0c0", "r0c1"}, {"r1c0", "r1c1"}}
+	if got := tables[0].Rows(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Rows() = %v, want %v", got, want)
+	}
+}
+
+func TestPDFPage_Tables_EmptyColumnsRemoved(t *testing.T) {
+	cells := [][]string{
+		{"Name", "", "Count"},
+		{"alpha", "", "3"},
+	}
+
+	tables := extractTables(t, ruledGrid([]float64{72, 172, 272, 372}, []float64{700, 680, 660}, cells))
+	if len(tables) != 1 {
+		t.Fatalf("expected 1 table, got %d", len(tables))
+	}
+
+	want := [][]string{{"Name", "Count"}, {"alpha", "3"}}
+	if got := tables[0].Rows(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Rows() = %v, want %v", got, want)
+	}
+}
+
+func TestPDFPage_Tables_Whitespace(t *testing.T) {
+	rows := [][]string{
+		{"Account", "2023", "2024"},
+		{"Cash", "1,000", "1,250"},
+		{"Receivables", "430", "512"},
+		{"Inventory", "88", "91"},
+	}
+
+	var b strings.Builder
+	b.WriteString(showText(72, 740, 10, "The balance sheet improved across all accounts this year."))
+	for i, row := range rows {
+		y := 700 - float64(i)*14
+		for j, text := range row {
+			b.WriteString(showText(72+float64(j)*150, y, 10, text))
+		}
+	}
+	// Horizontal rules only, as in booktabs-style tables.
+	b.WriteString("72 712 m 420 712 l S\n72 692 m 420 692 l S\n72 654 m 420 654 l S\n")
+	b.WriteString(showText(72, 600, 10, "Totals are reported in thousands of dollars."))
+
+	tables := extractTables(t, b.String())
+	if len(tables) != 1 {
+		t.Fatalf("expected 1 table, got %d", len(tables))
+	}
+
+	table := tables[0]
+	if table.Ruled {
+		t.Error("expected whitespace-aligned table")
+	}
+	if got := table.Rows(); !reflect.DeepEqual(got, rows) {
+		t.Errorf("Rows() = %v, want %v", got, rows)
+	}
+}
+
+func TestPDFPage_Tables_ProseOnly(t *testing.T) {
+	var b strings.Builder
+	for i := range 5 {
+		b.WriteString(showText(72, 700-float64(i)*14, 10, "A paragraph of running text without any columns."))
+	}
+
+	if tables := extractTables(t, b.String()); len(tables) != 0 {
+		t.Errorf("expected no tables, got %d", len(tables))
+	}
+}
+
+func TestPDFPage_Tables_CheatSheet(t *testing.T) {
+	page := extractPDFPage(t, 1)
+
+	if _, err := page.Tables(); err != nil {
+		t.Fatalf("Tables failed: %v", err)
+	}
+}
+
+func TestTable_Markdown(t *testing.T) {
+	table := document.Table{
+		Cells: [][]document.Cell{
+			{{Text: "Name"}, {Text: "Notes"}},
+			{{Text: "a|b"}, {Text: "line one\nline two"}},
+		},
+	}
+
+	want := "| Name | Notes |\n" +
+		"| --- | --- |\n" +
+		"| a\\|b | line one<br>line two |\n"
+
+	if got := table.Markdown(); got != want {
+		t.Errorf("Markdown() = %q, want %q", got, want)
+	}
+
+	empty := document.Table{}
+	if got := empty.Markdown(); got != "" {
+		t.Errorf("expected empty Markdown for empty table, got %q", got)
+	}
+}
+
+func TestTable_CSV(t *testing.T) {
+	table := document.Table{
+		Cells: [][]document.Cell{
+			{{Text: "Name"}, {Text: "Amount"}},
+			{{Text: "Smith, J."}, {Text: "1,200"}},
+			{{Text: `say "hi"`}, {Text: ""}},
+		},
+	}
+
+	want := "Name,Amount\n" +
+		"\"Smith, J.\",\"1,200\"\n" +
+		"\"say \"\"hi\"\"\",\n"
+
+	got, err := table.CSV()
+	if err != nil {
+		t.Fatalf("CSV failed: %v", err)
+	}
+	if got != want {
+		t.Errorf("CSV() = %q, want %q", got, want)
+	}
+}
+
+func TestPDFPage_Tables_MultiColumnLayout(t *testing.T) {
+	var b strings.Builder
+	for i := range 6 {
+		y := 700 - float64(i)*14
+		b.WriteString(showText(36, y, 8, "Left column prose continues for a while here"))
+		b.WriteString(showText(320, y, 8, "Right column prose also runs to the margin"))
+	}
+
+	if tables := extractTables(t, b.String()); len(tables) != 0 {
+		t.Errorf("expected multi-column prose not to be detected as a table, got %d", len(tables))
+	}
+}
@@ -0,0 +1,211 @@
+package document_test
+
+import (
+	"archive/zip"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+)
+
+// writeZip writes a zip archive with the given entries and returns its path.
+// Entries are written in name order so archives are deterministic.
+func writeZip(t *testing.T, name string, entries map[string]string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("Failed to create %s: %v", name, err)
+	}
+	defer f.Close()
+
+	names := make([]string, 0, len(entries))
+	for n := range entries {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	zw := zip.NewWriter(f)
+	for _, n := range names {
+		w, err := zw.Create(n)
+		if err != nil {
+			t.Fatalf("Failed to add %s: %v", n, err)
+		}
+		if _, err := w.Write([]byte(entries[n])); err != nil {
+			t.Fatalf("Failed to write %s: %v", n, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("Failed to finish %s: %v", name, err)
+	}
+	return path
+}
+
+const wordNS = `xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"`
+
+// writeDOCX writes a DOCX package whose document body is body. Optional
+// styles, numbering, and footnotes parts are included when non-empty.
+func writeDOCX(t *testing.T, body, styles, numbering, footnotes string) string {
+	t.Helper()
+
+	rels := `<?xml version="1.0" encoding="UTF-8"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`
+	entries := map[string]string{
+		"[Content_Types].xml": `<?xml version="1.0" encoding="UTF-8"?><Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"/>`,
+		"_rels/.rels": `<?xml version="1.0" encoding="UTF-8"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="word/document.xml"/>
+</Relationships>`,
+		"word/document.xml": `<?xml version="1.0" encoding="UTF-8"?><w:document ` + wordNS + `><w:body>` + body + `</w:body></w:document>`,
+	}
+
+	parts := []struct{ id, kind, name, content string }{
+		{"rId1", "styles", "styles.xml", styles},
+		{"rId2", "numbering", "numbering.xml", numbering},
+		{"rId3", "footnotes", "footnotes.xml", footnotes},
+	}
+	for _, p := range parts {
+		if p.content == "" {
+			continue
+		}
+		rels += `<Relationship Id="` + p.id + `" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/` + p.kind + `" Target="` + p.name + `"/>`
+		entries["word/"+p.name] = p.content
+	}
+	entries["word/_rels/document.xml.rels"] = rels + `</Relationships>`
+
+	return writeZip(t, "test.docx", entries)
+}
+
+// para returns a w:p element with the given paragraph properties and text.
+func para(props, text string) string {
+	return `<w:p><w:pPr>` + props + `</w:pPr><w:r><w:t xml:space="preserve">` + text + `</w:t></w:r></w:p>`
+}
+
+const sheetNS = `xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"`
+
+// xlsxSheet is a worksheet written by writeXLSX; data is the content of its
+// sheetData element.
+type xlsxSheet struct {
+	name string
+	data string
+}
+
+// writeXLSX writes an XLSX package with the given worksheets. Optional shared
+// strings and styles parts are included when non-empty.
+func writeXLSX(t *testing.T, sheets []xlsxSheet, sharedStrings, styles string) string {
+	t.Helper()
+
+	entries := map[string]string{
+		"[Content_Types].xml": `<?xml version="1.0" encoding="UTF-8"?><Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"/>`,
+		"_rels/.rels": `<?xml version="1.0" encoding="UTF-8"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>
+</Relationships>`,
+	}
+
+	rels := `<?xml version="1.0" encoding="UTF-8"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`
+	workbook := `<?xml version="1.0" encoding="UTF-8"?><workbook ` + sheetNS + `><sheets>`
+
+	for i, s := range sheets {
+		id := fmt.Sprintf("rId%d", i+1)
+		part := fmt.Sprintf("worksheets/sheet%d.xml", i+1)
+		workbook += `<sheet name="` + s.name + `" sheetId="` + fmt.Sprint(i+1) + `" r:id="` + id + `"/>`
+		rels += `<Relationship Id="` + id + `" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="` + part + `"/>`
+		entries["xl/"+part] = `<?xml version="1.0" encoding="UTF-8"?><worksheet ` + sheetNS + `><sheetData>` + s.data + `</sheetData></worksheet>`
+	}
+	entries["xl/workbook.xml"] = workbook + `</sheets></workbook>`
+
+	if sharedStrings != "" {
+		rels += `<Relationship Id="rIdS" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/sharedStrings" Target="sharedStrings.xml"/>`
+		entries["xl/sharedStrings.xml"] = `<?xml version="1.0" encoding="UTF-8"?><sst ` + sheetNS + `>` + sharedStrings + `</sst>`
+	}
+	if styles != "" {
+		rels += `<Relationship Id="rIdT" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>`
+		entries["xl/styles.xml"] = `<?xml version="1.0" encoding="UTF-8"?><styleSheet ` + sheetNS + `>` + styles + `</styleSheet>`
+	}
+	entries["xl/_rels/workbook.xml.rels"] = rels + `</Relationships>`
+
+	return writeZip(t, "test.xlsx", entries)
+}
+
+const presentationNS = `xmlns:p="http://schemas.openxmlformats.org/presentationml/2006/main" xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"`
+
+// pptxSlideXML is a slide written by writePPTX: the content of its shape tree,
+// optional speaker notes paragraphs, and attributes of the slide element.
+type pptxSlideXML struct {
+	shapes string
+	notes  string
+	attrs  string
+}
+
+// writePPTX writes a PPTX package with the given slides, sharing one layout
+// (with a title placeholder) and one master (with a body placeholder).
+func writePPTX(t *testing.T, slides []pptxSlideXML) string {
+	t.Helper()
+
+	const relsHeader = `<?xml version="1.0" encoding="UTF-8"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`
+	const relType = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/"
+
+	spTree := func(shapes string) string {
+		return `<p:cSld><p:spTree><p:nvGrpSpPr/><p:grpSpPr/>` + shapes + `</p:spTree></p:cSld>`
+	}
+
+	entries := map[string]string{
+		"[Content_Types].xml": `<?xml version="1.0" encoding="UTF-8"?><Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"/>`,
+		"_rels/.rels":         relsHeader + `<Relationship Id="rId1" Type="` + relType + `officeDocument" Target="ppt/presentation.xml"/></Relationships>`,
+		"ppt/slideLayouts/slideLayout1.xml": `<p:sldLayout ` + presentationNS + `>` + spTree(
+			pptxShape(`<p:ph type="title"/>`, pptxXfrm(457200, 274638, 8229600, 1143000), "")) + `</p:sldLayout>`,
+		"ppt/slideLayouts/_rels/slideLayout1.xml.rels": relsHeader + `<Relationship Id="rId1" Type="` + relType + `slideMaster" Target="../slideMasters/slideMaster1.xml"/></Relationships>`,
+		"ppt/slideMasters/slideMaster1.xml": `<p:sldMaster ` + presentationNS + `>` + spTree(
+			pptxShape(`<p:ph type="title"/>`, pptxXfrm(0, 0, 100, 100), "")+
+				pptxShape(`<p:ph type="body" idx="1"/>`, pptxXfrm(457200, 1600200, 8229600, 2000000), "")) + `</p:sldMaster>`,
+	}
+
+	presentation := `<?xml version="1.0" encoding="UTF-8"?><p:presentation ` + presentationNS + `><p:sldIdLst>`
+	rels := relsHeader
+
+	for i, s := range slides {
+		n := i + 1
+		presentation += fmt.Sprintf(`<p:sldId id="%d" r:id="rId%d"/>`, 255+n, n)
+		rels += fmt.Sprintf(`<Relationship Id="rId%d" Type="%sslide" Target="slides/slide%d.xml"/>`, n, relType, n)
+
+		entries[fmt.Sprintf("ppt/slides/slide%d.xml", n)] = `<p:sld ` + presentationNS + ` ` + s.attrs + `>` + spTree(s.shapes) + `</p:sld>`
+
+		slideRels := relsHeader + `<Relationship Id="rId1" Type="` + relType + `slideLayout" Target="../slideLayouts/slideLayout1.xml"/>`
+		if s.notes != "" {
+			slideRels += fmt.Sprintf(`<Relationship Id="rId2" Type="%snotesSlide" Target="../notesSlides/notesSlide%d.xml"/>`, relType, n)
+			entries[fmt.Sprintf("ppt/notesSlides/notesSlide%d.xml", n)] = `<p:notes ` + presentationNS + `>` + spTree(
+				pptxShape(`<p:ph type="sldImg"/>`, "", "")+
+					pptxShape(`<p:ph type="body" idx="1"/>`, "", s.notes)+
+					pptxShape(`<p:ph type="sldNum" idx="5"/>`, "", `<a:p><a:fld type="slidenum"><a:t>`+fmt.Sprint(n)+`</a:t></a:fld></a:p>`)) + `</p:notes>`
+		}
+		entries[fmt.Sprintf("ppt/slides/_rels/slide%d.xml.rels", n)] = slideRels + `</Relationships>`
+	}
+
+	entries["ppt/presentation.xml"] = presentation + `</p:sldIdLst><p:sldSz cx="9144000" cy="6858000"/></p:presentation>`
+	entries["ppt/_rels/presentation.xml.rels"] = rels + `</Relationships>`
+
+	return writeZip(t, "test.pptx", entries)
+}
+
+// pptxShape returns a p:sp element with optional placeholder, position, and
+// paragraphs.
+func pptxShape(ph, xfrm, paragraphs string) string {
+	return `<p:sp><p:nvSpPr><p:cNvPr id="2" name="Shape"/><p:cNvSpPr/><p:nvPr>` + ph + `</p:nvPr></p:nvSpPr>` +
+		`<p:spPr>` + xfrm + `</p:spPr><p:txBody><a:bodyPr/>` + paragraphs + `</p:txBody></p:sp>`
+}
+
+// pptxXfrm returns an a:xfrm element for the given position in EMUs.
+func pptxXfrm(x, y, cx, cy int) string {
+	return fmt.Sprintf(`<a:xfrm><a:off x="%d" y="%d"/><a:ext cx="%d" cy="%d"/></a:xfrm>`, x, y, cx, cy)
+}
+
+// pptxPara returns an a:p element with the given paragraph properties and
+// text.
+func pptxPara(props, text string) string {
+	return `<a:p>` + props + `<a:r><a:rPr lang="en-US"/><a:t>` + text + `</a:t></a:r></a:p>`
+}
@@ -0,0 +1,97 @@
+package document_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/JaimeStill/document-context/pkg/document"
+)
+
+func extractPDFPage(t *testing.T, pageNum int) *document.PDFPage {
+	t.Helper()
+
+	doc, err := document.OpenPDF(testPDFPath(t))
+	if err != nil {
+		t.Fatalf("OpenPDF failed: %v", err)
+	}
+	t.Cleanup(func() { doc.Close() })
+
+	page, err := doc.ExtractPage(pageNum)
+	if err != nil {
+		t.Fatalf("ExtractPage failed: %v", err)
+	}
+
+	return page.(*document.PDFPage)
+}
+
+func TestPDFPage_TextSpans(t *testing.T) {
+	page := extractPDFPage(t, 1)
+
+	spans, err := page.TextSpans()
+	if err != nil {
+		t.Fatalf("TextSpans failed: %v", err)
+	}
+
+	if len(spans) == 0 {
+		t.Fatal("Expected text spans on page 1")
+	}
+
+	first := spans[0]
+	if first.Text != "VIM QUICK REFERENCE CARD" {
+		t.Errorf("Expected first span to be the title, got %q", first.Text)
+	}
+
+	if first.FontSize <= 0 {
+		t.Errorf("Expected positive font size, got %f", first.FontSize)
+	}
+
+	bounds := first.Bounds()
+	if bounds.Width() <= 0 || bounds.Height() <= 0 {
+		t.Errorf("Expected non-empty bounds, got %+v", bounds)
+	}
+}
+
+func TestPDFPage_Text(t *testing.T) {
+	page := extractPDFPage(t, 1)
+
+	text, err := page.Text()
+	if err != nil {
+		t.Fatalf("Text failed: %v", err)
+	}
+
+	for _, want := range []string{"VIM QUICK REFERENCE CARD", "Movements", "Show a summary of all commands"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected text to contain %q", want)
+		}
+	}
+
+	if !strings.Contains(text, "\n") {
+		t.Error("Expected multiple lines of text")
+	}
+}
+
+func TestPDFPage_ImplementsTextPage(t *testing.T) {
+	page := extractPDFPage(t, 1)
+
+	var p document.Page = page
+	if _, ok := p.(document.TextPage); !ok {
+		t.Error("Expected PDFPage to implement TextPage")
+	}
+}
+
+func TestPDFPage_Text_ClosedDocument(t *testing.T) {
+	doc, err := document.OpenPDF(testPDFPath(t))
+	if err != nil {
+		t.Fatalf("OpenPDF failed: %v", err)
+	}
+
+	page, err := doc.ExtractPage(1)
+	if err != nil {
+		t.Fatalf("ExtractPage failed: %v", err)
+	}
+	doc.Close()
+
+	if _, err := page.(document.TextPage).Text(); err == nil {
+		t.Error("Expected error extracting text from closed document")
+	}
+}
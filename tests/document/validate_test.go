@@ -0,0 +1,246 @@
+package document_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/JaimeStill/document-context/pkg/config"
+	"github.com/JaimeStill/document-context/pkg/document"
+)
+
+// writeDamagedPDF writes a copy of the test PDF with its final end-of-file
+// marker removed, which pdfcpu refuses to parse.
+func writeDamagedPDF(t *testing.T) string {
+	t.Helper()
+
+	data, err := os.ReadFile(testPDFPath(t))
+	if err != nil {
+		t.Fatalf("Failed to read test PDF: %v", err)
+	}
+
+	data = data[:bytes.LastIndex(data, []byte("%%EOF"))]
+
+	path := filepath.Join(t.TempDir(), "damaged.pdf")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("Failed to write damaged PDF: %v", err)
+	}
+	return path
+}
+
+func TestOpenPDF_RelaxedByDefault(t *testing.T) {
+	doc, err := document.OpenPDF(testPDFPath(t))
+	if err != nil {
+		t.Fatalf("OpenPDF failed: %v", err)
+	}
+	defer doc.Close()
+
+	if len(doc.Warnings()) != 0 {
+		t.Errorf("expected no warnings, got %v", doc.Warnings())
+	}
+	if doc.Repaired() {
+		t.Error("expected document not to be repaired")
+	}
+}
+
+func TestOpenPDFWithConfig_Strict(t *testing.T) {
+	cfg := config.DocumentConfig{Validation: config.ValidationStrict}
+
+	if _, err := document.OpenPDFWithConfig(testPDFPath(t), cfg); err == nil {
+		t.Fatal("expected strict validation to reject the test PDF")
+	}
+
+	cfg.Repair = true
+	doc, err := document.OpenPDFWithConfig(testPDFPath(t), cfg)
+	if err != nil {
+		t.Fatalf("OpenPDFWithConfig with repair failed: %v", err)
+	}
+	defer doc.Close()
+
+	warnings := doc.Warnings()
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "opened without validation") {
+		t.Errorf("expected a single validation warning, got %v", warnings)
+	}
+	if doc.Repaired() {
+		t.Error("validation failures should not rewrite the document")
+	}
+	if doc.PageCount() != 2 {
+		t.Errorf("PageCount() = %d, want 2", doc.PageCount())
+	}
+}
+
+func TestOpenPDFWithConfig_NoValidation(t *testing.T) {
+	cfg := config.DocumentConfig{Validation: config.ValidationNone}
+
+	doc, err := document.OpenPDFWithConfig(testPDFPath(t), cfg)
+	if err != nil {
+		t.Fatalf("OpenPDFWithConfig failed: %v", err)
+	}
+	defer doc.Close()
+
+	if doc.PageCount() != 2 {
+		t.Errorf("PageCount() = %d, want 2", doc.PageCount())
+	}
+}
+
+func TestOpenPDFWithConfig_InvalidValidation(t *testing.T) {
+	cfg := config.DocumentConfig{Validation: "paranoid"}
+
+	_, err := document.OpenPDFWithConfig(testPDFPath(t), cfg)
+	if err == nil || !strings.Contains(err.Error(), "unsupported validation mode") {
+		t.Errorf("expected unsupported validation mode error, got %v", err)
+	}
+}
+
+func TestOpenPDFWithConfig_Repair(t *testing.T) {
+	path := writeDamagedPDF(t)
+
+	if _, err := document.OpenPDF(path); err == nil {
+		t.Fatal("expected damaged PDF to fail without repair")
+	}
+
+	doc, err := document.OpenPDFWithConfig(path, config.DocumentConfig{Repair: true})
+	if err != nil {
+		t.Fatalf("OpenPDFWithConfig with repair failed: %v", err)
+	}
+	defer doc.Close()
+
+	if !doc.Repaired() {
+		t.Error("expected document to be repaired")
+	}
+	if len(doc.Warnings()) == 0 || !strings.Contains(doc.Warnings()[0], "rebuilt cross-reference table") {
+		t.Errorf("expected rebuild warning, got %v", doc.Warnings())
+	}
+	if doc.PageCount() != 2 {
+		t.Fatalf("PageCount() = %d, want 2", doc.PageCount())
+	}
+
+	original := extractPDFPage(t, 1)
+	want, err := original.Text()
+	if err != nil {
+		t.Fatalf("Text() failed: %v", err)
+	}
+	if got := pageText(t, doc, 1); got != want {
+		t.Error("repaired page text does not match original")
+	}
+
+	data, err := doc.ExtractPDF([]int{2}, nil)
+	if err != nil {
+		t.Fatalf("ExtractPDF from repaired document failed: %v", err)
+	}
+	if !bytes.HasPrefix(data, []byte("%PDF-")) {
+		t.Error("expected PDF output from repaired document")
+	}
+}
+
+func TestOpenPDFWithConfig_RepairFails(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "garbage.pdf")
+	if err := os.WriteFile(path, []byte("%PDF-1.4\nnot really a pdf\n"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	_, err := document.OpenPDFWithConfig(path, config.DocumentConfig{Repair: true})
+	if err == nil || !strings.Contains(err.Error(), "repair failed") {
+		t.Errorf("expected repair failure, got %v", err)
+	}
+}
+
+func TestPDFDocument_Validate(t *testing.T) {
+	doc, err := document.OpenPDF(testPDFPath(t))
+	if err != nil {
+		t.Fatalf("OpenPDF failed: %v", err)
+	}
+	defer doc.Close()
+
+	report, err := doc.Validate()
+	if err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+
+	if !report.Valid() {
+		t.Errorf("expected valid report, got errors %v", report.Errors)
+	}
+	if !report.Relaxed {
+		t.Error("expected relaxed validation to pass")
+	}
+	if report.Strict {
+		t.Error("expected strict validation to fail for the test PDF")
+	}
+	if len(report.Warnings) == 0 || !strings.Contains(report.Warnings[0], "strict validation failed") {
+		t.Errorf("expected strict violation as warning, got %v", report.Warnings)
+	}
+	if report.Repaired {
+		t.Error("expected Repaired to be false")
+	}
+}
+
+func TestPDFDocument_Validate_Repaired(t *testing.T) {
+	doc, err := document.OpenPDFWithConfig(writeDamagedPDF(t), config.DocumentConfig{Repair: true})
+	if err != nil {
+		t.Fatalf("OpenPDFWithConfig failed: %v", err)
+	}
+	defer doc.Close()
+
+	report, err := doc.Validate()
+	if err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+
+	if !report.Repaired {
+		t.Error("expected Repaired to be true")
+	}
+	if !report.Relaxed {
+		t.Errorf("expected repaired document to pass relaxed validation, got %v", report.Errors)
+	}
+	if len(report.Warnings) < 2 {
+		t.Errorf("expected repair and strict warnings, got %v", report.Warnings)
+	}
+}
+
+func TestPDFDocument_Validate_ToleratedViolations(t *testing.T) {
+	data, err := os.ReadFile(writeContentPDF(t, showText(72, 700, 12, "Report")))
+	if err != nil {
+		t.Fatalf("Failed to read PDF: %v", err)
+	}
+
+	// A duplicate trailer key is digested by pdfcpu in every mode; the
+	// trailer follows the cross-reference table, so offsets stay valid.
+	data = bytes.Replace(data, []byte("/Root 1 0 R >>"), []byte("/Root 1 0 R /Root 1 0 R >>"), 1)
+	path := filepath.Join(t.TempDir(), "duplicate.pdf")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("Failed to write PDF: %v", err)
+	}
+
+	doc, err := document.OpenPDF(path)
+	if err != nil {
+		t.Fatalf("OpenPDF failed: %v", err)
+	}
+	defer doc.Close()
+
+	report, err := doc.Validate()
+	if err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+
+	if !report.Relaxed || !report.Valid() {
+		t.Errorf("expected relaxed validation to pass, got %v", report.Errors)
+	}
+	if !slices.Contains(report.Warnings, `digested: duplicate key "Root"`) {
+		t.Errorf("expected tolerated duplicate key as warning, got %v", report.Warnings)
+	}
+}
+
+func TestPDFDocument_Validate_Closed(t *testing.T) {
+	doc, err := document.OpenPDF(testPDFPath(t))
+	if err != nil {
+		t.Fatalf("OpenPDF failed: %v", err)
+	}
+	doc.Close()
+
+	if _, err := doc.Validate(); err == nil {
+		t.Error("expected error for closed document")
+	}
+}
@@ -58,6 +58,29 @@ func TestEncodeImageDataURI_JPEG(t *testing.T) {
 	}
 }
 
+func TestEncodeImageDataURI_ModernFormats(t *testing.T) {
+	tests := []struct {
+		format document.ImageFormat
+		prefix string
+	}{
+		{document.WebP, "data:image/webp;base64,"},
+		{document.AVIF, "data:image/avif;base64,"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			dataURI, err := encoding.EncodeImageDataURI([]byte("fake-image-data"), tt.format)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !strings.HasPrefix(dataURI, tt.prefix) {
+				t.Errorf("expected %s data URI prefix, got: %s", tt.format, dataURI[:min(30, len(dataURI))])
+			}
+		})
+	}
+}
+
 func TestEncodeImageDataURI_EmptyData(t *testing.T) {
 	imageData := []byte{}
 
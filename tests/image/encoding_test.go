@@ -0,0 +1,78 @@
+package image_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/JaimeStill/document-context/pkg/config"
+	"github.com/JaimeStill/document-context/pkg/image"
+)
+
+func TestIsLossy(t *testing.T) {
+	tests := []struct {
+		name   string
+		config config.ImageConfig
+		want   bool
+	}{
+		{"png", config.ImageConfig{Format: "png"}, false},
+		{"jpg", config.ImageConfig{Format: "jpg"}, true},
+		{"jpeg", config.ImageConfig{Format: "jpeg"}, true},
+		{"avif", config.ImageConfig{Format: "avif"}, true},
+		{"lossy webp", config.ImageConfig{Format: "webp"}, true},
+		{"lossless webp", config.ImageConfig{Format: "webp", Lossless: true}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := image.IsLossy(tt.config); got != tt.want {
+				t.Errorf("IsLossy() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderers_RenderWith(t *testing.T) {
+	_, imArgs := installFakeImageMagick(t, "7.1.1-29", "magick")
+	ppArgs := installFakePdftoppm(t)
+
+	tests := []struct {
+		name     string
+		create   func(config.ImageConfig) (image.Renderer, error)
+		argsFile string
+		want     []string
+	}{
+		{"imagemagick", image.NewImageMagickRenderer, imArgs, []string{"-density 96.5 in.pdf[0]", "-quality 50"}},
+		{"pdftoppm", image.NewPdftoppmRenderer, ppArgs, []string{"-r 96.5 -jpeg -jpegopt quality=50"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			renderer, err := tt.create(config.ImageConfig{Format: "jpg", Quality: 90})
+			if err != nil {
+				t.Fatalf("renderer creation failed: %v", err)
+			}
+
+			encoder, ok := renderer.(image.EncodingRenderer)
+			if !ok {
+				t.Fatal("expected renderer to implement image.EncodingRenderer")
+			}
+
+			output := filepath.Join(t.TempDir(), "page.jpg")
+			if err := encoder.RenderWith("in.pdf", 1, output, image.Encoding{DPI: 96.5, Quality: 50}); err != nil {
+				t.Fatalf("RenderWith failed: %v", err)
+			}
+
+			args, err := os.ReadFile(tt.argsFile)
+			if err != nil {
+				t.Fatalf("Failed to read args: %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(string(args), want) {
+					t.Errorf("unexpected arguments:\n%s\nwant:\n%s", args, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package image_test
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/JaimeStill/document-context/pkg/config"
+	"github.com/JaimeStill/document-context/pkg/image"
+)
+
+func TestHasFitLimits(t *testing.T) {
+	tests := []struct {
+		name   string
+		config config.ImageConfig
+		want   bool
+	}{
+		{"DPI only", config.ImageConfig{DPI: 300}, false},
+		{"max width", config.ImageConfig{MaxWidth: 1000}, true},
+		{"max height", config.ImageConfig{MaxHeight: 1000}, true},
+		{"max long edge", config.ImageConfig{MaxLongEdge: 1568}, true},
+		{"max megapixels", config.ImageConfig{MaxMegapixels: 1.15}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := image.HasFitLimits(tt.config); got != tt.want {
+				t.Errorf("HasFitLimits() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFitDPI(t *testing.T) {
+	const letterWidth, letterHeight = 612, 792
+
+	tests := []struct {
+		name          string
+		config        config.ImageConfig
+		width, height float64
+		want          float64
+	}{
+		{"no limits uses DPI", config.ImageConfig{DPI: 300}, letterWidth, letterHeight, 300},
+		{"long edge", config.ImageConfig{DPI: 300, MaxLongEdge: 1568}, letterWidth, letterHeight, 142.54},
+		{"long edge landscape", config.ImageConfig{MaxLongEdge: 1568}, letterHeight, letterWidth, 142.54},
+		{"max width", config.ImageConfig{MaxWidth: 1275}, letterWidth, letterHeight, 150},
+		{"max height", config.ImageConfig{MaxHeight: 1100}, letterWidth, letterHeight, 100},
+		{"smallest limit wins", config.ImageConfig{MaxWidth: 1275, MaxHeight: 1100}, letterWidth, letterHeight, 100},
+		{"megapixels", config.ImageConfig{MaxMegapixels: 1}, letterWidth, letterHeight, 103.41},
+		{"small page scaled up", config.ImageConfig{MaxLongEdge: 1568}, 144, 72, 784},
+		{"invalid size uses DPI", config.ImageConfig{DPI: 200, MaxLongEdge: 1568}, 0, letterHeight, 200},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := image.FitDPI(tt.config, tt.width, tt.height)
+			if math.Abs(got-tt.want) > 0.005 {
+				t.Errorf("FitDPI() = %g, want %g", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFitDPI_FitsLimits(t *testing.T) {
+	cfg := config.ImageConfig{MaxWidth: 1000, MaxHeight: 1000, MaxLongEdge: 1568, MaxMegapixels: 1.15}
+
+	sizes := [][2]float64{{612, 792}, {841.89, 595.276}, {1224, 792}, {300, 4000}, {72, 72}}
+	for _, size := range sizes {
+		dpi := image.FitDPI(cfg, size[0], size[1])
+		width := math.Round(size[0] * dpi / 72)
+		height := math.Round(size[1] * dpi / 72)
+
+		if width > 1000 || height > 1000 || width*height > 1.15e6 {
+			t.Errorf("%vpt at %g DPI renders %gx%g px, exceeding limits", size, dpi, width, height)
+		}
+		if width < 999 && height < 999 && width*height < 1.14e6 {
+			t.Errorf("%vpt at %g DPI renders %gx%g px, not touching any limit", size, dpi, width, height)
+		}
+	}
+}
+
+func TestRenderers_FitDPI(t *testing.T) {
+	limits := config.ImageConfig{MaxWidth: 1100}
+
+	pdftoppm, err := image.NewPdftoppmRenderer(limits)
+	if err != nil {
+		t.Fatalf("NewPdftoppmRenderer failed: %v", err)
+	}
+
+	rotated := limits
+	rotated.Options = map[string]any{"rotation": 90}
+	imagemagick, err := image.NewImageMagickRenderer(rotated)
+	if err != nil {
+		t.Fatalf("NewImageMagickRenderer failed: %v", err)
+	}
+
+	// Portrait Letter: pdftoppm fits the page width, while ImageMagick
+	// rotates the page so its height becomes the output width.
+	if got := pdftoppm.(image.FitRenderer).FitDPI(612, 792); math.Abs(got-129.41) > 0.005 {
+		t.Errorf("pdftoppm FitDPI = %g, want 129.41", got)
+	}
+	if got := imagemagick.(image.FitRenderer).FitDPI(612, 792); got != 100 {
+		t.Errorf("rotated ImageMagick FitDPI = %g, want 100", got)
+	}
+}
+
+func TestRenderers_RenderAt(t *testing.T) {
+	_, imArgs := installFakeImageMagick(t, "7.1.1-29", "magick")
+	ppArgs := installFakePdftoppm(t)
+
+	tests := []struct {
+		name     string
+		create   func(config.ImageConfig) (image.Renderer, error)
+		argsFile string
+		want     string
+	}{
+		{"imagemagick", image.NewImageMagickRenderer, imArgs, "-density 142.54 in.pdf[0]"},
+		{"pdftoppm", image.NewPdftoppmRenderer, ppArgs, "-r 142.54 -png"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			renderer, err := tt.create(config.ImageConfig{MaxLongEdge: 1568})
+			if err != nil {
+				t.Fatalf("renderer creation failed: %v", err)
+			}
+
+			output := filepath.Join(t.TempDir(), "page.png")
+			if err := renderer.(image.FitRenderer).RenderAt("in.pdf", 1, output, 142.54); err != nil {
+				t.Fatalf("RenderAt failed: %v", err)
+			}
+
+			args, err := os.ReadFile(tt.argsFile)
+			if err != nil {
+				t.Fatalf("Failed to read args: %v", err)
+			}
+			if !strings.Contains(string(args), tt.want) {
+				t.Errorf("unexpected arguments:\n%s\nwant:\n%s", args, tt.want)
+			}
+		})
+	}
+}
@@ -1,8 +1,11 @@
 package image_test
 
 import (
+	"bytes"
 	"os"
 	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
 
 	"github.com/JaimeStill/document-context/pkg/config"
@@ -496,6 +499,10 @@ func TestRenderer_Render_Integration(t *testing.T) {
 		t.Fatalf("NewImageMagickRenderer failed: %v", err)
 	}
 
+	if err := renderer.(image.CheckableRenderer).Check(); err != nil {
+		t.Skipf("ImageMagick not available, skipping integration test: %v", err)
+	}
+
 	tmpFile, err := os.CreateTemp("", "test-render-*.png")
 	if err != nil {
 		t.Fatalf("Failed to create temp file: %v", err)
@@ -524,3 +531,408 @@ func TestRenderer_Render_Integration(t *testing.T) {
 		t.Error("Expected non-empty output file")
 	}
 }
+
+// installFakeImageMagick makes names the only ImageMagick executables on
+// PATH. Each reports the given version for -version, and otherwise records
+// its name and arguments and writes a fake image to its last argument.
+func installFakeImageMagick(t *testing.T, version string, names ...string) (dir, argsFile string) {
+	t.Helper()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("fake ImageMagick requires a POSIX shell")
+	}
+
+	dir = t.TempDir()
+	argsFile = filepath.Join(dir, "args")
+
+	script := "#!/bin/sh\n" +
+		"if [ \"$1\" = -version ]; then echo 'Version: ImageMagick " + version + " Q16 x86_64'; exit 0; fi\n" +
+		"echo \"${0##*/} $@\" > '" + argsFile + "'\n" +
+		"for a in \"$@\"; do out=\"$a\"; done\n" +
+		"case \"$out\" in *:-) printf fake-image ;; *) printf fake-image > \"$out\" ;; esac\n"
+
+	for _, name := range names {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(script), 0755); err != nil {
+			t.Fatalf("Failed to write fake %s: %v", name, err)
+		}
+	}
+	t.Setenv("PATH", dir)
+	return dir, argsFile
+}
+
+func TestImageMagickRenderer_CheckVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		version string
+		names   []string
+		binary  string
+	}{
+		{"magick preferred", "7.1.1-29", []string{"magick", "convert"}, "magick"},
+		{"legacy convert fallback", "6.9.11-60", []string{"convert"}, "convert"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, argsFile := installFakeImageMagick(t, tt.version, tt.names...)
+
+			renderer, err := image.NewImageMagickRenderer(config.ImageConfig{})
+			if err != nil {
+				t.Fatalf("NewImageMagickRenderer failed: %v", err)
+			}
+
+			checkable, ok := renderer.(image.CheckableRenderer)
+			if !ok {
+				t.Fatal("expected ImageMagick renderer to implement CheckableRenderer")
+			}
+			if err := checkable.Check(); err != nil {
+				t.Fatalf("Check failed: %v", err)
+			}
+
+			version, err := checkable.Version()
+			if err != nil {
+				t.Fatalf("Version failed: %v", err)
+			}
+			if version != tt.version {
+				t.Errorf("expected version %q, got %q", tt.version, version)
+			}
+
+			output := filepath.Join(t.TempDir(), "page.png")
+			if err := renderer.Render("in.pdf", 2, output); err != nil {
+				t.Fatalf("Render failed: %v", err)
+			}
+
+			args, err := os.ReadFile(argsFile)
+			if err != nil {
+				t.Fatalf("Failed to read args: %v", err)
+			}
+			if !strings.HasPrefix(string(args), tt.binary+" -density 300 in.pdf[1]") {
+				t.Errorf("expected render through %s, got %s", tt.binary, args)
+			}
+		})
+	}
+}
+
+func TestImageMagickRenderer_ConfiguredBinary(t *testing.T) {
+	dir, _ := installFakeImageMagick(t, "7.0.10-0", "magick-7")
+	t.Setenv("PATH", t.TempDir())
+
+	renderer, err := image.NewImageMagickRenderer(config.ImageConfig{
+		Options: map[string]any{"binary": filepath.Join(dir, "magick-7")},
+	})
+	if err != nil {
+		t.Fatalf("NewImageMagickRenderer failed: %v", err)
+	}
+
+	version, err := renderer.(image.CheckableRenderer).Version()
+	if err != nil {
+		t.Fatalf("Version failed: %v", err)
+	}
+	if version != "7.0.10-0" {
+		t.Errorf("expected configured binary version, got %q", version)
+	}
+}
+
+func TestImageMagickRenderer_IncludeVersion(t *testing.T) {
+	installFakeImageMagick(t, "7.1.1-29", "magick")
+
+	renderer, err := image.NewImageMagickRenderer(config.ImageConfig{
+		Options: map[string]any{"include_version": true},
+	})
+	if err != nil {
+		t.Fatalf("NewImageMagickRenderer failed: %v", err)
+	}
+
+	want := []string{"background=white", "version=7.1.1-29"}
+	if got := renderer.Parameters(); strings.Join(got, "&") != strings.Join(want, "&") {
+		t.Errorf("Parameters() = %v, want %v", got, want)
+	}
+}
+
+func TestImageMagickRenderer_MissingBinary(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+
+	renderer, err := image.NewImageMagickRenderer(config.ImageConfig{})
+	if err != nil {
+		t.Fatalf("NewImageMagickRenderer failed: %v", err)
+	}
+
+	err = renderer.(image.CheckableRenderer).Check()
+	if err == nil || !strings.Contains(err.Error(), "imagemagick not found") {
+		t.Errorf("expected missing-binary error from Check, got %v", err)
+	}
+
+	err = renderer.Render("in.pdf", 1, filepath.Join(t.TempDir(), "page.png"))
+	if err == nil || !strings.Contains(err.Error(), "imagemagick not found") {
+		t.Errorf("expected missing-binary error from Render, got %v", err)
+	}
+
+	if _, err := image.NewImageMagickRenderer(config.ImageConfig{Options: map[string]any{"include_version": true}}); err == nil {
+		t.Error("expected include_version to fail without ImageMagick")
+	}
+}
+
+func TestImageMagickRenderer_UnrecognizedVersion(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake binary requires a POSIX shell")
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "convert"), []byte("#!/bin/sh\necho 'Invalid drive specification.'\n"), 0755); err != nil {
+		t.Fatalf("Failed to write fake convert: %v", err)
+	}
+	t.Setenv("PATH", dir)
+
+	renderer, err := image.NewImageMagickRenderer(config.ImageConfig{})
+	if err != nil {
+		t.Fatalf("NewImageMagickRenderer failed: %v", err)
+	}
+
+	err = renderer.(image.CheckableRenderer).Check()
+	if err == nil || !strings.Contains(err.Error(), "unrecognized convert version output") {
+		t.Errorf("expected unrecognized version error, got %v", err)
+	}
+}
+
+func TestNewImageMagickRenderer_ModernFormats(t *testing.T) {
+	tests := []struct {
+		name   string
+		config config.ImageConfig
+		errMsg string
+	}{
+		{"lossy WebP", config.ImageConfig{Format: "webp", Quality: 80}, ""},
+		{"lossless WebP ignores quality", config.ImageConfig{Format: "webp", Lossless: true}, ""},
+		{"AVIF", config.ImageConfig{Format: "avif", Quality: 60}, ""},
+		{"lossy WebP without quality", config.ImageConfig{Format: "webp"}, "quality must be 1-100 for webp"},
+		{"AVIF without quality", config.ImageConfig{Format: "avif"}, "quality must be 1-100 for avif"},
+		{"lossless AVIF", config.ImageConfig{Format: "avif", Quality: 60, Lossless: true}, "lossless encoding is only supported for webp"},
+		{"lossless PNG", config.ImageConfig{Format: "png", Lossless: true}, "lossless encoding is only supported for webp"},
+		{"unsupported format", config.ImageConfig{Format: "tiff"}, "must be 'png', 'jpg', 'webp', 'avif'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			renderer, err := image.NewImageMagickRenderer(tt.config)
+			if tt.errMsg == "" {
+				if err != nil {
+					t.Fatalf("NewImageMagickRenderer failed: %v", err)
+				}
+				if renderer.FileExtension() != tt.config.Format {
+					t.Errorf("expected extension %q, got %q", tt.config.Format, renderer.FileExtension())
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("expected error containing %q, got %v", tt.errMsg, err)
+			}
+		})
+	}
+}
+
+func TestImageMagickRenderer_Render_ModernFormats(t *testing.T) {
+	tests := []struct {
+		name   string
+		config config.ImageConfig
+		args   string
+	}{
+		{
+			name:   "lossy WebP",
+			config: config.ImageConfig{Format: "webp", Quality: 80},
+			args:   "-flatten -quality 80 ",
+		},
+		{
+			name:   "lossless WebP",
+			config: config.ImageConfig{Format: "webp", Lossless: true},
+			args:   "-flatten -define webp:lossless=true ",
+		},
+		{
+			name:   "AVIF",
+			config: config.ImageConfig{Format: "avif", Quality: 60},
+			args:   "-flatten -quality 60 ",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, argsFile := installFakeImageMagick(t, "7.1.1-29", "magick")
+
+			renderer, err := image.NewImageMagickRenderer(tt.config)
+			if err != nil {
+				t.Fatalf("NewImageMagickRenderer failed: %v", err)
+			}
+
+			output := filepath.Join(t.TempDir(), "page."+renderer.FileExtension())
+			if err := renderer.Render("in.pdf", 1, output); err != nil {
+				t.Fatalf("Render failed: %v", err)
+			}
+
+			args, err := os.ReadFile(argsFile)
+			if err != nil {
+				t.Fatalf("Failed to read args: %v", err)
+			}
+			if !strings.Contains(string(args), tt.args+output) {
+				t.Errorf("unexpected arguments:\n%s\nwant suffix:\n%s", args, tt.args+output)
+			}
+		})
+	}
+}
+
+func TestNewImageMagickRenderer_InvalidColorMode(t *testing.T) {
+	tests := []struct {
+		name    string
+		options map[string]any
+		errMsg  string
+	}{
+		{"unknown mode", map[string]any{"color_mode": "cmyk"}, "color_mode must be one of rgb, grayscale, bilevel, palette"},
+		{"threshold out of range", map[string]any{"color_mode": "bilevel", "threshold": 101}, "threshold must be 0-100"},
+		{"threshold without bilevel", map[string]any{"color_mode": "grayscale", "threshold": 40}, "threshold requires color_mode bilevel"},
+		{"threshold with dither", map[string]any{"color_mode": "bilevel", "threshold": 40, "dither": true}, "threshold cannot be combined with dither"},
+		{"too few colors", map[string]any{"color_mode": "palette", "colors": 1}, "colors must be 2-256"},
+		{"colors without palette", map[string]any{"colors": 8}, "colors requires color_mode palette"},
+		{"dither without reduction", map[string]any{"color_mode": "grayscale", "dither": true}, "dither requires color_mode bilevel or palette"},
+		{"non-boolean dither", map[string]any{"color_mode": "palette", "dither": "yes"}, "dither must be a boolean"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := image.NewImageMagickRenderer(config.ImageConfig{Options: tt.options})
+			if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("expected error containing %q, got %v", tt.errMsg, err)
+			}
+		})
+	}
+}
+
+func TestImageMagickRenderer_ColorModes(t *testing.T) {
+	tests := []struct {
+		name    string
+		options map[string]any
+		params  []string
+		args    string
+	}{
+		{
+			name:    "rgb adds nothing",
+			options: map[string]any{"color_mode": "rgb"},
+			params:  []string{"background=white"},
+			args:    "-flatten ",
+		},
+		{
+			name:    "grayscale",
+			options: map[string]any{"color_mode": "grayscale"},
+			params:  []string{"background=white", "color_mode=grayscale"},
+			args:    "-flatten -colorspace Gray ",
+		},
+		{
+			name:    "bilevel default threshold",
+			options: map[string]any{"color_mode": "bilevel"},
+			params:  []string{"background=white", "color_mode=bilevel", "dither=false", "threshold=50"},
+			args:    "-flatten -colorspace Gray -threshold 50% -type Bilevel ",
+		},
+		{
+			name:    "bilevel after contrast",
+			options: map[string]any{"color_mode": "bilevel", "threshold": 65, "contrast": 20},
+			params:  []string{"background=white", "color_mode=bilevel", "contrast=20", "dither=false", "threshold=65"},
+			args:    "-brightness-contrast 0,20 -colorspace Gray -threshold 65% -type Bilevel ",
+		},
+		{
+			name:    "bilevel dithered",
+			options: map[string]any{"color_mode": "bilevel", "dither": true},
+			params:  []string{"background=white", "color_mode=bilevel", "dither=true"},
+			args:    "-flatten -colorspace Gray -dither FloydSteinberg -monochrome ",
+		},
+		{
+			name:    "palette",
+			options: map[string]any{"color_mode": "palette", "colors": float64(8)},
+			params:  []string{"background=white", "color_mode=palette", "colors=8", "dither=false"},
+			args:    "-flatten +dither -colors 8 ",
+		},
+		{
+			name:    "palette dithered default colors",
+			options: map[string]any{"color_mode": "palette", "dither": true},
+			params:  []string{"background=white", "color_mode=palette", "colors=16", "dither=true"},
+			args:    "-flatten -dither FloydSteinberg -colors 16 ",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, argsFile := installFakeImageMagick(t, "7.1.1-29", "magick")
+
+			renderer, err := image.NewImageMagickRenderer(config.ImageConfig{Options: tt.options})
+			if err != nil {
+				t.Fatalf("NewImageMagickRenderer failed: %v", err)
+			}
+
+			if got := renderer.Parameters(); strings.Join(got, "&") != strings.Join(tt.params, "&") {
+				t.Errorf("Parameters() = %v, want %v", got, tt.params)
+			}
+
+			output := filepath.Join(t.TempDir(), "page.png")
+			if err := renderer.Render("in.pdf", 1, output); err != nil {
+				t.Fatalf("Render failed: %v", err)
+			}
+
+			args, err := os.ReadFile(argsFile)
+			if err != nil {
+				t.Fatalf("Failed to read args: %v", err)
+			}
+			if !strings.Contains(string(args), tt.args+output) {
+				t.Errorf("unexpected arguments:\n%s\nwant suffix:\n%s", args, tt.args+output)
+			}
+		})
+	}
+}
+
+func TestImageMagickRenderer_RenderTo(t *testing.T) {
+	_, argsFile := installFakeImageMagick(t, "7.1.1-29", "magick")
+
+	renderer, err := image.NewImageMagickRenderer(config.ImageConfig{Format: "jpg", Quality: 90})
+	if err != nil {
+		t.Fatalf("NewImageMagickRenderer failed: %v", err)
+	}
+
+	stream, ok := renderer.(image.StreamRenderer)
+	if !ok {
+		t.Fatal("expected ImageMagick renderer to implement image.StreamRenderer")
+	}
+
+	var buf bytes.Buffer
+	if err := stream.RenderTo("in.pdf", 2, &buf, image.Encoding{DPI: 150, Quality: 60}); err != nil {
+		t.Fatalf("RenderTo failed: %v", err)
+	}
+	if buf.String() != "fake-image" {
+		t.Errorf("expected image written to the writer, got %q", buf.String())
+	}
+
+	args, err := os.ReadFile(argsFile)
+	if err != nil {
+		t.Fatalf("Failed to read args: %v", err)
+	}
+	for _, want := range []string{"-density 150 in.pdf[1]", "-quality 60 jpg:-"} {
+		if !strings.Contains(string(args), want) {
+			t.Errorf("unexpected arguments:\n%s\nwant:\n%s", args, want)
+		}
+	}
+}
+
+func TestImageMagickRenderer_RenderTo_Failure(t *testing.T) {
+	dir := t.TempDir()
+	script := "#!/bin/sh\necho 'no decode delegate' >&2\nexit 1\n"
+	if err := os.WriteFile(filepath.Join(dir, "magick"), []byte(script), 0755); err != nil {
+		t.Fatalf("Failed to write fake magick: %v", err)
+	}
+	t.Setenv("PATH", dir)
+
+	renderer, err := image.NewImageMagickRenderer(config.ImageConfig{})
+	if err != nil {
+		t.Fatalf("NewImageMagickRenderer failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	err = renderer.(image.StreamRenderer).RenderTo("in.pdf", 1, &buf, image.Encoding{DPI: 300})
+	if err == nil || !strings.Contains(err.Error(), "no decode delegate") {
+		t.Errorf("expected error with ImageMagick output, got %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("expected no image data, got %q", buf.String())
+	}
+}
@@ -0,0 +1,270 @@
+package image_test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/JaimeStill/document-context/pkg/config"
+	"github.com/JaimeStill/document-context/pkg/image"
+)
+
+// installFakePdftoppm puts an executable named pdftoppm first on PATH. It
+// reports version 24.02.0 for -v, records its arguments and, like pdftoppm,
+// writes the image to the output root (its last argument) with the format's
+// extension appended.
+func installFakePdftoppm(t *testing.T) (argsFile string) {
+	t.Helper()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("fake pdftoppm requires a POSIX shell")
+	}
+
+	dir := t.TempDir()
+	argsFile = filepath.Join(dir, "args")
+
+	script := "#!/bin/sh\n" +
+		"if [ \"$1\" = -v ]; then echo 'pdftoppm version 24.02.0' >&2; exit 0; fi\n" +
+		"echo \"$@\" > '" + argsFile + "'\n" +
+		"ext=jpg\n" +
+		"for a in \"$@\"; do [ \"$a\" = -png ] && ext=png; root=\"$a\"; done\n" +
+		"printf fake-image > \"$root.$ext\"\n"
+
+	if err := os.WriteFile(filepath.Join(dir, "pdftoppm"), []byte(script), 0755); err != nil {
+		t.Fatalf("Failed to write fake pdftoppm: %v", err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return argsFile
+}
+
+func TestNewPdftoppmRenderer_ValidConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		config config.ImageConfig
+	}{
+		{"empty config gets defaults", config.ImageConfig{}},
+		{"valid JPEG config", config.ImageConfig{Format: "jpg", Quality: 85, DPI: 200}},
+		{"supported options", config.ImageConfig{Options: map[string]any{"antialias": false, "cropbox": true}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			renderer, err := image.NewPdftoppmRenderer(tt.config)
+			if err != nil {
+				t.Fatalf("NewPdftoppmRenderer failed: %v", err)
+			}
+			if renderer == nil {
+				t.Error("expected non-nil renderer")
+			}
+		})
+	}
+}
+
+func TestNewPdftoppmRenderer_InvalidConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		config config.ImageConfig
+		errMsg string
+	}{
+		{"unsupported format", config.ImageConfig{Format: "webp"}, "unsupported image format"},
+		{"JPEG without quality", config.ImageConfig{Format: "jpg"}, "quality"},
+		{"AVIF", config.ImageConfig{Format: "avif", Quality: 60}, "unsupported image format"},
+		{"lossless", config.ImageConfig{Lossless: true}, "lossless encoding is only supported for webp"},
+		{"ImageMagick filter", config.ImageConfig{Options: map[string]any{"brightness": 120}}, "brightness is not supported by pdftoppm"},
+		{"ImageMagick background", config.ImageConfig{Options: map[string]any{"background": "white"}}, "background is not supported"},
+		{"ImageMagick color mode", config.ImageConfig{Options: map[string]any{"color_mode": "grayscale"}}, "color_mode is not supported by pdftoppm"},
+		{"non-boolean option", config.ImageConfig{Options: map[string]any{"antialias": "yes"}}, "antialias must be a boolean"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := image.NewPdftoppmRenderer(tt.config)
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("expected error containing %q, got %v", tt.errMsg, err)
+			}
+		})
+	}
+}
+
+func TestPdftoppmRenderer_Parameters(t *testing.T) {
+	renderer, err := image.NewPdftoppmRenderer(config.ImageConfig{Options: map[string]any{"cropbox": true}})
+	if err != nil {
+		t.Fatalf("NewPdftoppmRenderer failed: %v", err)
+	}
+
+	want := []string{"antialias=true", "cropbox=true", "renderer=pdftoppm"}
+	if got := renderer.Parameters(); strings.Join(got, "&") != strings.Join(want, "&") {
+		t.Errorf("Parameters() = %v, want %v", got, want)
+	}
+	if renderer.FileExtension() != "png" {
+		t.Errorf("expected extension 'png', got %q", renderer.FileExtension())
+	}
+	if renderer.Settings().DPI != 300 {
+		t.Errorf("expected default DPI 300, got %d", renderer.Settings().DPI)
+	}
+}
+
+func TestPdftoppmRenderer_Render(t *testing.T) {
+	tests := []struct {
+		name   string
+		config config.ImageConfig
+		output string
+		args   string
+	}{
+		{
+			name:   "PNG",
+			config: config.ImageConfig{DPI: 150},
+			output: "page.png",
+			args:   "-f 3 -l 3 -singlefile -r 150 -png in.pdf ",
+		},
+		{
+			name:   "JPEG with options",
+			config: config.ImageConfig{Format: "jpg", Quality: 80, Options: map[string]any{"antialias": false, "cropbox": true}},
+			output: "page.jpg",
+			args:   "-f 3 -l 3 -singlefile -r 300 -jpeg -jpegopt quality=80 -aa no -aaVector no -cropbox in.pdf ",
+		},
+		{
+			name:   "JPEG extension renamed",
+			config: config.ImageConfig{Format: "jpeg", Quality: 80},
+			output: "page.jpeg",
+			args:   "-f 3 -l 3 -singlefile -r 300 -jpeg -jpegopt quality=80 in.pdf ",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			argsFile := installFakePdftoppm(t)
+
+			renderer, err := image.NewPdftoppmRenderer(tt.config)
+			if err != nil {
+				t.Fatalf("NewPdftoppmRenderer failed: %v", err)
+			}
+
+			output := filepath.Join(t.TempDir(), tt.output)
+			if err := renderer.Render("in.pdf", 3, output); err != nil {
+				t.Fatalf("Render failed: %v", err)
+			}
+
+			data, err := os.ReadFile(output)
+			if err != nil {
+				t.Fatalf("expected output at %s: %v", output, err)
+			}
+			if string(data) != "fake-image" {
+				t.Errorf("unexpected output %q", data)
+			}
+
+			args, err := os.ReadFile(argsFile)
+			if err != nil {
+				t.Fatalf("Failed to read args: %v", err)
+			}
+			if !strings.HasPrefix(string(args), tt.args) {
+				t.Errorf("unexpected arguments:\n%s\nwant prefix:\n%s", args, tt.args)
+			}
+		})
+	}
+}
+
+func TestPdftoppmRenderer_MissingBinary(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+
+	renderer, err := image.NewPdftoppmRenderer(config.ImageConfig{})
+	if err != nil {
+		t.Fatalf("NewPdftoppmRenderer failed: %v", err)
+	}
+
+	err = renderer.Render("in.pdf", 1, filepath.Join(t.TempDir(), "page.png"))
+	if err == nil {
+		t.Fatal("expected error when pdftoppm is missing")
+	}
+	if !strings.Contains(err.Error(), "pdftoppm not found") {
+		t.Errorf("expected clear missing-binary error, got %v", err)
+	}
+}
+
+func TestPdftoppmRenderer_CheckVersion(t *testing.T) {
+	installFakePdftoppm(t)
+
+	renderer, err := image.NewPdftoppmRenderer(config.ImageConfig{})
+	if err != nil {
+		t.Fatalf("NewPdftoppmRenderer failed: %v", err)
+	}
+
+	checkable, ok := renderer.(image.CheckableRenderer)
+	if !ok {
+		t.Fatal("expected pdftoppm renderer to implement CheckableRenderer")
+	}
+	if err := checkable.Check(); err != nil {
+		t.Errorf("Check failed: %v", err)
+	}
+
+	version, err := checkable.Version()
+	if err != nil {
+		t.Fatalf("Version failed: %v", err)
+	}
+	if version != "24.02.0" {
+		t.Errorf("expected version 24.02.0, got %q", version)
+	}
+}
+
+func TestPdftoppmRenderer_IncludeVersion(t *testing.T) {
+	installFakePdftoppm(t)
+
+	renderer, err := image.NewPdftoppmRenderer(config.ImageConfig{Options: map[string]any{"include_version": true}})
+	if err != nil {
+		t.Fatalf("NewPdftoppmRenderer failed: %v", err)
+	}
+
+	want := []string{"antialias=true", "cropbox=false", "renderer=pdftoppm", "version=24.02.0"}
+	if got := renderer.Parameters(); strings.Join(got, "&") != strings.Join(want, "&") {
+		t.Errorf("Parameters() = %v, want %v", got, want)
+	}
+}
+
+func TestPdftoppmRenderer_ConfiguredBinary(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake pdftoppm requires a POSIX shell")
+	}
+
+	binary := filepath.Join(t.TempDir(), "pdftoppm-custom")
+	script := "#!/bin/sh\necho 'pdftoppm version 0.86.1' >&2\nexit 99\n"
+	if err := os.WriteFile(binary, []byte(script), 0755); err != nil {
+		t.Fatalf("Failed to write fake pdftoppm: %v", err)
+	}
+	t.Setenv("PATH", t.TempDir())
+
+	renderer, err := image.NewPdftoppmRenderer(config.ImageConfig{Options: map[string]any{"binary": binary}})
+	if err != nil {
+		t.Fatalf("NewPdftoppmRenderer failed: %v", err)
+	}
+
+	version, err := renderer.(image.CheckableRenderer).Version()
+	if err != nil {
+		t.Fatalf("Version failed: %v", err)
+	}
+	if version != "0.86.1" {
+		t.Errorf("expected version parsed despite non-zero exit, got %q", version)
+	}
+}
+
+func TestPdftoppmRenderer_CheckMissingBinary(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+
+	renderer, err := image.NewPdftoppmRenderer(config.ImageConfig{})
+	if err != nil {
+		t.Fatalf("NewPdftoppmRenderer failed: %v", err)
+	}
+
+	err = renderer.(image.CheckableRenderer).Check()
+	if err == nil || !strings.Contains(err.Error(), "pdftoppm not found") {
+		t.Errorf("expected missing-binary error from Check, got %v", err)
+	}
+
+	if _, err := image.NewPdftoppmRenderer(config.ImageConfig{Options: map[string]any{"include_version": true}}); err == nil {
+		t.Error("expected include_version to fail without pdftoppm")
+	}
+}
@@ -0,0 +1,175 @@
+package image_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/JaimeStill/document-context/pkg/config"
+	"github.com/JaimeStill/document-context/pkg/image"
+)
+
+// mockRenderer implements image.Renderer for testing
+type mockRenderer struct {
+	cfg config.ImageConfig
+}
+
+func (m *mockRenderer) Render(inputPath string, pageNum int, outputPath string) error {
+	return nil
+}
+
+func (m *mockRenderer) FileExtension() string {
+	return m.cfg.Format
+}
+
+func (m *mockRenderer) Settings() config.ImageConfig {
+	return m.cfg
+}
+
+func (m *mockRenderer) Parameters() []string {
+	return nil
+}
+
+func mockFactory(cfg config.ImageConfig) (image.Renderer, error) {
+	return &mockRenderer{cfg: cfg}, nil
+}
+
+func TestRegister_BuiltinRenderers(t *testing.T) {
+	renderers := image.ListRenderers()
+
+	for _, want := range []string{"imagemagick", "pdftoppm"} {
+		found := false
+		for _, name := range renderers {
+			if name == want {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("expected %s to be registered, got %v", want, renderers)
+		}
+	}
+}
+
+func TestRegister_EmptyName_Panics(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic for empty name")
+		}
+	}()
+
+	image.Register("", mockFactory)
+}
+
+func TestRegister_NilFactory_Panics(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic for nil factory")
+		}
+	}()
+
+	image.Register("nil-factory", nil)
+}
+
+func TestCreate_RegisteredRenderer(t *testing.T) {
+	image.Register("mock-renderer", mockFactory)
+
+	renderer, err := image.Create(config.ImageConfig{Renderer: "mock-renderer", Format: "tiff"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	mock, ok := renderer.(*mockRenderer)
+	if !ok {
+		t.Fatalf("expected *mockRenderer, got %T", renderer)
+	}
+	if mock.cfg.Format != "tiff" {
+		t.Errorf("expected factory to receive config, got %+v", mock.cfg)
+	}
+}
+
+func TestCreate_BuiltinRenderers(t *testing.T) {
+	tests := []struct {
+		name     string
+		renderer string
+		param    string
+	}{
+		{"empty name selects imagemagick", "", "background=white"},
+		{"imagemagick", "imagemagick", "background=white"},
+		{"pdftoppm", "pdftoppm", "renderer=pdftoppm"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			renderer, err := image.Create(config.ImageConfig{Renderer: tt.renderer})
+			if err != nil {
+				t.Fatalf("Create failed: %v", err)
+			}
+
+			found := false
+			for _, p := range renderer.Parameters() {
+				if p == tt.param {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("expected parameter %q, got %v", tt.param, renderer.Parameters())
+			}
+		})
+	}
+}
+
+func TestCreate_FactoryError(t *testing.T) {
+	_, err := image.Create(config.ImageConfig{Renderer: "pdftoppm", Format: "webp"})
+	if err == nil {
+		t.Fatal("expected factory validation error")
+	}
+}
+
+func TestCreate_UnknownRenderer(t *testing.T) {
+	_, err := image.Create(config.ImageConfig{Renderer: "nonexistent-renderer"})
+	if err == nil {
+		t.Fatal("expected error for unknown renderer")
+	}
+
+	expectedMsg := "unknown renderer: nonexistent-renderer"
+	if err.Error() != expectedMsg {
+		t.Errorf("expected error %q, got %q", expectedMsg, err.Error())
+	}
+}
+
+func TestListRenderers_Sorted(t *testing.T) {
+	image.Register("zebra-renderer", mockFactory)
+	image.Register("alpha-renderer", mockFactory)
+
+	renderers := image.ListRenderers()
+
+	for i := 1; i < len(renderers); i++ {
+		if renderers[i-1] >= renderers[i] {
+			t.Errorf("renderers not sorted: %q >= %q", renderers[i-1], renderers[i])
+		}
+	}
+}
+
+func TestCreate_Concurrent(t *testing.T) {
+	image.Register("concurrent-create", mockFactory)
+
+	var wg sync.WaitGroup
+	errors := make(chan error, 100)
+
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := image.Create(config.ImageConfig{Renderer: "concurrent-create"}); err != nil {
+				errors <- err
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(errors)
+
+	for err := range errors {
+		t.Errorf("concurrent create failed: %v", err)
+	}
+}
@@ -0,0 +1,438 @@
+package ocr_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/JaimeStill/document-context/pkg/cache"
+	"github.com/JaimeStill/document-context/pkg/config"
+	"github.com/JaimeStill/document-context/pkg/image"
+	"github.com/JaimeStill/document-context/pkg/ocr"
+)
+
+const sampleTSV = "level\tpage_num\tblock_num\tpar_num\tline_num\tword_num\tleft\ttop\twidth\theight\tconf\ttext\n" +
+	"1\t1\t0\t0\t0\t0\t0\t0\t1275\t1650\t-1\t\n" +
+	"2\t1\t1\t0\t0\t0\t100\t120\t400\t80\t-1\t\n" +
+	"3\t1\t1\t1\t0\t0\t100\t120\t400\t80\t-1\t\n" +
+	"4\t1\t1\t1\t1\t0\t100\t120\t400\t30\t-1\t\n" +
+	"5\t1\t1\t1\t1\t1\t100\t120\t120\t30\t96.5\tQuarterly\n" +
+	"5\t1\t1\t1\t1\t2\t230\t120\t90\t30\t93.5\treport\n" +
+	"4\t1\t1\t1\t2\t0\t100\t160\t200\t30\t-1\t\n" +
+	"5\t1\t1\t1\t2\t1\t100\t160\t200\t30\t90\tsummary\n" +
+	"5\t1\t1\t1\t2\t2\t310\t160\t10\t30\t95\t \n" +
+	"2\t1\t2\t0\t0\t0\t100\t400\t300\t40\t-1\t\n" +
+	"5\t1\t2\t1\t1\t1\t100\t400\t300\t40\t88\tRevenue\n"
+
+// writeFakeTesseract creates an executable that records its arguments and
+// prints output to stdout, standing in for the tesseract binary.
+func writeFakeTesseract(t *testing.T, output string, exitCode int) (binary, argsFile string) {
+	t.Helper()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("fake tesseract requires a POSIX shell")
+	}
+
+	dir := t.TempDir()
+	argsFile = filepath.Join(dir, "args")
+	outputFile := filepath.Join(dir, "output.tsv")
+
+	if err := os.WriteFile(outputFile, []byte(output), 0644); err != nil {
+		t.Fatalf("Failed to write fake output: %v", err)
+	}
+
+	script := "#!/bin/sh\n" +
+		"echo \"$@\" >> '" + argsFile + "'\n" +
+		"cat '" + outputFile + "'\n" +
+		"echo 'fake diagnostics' >&2\n" +
+		"exit " + strconv.Itoa(exitCode) + "\n"
+
+	binary = filepath.Join(dir, "tesseract")
+	if err := os.WriteFile(binary, []byte(script), 0755); err != nil {
+		t.Fatalf("Failed to write fake tesseract: %v", err)
+	}
+
+	return binary, argsFile
+}
+
+func readArgs(t *testing.T, argsFile string) []string {
+	t.Helper()
+
+	data, err := os.ReadFile(argsFile)
+	if err != nil {
+		return nil
+	}
+	return strings.Split(strings.TrimSpace(string(data)), "\n")
+}
+
+func TestNewTesseractEngine_Defaults(t *testing.T) {
+	engine, err := ocr.NewTesseractEngine(config.OCRConfig{})
+	if err != nil {
+		t.Fatalf("NewTesseractEngine failed: %v", err)
+	}
+
+	settings := engine.Settings()
+	if settings.Binary != "tesseract" {
+		t.Errorf("expected Binary 'tesseract', got %q", settings.Binary)
+	}
+
+	want := []string{"engine=tesseract", "languages=eng", "psm=3"}
+	if got := engine.Parameters(); strings.Join(got, "&") != strings.Join(want, "&") {
+		t.Errorf("Parameters() = %v, want %v", got, want)
+	}
+}
+
+func TestNewTesseractEngine_Validation(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  config.OCRConfig
+	}{
+		{name: "language with spaces", cfg: config.OCRConfig{Languages: []string{"eng deu"}}},
+		{name: "language with shell characters", cfg: config.OCRConfig{Languages: []string{"eng;rm"}}},
+		{name: "page segmentation mode too high", cfg: config.OCRConfig{PageSegMode: 14}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ocr.NewTesseractEngine(tt.cfg); err == nil {
+				t.Error("expected validation error")
+			}
+		})
+	}
+}
+
+func TestNewTesseractEngine_PageSegModeBounds(t *testing.T) {
+	tests := []struct {
+		name string
+		mode int
+		want string
+	}{
+		{name: "unset selects default", mode: 0, want: "psm=3"},
+		{name: "lowest mode", mode: 1, want: "psm=1"},
+		{name: "highest mode", mode: 13, want: "psm=13"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine, err := ocr.NewTesseractEngine(config.OCRConfig{PageSegMode: tt.mode})
+			if err != nil {
+				t.Fatalf("NewTesseractEngine failed: %v", err)
+			}
+			if params := engine.Parameters(); !slices.Contains(params, tt.want) {
+				t.Errorf("expected %s in parameters, got %v", tt.want, params)
+			}
+		})
+	}
+}
+
+func TestTesseractEngine_Recognize(t *testing.T) {
+	binary, argsFile := writeFakeTesseract(t, sampleTSV, 0)
+
+	engine, err := ocr.NewTesseractEngine(config.OCRConfig{
+		Binary:      binary,
+		Languages:   []string{"eng", "deu"},
+		PageSegMode: 6,
+	})
+	if err != nil {
+		t.Fatalf("NewTesseractEngine failed: %v", err)
+	}
+
+	result, err := engine.Recognize([]byte("image"))
+	if err != nil {
+		t.Fatalf("Recognize failed: %v", err)
+	}
+
+	wantText := "Quarterly report\nsummary\n\nRevenue"
+	if result.Text != wantText {
+		t.Errorf("Text = %q, want %q", result.Text, wantText)
+	}
+
+	if len(result.Words) != 4 {
+		t.Fatalf("expected 4 words, got %d", len(result.Words))
+	}
+
+	first := result.Words[0]
+	if first.Text != "Quarterly" || first.Confidence != 96.5 {
+		t.Errorf("unexpected first word: %+v", first)
+	}
+	if first.Box != (ocr.Box{Left: 100, Top: 120, Width: 120, Height: 30}) {
+		t.Errorf("unexpected first word box: %+v", first.Box)
+	}
+	if first.Block != 1 || first.Paragraph != 1 || first.Line != 1 {
+		t.Errorf("unexpected first word layout: %+v", first)
+	}
+
+	if result.Confidence != (96.5+93.5+90+88)/4 {
+		t.Errorf("Confidence = %f, want mean word confidence", result.Confidence)
+	}
+
+	args := readArgs(t, argsFile)
+	if len(args) != 1 {
+		t.Fatalf("expected one invocation, got %d", len(args))
+	}
+	if !strings.HasSuffix(args[0], "stdout -l eng+deu --psm 6 tsv") {
+		t.Errorf("unexpected arguments: %q", args[0])
+	}
+}
+
+func TestTesseractEngine_Recognize_Empty(t *testing.T) {
+	binary, _ := writeFakeTesseract(t, "level\tpage_num\tblock_num\tpar_num\tline_num\tword_num\tleft\ttop\twidth\theight\tconf\ttext\n", 0)
+
+	engine, err := ocr.NewTesseractEngine(config.OCRConfig{Binary: binary})
+	if err != nil {
+		t.Fatalf("NewTesseractEngine failed: %v", err)
+	}
+
+	result, err := engine.Recognize([]byte("image"))
+	if err != nil {
+		t.Fatalf("Recognize failed: %v", err)
+	}
+	if result.Text != "" || len(result.Words) != 0 || result.Confidence != 0 {
+		t.Errorf("expected empty result, got %+v", result)
+	}
+}
+
+func TestTesseractEngine_Recognize_Failure(t *testing.T) {
+	binary, _ := writeFakeTesseract(t, "", 1)
+
+	engine, err := ocr.NewTesseractEngine(config.OCRConfig{Binary: binary})
+	if err != nil {
+		t.Fatalf("NewTesseractEngine failed: %v", err)
+	}
+
+	_, err = engine.Recognize([]byte("image"))
+	if err == nil {
+		t.Fatal("expected error from failing binary")
+	}
+	if !strings.Contains(err.Error(), "fake diagnostics") {
+		t.Errorf("expected stderr in error, got %v", err)
+	}
+}
+
+func TestTesseractEngine_Recognize_MissingBinary(t *testing.T) {
+	engine, err := ocr.NewTesseractEngine(config.OCRConfig{
+		Binary: filepath.Join(t.TempDir(), "missing-tesseract"),
+	})
+	if err != nil {
+		t.Fatalf("NewTesseractEngine failed: %v", err)
+	}
+
+	if _, err := engine.Recognize([]byte("image")); err == nil {
+		t.Error("expected error for missing binary")
+	}
+}
+
+func TestTesseractEngine_Recognize_Malformed(t *testing.T) {
+	binary, _ := writeFakeTesseract(t, "5\t1\tnot-a-number\n", 0)
+
+	engine, err := ocr.NewTesseractEngine(config.OCRConfig{Binary: binary})
+	if err != nil {
+		t.Fatalf("NewTesseractEngine failed: %v", err)
+	}
+
+	if _, err := engine.Recognize([]byte("image")); err == nil {
+		t.Error("expected error for malformed output")
+	}
+}
+
+// stubPage implements document.Page with a fixed image and an optional image
+// cache key, counting renders.
+type stubPage struct {
+	number   int
+	image    []byte
+	imageKey string
+	renders  int
+}
+
+func (p *stubPage) Number() int { return p.number }
+
+func (p *stubPage) ToImage(renderer image.Renderer, c cache.Cache) ([]byte, error) {
+	p.renders++
+	return p.image, nil
+}
+
+// keyedPage adds an image cache key to stubPage.
+type keyedPage struct {
+	*stubPage
+}
+
+func (p keyedPage) ImageCacheKey(renderer image.Renderer) (string, error) {
+	return p.imageKey, nil
+}
+
+// mockCache implements cache.Cache in memory.
+type mockCache struct {
+	mu      sync.Mutex
+	entries map[string]*cache.CacheEntry
+	getErr  error
+}
+
+func newMockCache() *mockCache {
+	return &mockCache{entries: make(map[string]*cache.CacheEntry)}
+}
+
+func (m *mockCache) Get(key string) (*cache.CacheEntry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.getErr != nil {
+		return nil, m.getErr
+	}
+	entry, ok := m.entries[key]
+	if !ok {
+		return nil, cache.ErrCacheEntryNotFound
+	}
+	return entry, nil
+}
+
+func (m *mockCache) Set(entry *cache.CacheEntry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.entries[entry.Key] = entry
+	return nil
+}
+
+func (m *mockCache) Invalidate(key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.entries, key)
+	return nil
+}
+
+func (m *mockCache) Clear() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.entries = make(map[string]*cache.CacheEntry)
+	return nil
+}
+
+func newFakeEngine(t *testing.T, cfg config.OCRConfig) (ocr.OCREngine, string) {
+	t.Helper()
+
+	binary, argsFile := writeFakeTesseract(t, sampleTSV, 0)
+	cfg.Binary = binary
+
+	engine, err := ocr.NewTesseractEngine(cfg)
+	if err != nil {
+		t.Fatalf("NewTesseractEngine failed: %v", err)
+	}
+	return engine, argsFile
+}
+
+func TestRecognizePage_NoCache(t *testing.T) {
+	engine, argsFile := newFakeEngine(t, config.OCRConfig{})
+	page := &stubPage{number: 1, image: []byte("image")}
+
+	for range 2 {
+		result, err := ocr.RecognizePage(page, nil, engine, nil)
+		if err != nil {
+			t.Fatalf("RecognizePage failed: %v", err)
+		}
+		if result.Text == "" {
+			t.Error("expected recognized text")
+		}
+	}
+
+	if n := len(readArgs(t, argsFile)); n != 2 {
+		t.Errorf("expected 2 recognitions without cache, got %d", n)
+	}
+}
+
+func TestRecognizePage_CachedByImageKey(t *testing.T) {
+	engine, argsFile := newFakeEngine(t, config.OCRConfig{})
+	page := keyedPage{&stubPage{number: 2, image: []byte("image"), imageKey: "image-key"}}
+	c := newMockCache()
+
+	first, err := ocr.RecognizePage(page, nil, engine, c)
+	if err != nil {
+		t.Fatalf("RecognizePage failed: %v", err)
+	}
+
+	second, err := ocr.RecognizePage(page, nil, engine, c)
+	if err != nil {
+		t.Fatalf("RecognizePage failed: %v", err)
+	}
+
+	if first.Text != second.Text || len(second.Words) != len(first.Words) {
+		t.Error("cached result differs from recognized result")
+	}
+	if n := len(readArgs(t, argsFile)); n != 1 {
+		t.Errorf("expected 1 recognition, got %d", n)
+	}
+	if page.renders != 1 {
+		t.Errorf("expected cache hit to skip rendering, got %d renders", page.renders)
+	}
+
+	if len(c.entries) != 1 {
+		t.Fatalf("expected 1 cache entry, got %d", len(c.entries))
+	}
+	for _, entry := range c.entries {
+		if entry.Filename != "page-2.ocr.json" {
+			t.Errorf("Filename = %q, want %q", entry.Filename, "page-2.ocr.json")
+		}
+	}
+}
+
+func TestRecognizePage_KeyIncludesSettings(t *testing.T) {
+	c := newMockCache()
+	page := keyedPage{&stubPage{number: 1, image: []byte("image"), imageKey: "image-key"}}
+
+	eng, _ := newFakeEngine(t, config.OCRConfig{Languages: []string{"eng"}})
+	if _, err := ocr.RecognizePage(page, nil, eng, c); err != nil {
+		t.Fatalf("RecognizePage failed: %v", err)
+	}
+
+	deu, _ := newFakeEngine(t, config.OCRConfig{Languages: []string{"deu"}})
+	if _, err := ocr.RecognizePage(page, nil, deu, c); err != nil {
+		t.Fatalf("RecognizePage failed: %v", err)
+	}
+
+	other := keyedPage{&stubPage{number: 1, image: []byte("image"), imageKey: "other-image-key"}}
+	if _, err := ocr.RecognizePage(other, nil, eng, c); err != nil {
+		t.Fatalf("RecognizePage failed: %v", err)
+	}
+
+	if len(c.entries) != 3 {
+		t.Errorf("expected 3 distinct cache entries, got %d", len(c.entries))
+	}
+}
+
+func TestRecognizePage_CachedByImageContent(t *testing.T) {
+	engine, argsFile := newFakeEngine(t, config.OCRConfig{})
+	c := newMockCache()
+
+	a := &stubPage{number: 1, image: []byte("same image")}
+	b := &stubPage{number: 5, image: []byte("same image")}
+
+	if _, err := ocr.RecognizePage(a, nil, engine, c); err != nil {
+		t.Fatalf("RecognizePage failed: %v", err)
+	}
+	if _, err := ocr.RecognizePage(b, nil, engine, c); err != nil {
+		t.Fatalf("RecognizePage failed: %v", err)
+	}
+
+	if n := len(readArgs(t, argsFile)); n != 1 {
+		t.Errorf("expected identical images to share a result, got %d recognitions", n)
+	}
+}
+
+func TestRecognizePage_CacheError(t *testing.T) {
+	engine, _ := newFakeEngine(t, config.OCRConfig{})
+	page := keyedPage{&stubPage{number: 1, image: []byte("image"), imageKey: "image-key"}}
+
+	c := newMockCache()
+	c.getErr = errors.New("storage unavailable")
+
+	if _, err := ocr.RecognizePage(page, nil, engine, c); !errors.Is(err, c.getErr) {
+		t.Errorf("expected cache error to propagate, got %v", err)
+	}
+}